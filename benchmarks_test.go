@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/encoding/wkb"
 )
 
 // ==================== Geometry Generators ====================
@@ -655,36 +656,315 @@ func BenchmarkHelper_Cross2D(b *testing.B) {
 	}
 }
 
-func BenchmarkHelper_BoundingBoxOverlap(b *testing.B) {
+func BenchmarkHelper_BoundsOverlap(b *testing.B) {
+	ba, bb := benchSmallPoly.Bound(), benchPolyOverlapping.Bound()
 	for i := 0; i < b.N; i++ {
-		boundingBoxOverlap(benchSmallPoly, benchPolyOverlapping)
+		boundsOverlap(ba, bb)
 	}
 }
 
-func BenchmarkHelper_PointOnRingBoundary_Small(b *testing.B) {
+func BenchmarkHelper_ProbeRing_OnEdge_Small(b *testing.B) {
 	ring := benchSmallPoly[0]
 	for i := 0; i < b.N; i++ {
-		pointOnRingBoundary(benchPointOnEdge, ring)
+		inside := false
+		probeRing(benchPointOnEdge, ring, &inside)
 	}
 }
 
-func BenchmarkHelper_PointOnRingBoundary_Large(b *testing.B) {
+func BenchmarkHelper_ProbeRing_OnEdge_Large(b *testing.B) {
 	ring := benchLargePoly[0]
 	for i := 0; i < b.N; i++ {
-		pointOnRingBoundary(benchPointOnEdge, ring)
+		inside := false
+		probeRing(benchPointOnEdge, ring, &inside)
 	}
 }
 
-func BenchmarkHelper_PointInRingInterior_Small(b *testing.B) {
+func BenchmarkHelper_ProbeRing_Inside_Small(b *testing.B) {
 	ring := benchSmallPoly[0]
 	for i := 0; i < b.N; i++ {
-		pointInRingInterior(benchPointInside, ring)
+		inside := false
+		probeRing(benchPointInside, ring, &inside)
 	}
 }
 
-func BenchmarkHelper_PointInRingInterior_Large(b *testing.B) {
+func BenchmarkHelper_ProbeRing_Inside_Large(b *testing.B) {
 	ring := benchLargePoly[0]
 	for i := 0; i < b.N; i++ {
-		pointInRingInterior(benchPointInside, ring)
+		inside := false
+		probeRing(benchPointInside, ring, &inside)
+	}
+}
+
+// ==================== Typed Entry Point Benchmarks ====================
+// These report allocations and should all show 0 allocs/op
+
+func BenchmarkTyped_PointInSmallPolygon(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		PointInPolygon(benchPointInside, benchSmallPoly)
+	}
+}
+
+func BenchmarkTyped_PointInLargePolygon(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		PointInPolygon(benchPointInside, benchLargePoly)
+	}
+}
+
+func BenchmarkTyped_PointInMultiPolygon(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		PointInMultiPolygon(benchPointInside, benchMultiPoly)
+	}
+}
+
+func BenchmarkTyped_PointInBound(b *testing.B) {
+	bound := orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{100, 100}}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		PointInBound(benchPointInside, bound)
+	}
+}
+
+func BenchmarkTyped_PolygonCoversPointOnEdge(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		PolygonCoversPoint(benchLargePoly, benchPointOnEdge)
+	}
+}
+
+func BenchmarkTyped_PolygonIntersectsPolygon_Overlapping(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		PolygonIntersectsPolygon(benchLargePoly, benchPolyOverlapping)
+	}
+}
+
+func BenchmarkTyped_PolygonIntersectsPolygon_Disjoint(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		PolygonIntersectsPolygon(benchLargePoly, benchPolyDisjoint)
+	}
+}
+
+func BenchmarkTyped_LineStringIntersectsPolygon(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		LineStringIntersectsPolygon(benchLineCrossing, benchLargePoly)
+	}
+}
+
+func BenchmarkTyped_LineStringIntersectsLineString(b *testing.B) {
+	other := generateLineString(0, 100, 100, 0, 10)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		LineStringIntersectsLineString(benchLineCrossing, other)
+	}
+}
+
+func BenchmarkTyped_BoundIntersectsLineString(b *testing.B) {
+	bound := orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{100, 100}}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		BoundIntersectsLineString(bound, benchLineCrossing)
+	}
+}
+
+func BenchmarkTyped_BoundIntersectsPolygon(b *testing.B) {
+	bound := orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{100, 100}}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		BoundIntersectsPolygon(bound, benchSmallPoly)
+	}
+}
+
+func BenchmarkTyped_BoundIntersectsBound(b *testing.B) {
+	bound := orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{100, 100}}
+	other := orb.Bound{Min: orb.Point{50, 50}, Max: orb.Point{150, 150}}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		BoundIntersectsBound(bound, other)
+	}
+}
+
+// ==================== Circle Benchmarks ====================
+
+var benchCircle = Circle{Center: orb.Point{50, 50}, Radius: 40}
+
+func BenchmarkCircle_PointWithin(b *testing.B) {
+	p := orb.Point{60, 60}
+	for i := 0; i < b.N; i++ {
+		Within(p, benchCircle)
+	}
+}
+
+// BenchmarkCircle_PointWithin64gon is the polygon approximation Circle replaces
+func BenchmarkCircle_PointWithin64gon(b *testing.B) {
+	p := orb.Point{60, 60}
+	poly := generateCircularPolygon(50, 50, 40, 64)
+	for i := 0; i < b.N; i++ {
+		Within(p, poly)
+	}
+}
+
+func BenchmarkCircle_CircleWithinCircle(b *testing.B) {
+	inner := Circle{Center: orb.Point{55, 50}, Radius: 10}
+	for i := 0; i < b.N; i++ {
+		Within(inner, benchCircle)
+	}
+}
+
+func BenchmarkCircle_PolygonIntersects(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Intersects(benchSmallPoly, benchCircle)
+	}
+}
+
+func BenchmarkTyped_PointInCircle(b *testing.B) {
+	p := orb.Point{60, 60}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		PointInCircle(p, benchCircle)
+	}
+}
+
+// ==================== Tile Cover Benchmarks ====================
+
+func BenchmarkTileCover_Polygon(b *testing.B) {
+	poly := generateCircularPolygon(10, 45, 8, 64)
+	for i := 0; i < b.N; i++ {
+		TileCover(poly, 10)
+	}
+}
+
+// ==================== WKB Benchmarks ====================
+
+var (
+	benchWKBParcel = wkb.MustMarshal(generateCircularPolygon(10, 10, 5, 256))
+	benchWKBFar    = wkb.MustMarshal(generateCircularPolygon(100, 100, 5, 256))
+	benchWKBNear   = wkb.MustMarshal(generateCircularPolygon(12, 12, 5, 256))
+)
+
+// The envelopes are apart, so only the coordinates are scanned
+func BenchmarkWKB_IntersectsDisjoint(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		IntersectsWKB(benchWKBParcel, benchWKBFar)
+	}
+}
+
+func BenchmarkWKB_DecodeIntersectsDisjoint(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		pa, _ := wkb.Unmarshal(benchWKBParcel)
+		pb, _ := wkb.Unmarshal(benchWKBFar)
+		Intersects(pa, pb)
+	}
+}
+
+func BenchmarkWKB_IntersectsOverlapping(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		IntersectsWKB(benchWKBParcel, benchWKBNear)
+	}
+}
+
+// ==================== Evaluate Benchmarks ====================
+
+// benchClassifyPairs are classified with Intersects, Touches, Within and Overlaps
+var benchClassifyPairs = []struct {
+	name string
+	a, b orb.Geometry
+}{
+	{"PointPolygon", orb.Point{1, 1}, generateCircularPolygon(0, 0, 10, 64)},
+	{"LinePolygon", generateLineString(-20, 0, 20, 0, 32), generateCircularPolygon(0, 0, 10, 64)},
+	{"PolygonOverlapping", generateCircularPolygon(0, 0, 10, 64), generateCircularPolygon(5, 0, 10, 64)},
+	{"PolygonInside", generateCircularPolygon(0, 0, 2, 64), generateCircularPolygon(0, 0, 10, 64)},
+}
+
+func BenchmarkEvaluate_Classify(b *testing.B) {
+	set := PredIntersects | PredTouches | PredWithin | PredOverlaps
+	for _, p := range benchClassifyPairs {
+		b.Run(p.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Evaluate(p.a, p.b, set)
+			}
+		})
+	}
+}
+
+func BenchmarkEvaluate_ClassifySeparate(b *testing.B) {
+	for _, p := range benchClassifyPairs {
+		b.Run(p.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Intersects(p.a, p.b)
+				Touches(p.a, p.b)
+				Within(p.a, p.b)
+				Overlaps(p.a, p.b)
+			}
+		})
+	}
+}
+
+// ==================== Small Input Benchmarks ====================
+
+var (
+	benchSmallBound     = orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{100, 100}}
+	benchSmallMultiPoly = orb.MultiPolygon{benchSmallPoly, generateSquarePolygon(200, 200, 50)}
+	benchSmallRingA     = orb.Ring(generateCircularPolygon(0, 0, 10, 16)[0])
+	benchSmallRingB     = orb.Ring(generateCircularPolygon(5, 0, 10, 16)[0])
+)
+
+// benchSmallInputs are the small inputs Evaluate answers without building
+// the relate engine's geometries, with the typed predicate for the same
+// question where there is one
+var benchSmallInputs = []struct {
+	name  string
+	a, b  orb.Geometry
+	set   PredicateSet
+	typed func() bool
+}{
+	{"WithinPointBound", benchPointInside, benchSmallBound, PredWithin,
+		func() bool { return PointInBound(benchPointInside, benchSmallBound) }},
+	{"WithinPointSmallPoly", benchPointInside, benchSmallPoly, PredWithin,
+		func() bool { return PointInPolygon(benchPointInside, benchSmallPoly) }},
+	{"WithinPointMultiPoly", benchPointInside, benchSmallMultiPoly, PredWithin,
+		func() bool { return PointInMultiPolygon(benchPointInside, benchSmallMultiPoly) }},
+	{"IntersectsPolyBound", benchSmallPoly, benchSmallBound, PredIntersects,
+		func() bool { return BoundIntersectsPolygon(benchSmallBound, benchSmallPoly) }},
+	{"IntersectsRingRing", benchSmallRingA, benchSmallRingB, PredIntersects,
+		func() bool {
+			return PolygonIntersectsPolygon(orb.Polygon{benchSmallRingA}, orb.Polygon{benchSmallRingB})
+		}},
+	{"CrossesLineStringLineString", generateLineString(0, 0, 100, 100, 10), generateLineString(0, 100, 100, 0, 10), PredCrosses, nil},
+	{"IntersectsCollectionPoly", orb.Collection{orb.Point{150, 150}, generateLineString(120, 120, 140, 140, 4), benchSmallPoly}, generateSquarePolygon(120, 120, 40), PredIntersects, nil},
+}
+
+// BenchmarkSmallInputs compares Evaluate on small inputs with the typed
+// predicates and with the relate engine alone, which is what they cost
+// without the direct paths
+func BenchmarkSmallInputs(b *testing.B) {
+	for _, c := range benchSmallInputs {
+		b.Run(c.name+"/Evaluate", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Evaluate(c.a, c.b, c.set)
+			}
+		})
+		if c.typed != nil {
+			b.Run(c.name+"/Typed", func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					c.typed()
+				}
+			})
+		}
+		b.Run(c.name+"/Engine", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				na, nb := normalize(c.a), normalize(c.b)
+				ea, eb := Envelope(na), Envelope(nb)
+				dimA, dimB := normalizedDimension(na), normalizedDimension(nb)
+				relateGraph(na, nb, ea, eb, Mod2Rule, c.set, dimA, dimB).result(c.set, dimA, dimB)
+			}
+		})
 	}
 }
@@ -0,0 +1,468 @@
+package predicates
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/paulmach/orb"
+)
+
+// Dimension returns the topological dimension of a geometry as used by the
+// predicates: 0 for puntal, 1 for lineal and 2 for areal geometries.
+// orb.Ring, orb.Bound and Circle are treated as areas, unless they have
+// collapsed: a Bound of zero width or a polygon that encloses no area has
+// dimension 1, and a point-sized one or a Circle of zero radius dimension 0. Empty geometries keep the dimension of
+// their type. A Collection has the highest dimension of its
+// non-empty components, and -1 is returned for an empty Collection or an
+// unsupported type. Custom types give the dimension of what they stand for.
+func Dimension(g orb.Geometry) int {
+	switch c := g.(type) {
+	case orb.Point, orb.MultiPoint:
+		return 0
+	case orb.LineString, orb.MultiLineString, CircularString:
+		return 1
+	case orb.Ring, orb.Polygon, orb.MultiPolygon, orb.Bound, Circle:
+		switch collapsed := collapseAreas(g).(type) {
+		case orb.Ring, orb.Polygon, orb.MultiPolygon, orb.Bound, Circle:
+			return 2
+		default:
+			return Dimension(collapsed)
+		}
+	case orb.Collection:
+		// Collection dimension is the max of its non-empty components
+		maxDim := -1
+		for _, geom := range c {
+			if IsEmpty(geom) {
+				continue
+			}
+			d := Dimension(geom)
+			if d > maxDim {
+				maxDim = d
+			}
+		}
+		return maxDim
+	case Relater:
+		return c.Dimensions()
+	}
+	if adapted, ok := adaptCustom(g); ok {
+		return Dimension(adapted)
+	}
+	return -1
+}
+
+// normalizedDimension is Dimension for a normalized geometry, whose areas
+// have not collapsed
+func normalizedDimension(g orb.Geometry) int {
+	switch c := g.(type) {
+	case orb.Ring, orb.Polygon, orb.MultiPolygon, orb.Bound, Circle:
+		return 2
+	case orb.Collection:
+		maxDim := -1
+		for _, part := range c {
+			maxDim = max(maxDim, normalizedDimension(part))
+		}
+		return maxDim
+	}
+	return Dimension(g)
+}
+
+// IsEmpty reports whether a geometry contains no points. A Point is empty
+// when either coordinate is NaN, which is how WKB encodes POINT EMPTY.
+// Multi* and Collection values are empty when all of their components are,
+// so MULTIPOINT(EMPTY, EMPTY) and GEOMETRYCOLLECTION(POINT EMPTY) are empty.
+// Unsupported types are empty; use CheckGeometry to tell them apart.
+func IsEmpty(g orb.Geometry) bool {
+	switch geom := g.(type) {
+	case orb.Point:
+		return pointEmpty(geom)
+	case orb.MultiPoint:
+		for _, p := range geom {
+			if !pointEmpty(p) {
+				return false
+			}
+		}
+		return true
+	case orb.LineString:
+		return len(geom) == 0
+	case orb.MultiLineString:
+		for _, ls := range geom {
+			if len(ls) > 0 {
+				return false
+			}
+		}
+		return true
+	case orb.Ring:
+		return len(geom) == 0
+	case orb.Polygon:
+		return len(geom) == 0 || len(geom[0]) == 0
+	case orb.MultiPolygon:
+		for _, poly := range geom {
+			if len(poly) > 0 && len(poly[0]) > 0 {
+				return false
+			}
+		}
+		return true
+	case orb.Collection:
+		for _, part := range geom {
+			if !IsEmpty(part) {
+				return false
+			}
+		}
+		return true
+	case orb.Bound:
+		return geom.IsEmpty()
+	case Circle:
+		return IsEmpty(geom.Center) || !(geom.Radius >= 0)
+	case CircularString:
+		return len(geom.Points) == 0
+	case Relater:
+		return geom.Bound().IsEmpty()
+	}
+	if adapted, ok := adaptCustom(g); ok {
+		return IsEmpty(adapted)
+	}
+	return true
+}
+
+// pointEmpty checks if a point is POINT EMPTY, see IsEmpty
+func pointEmpty(p orb.Point) bool {
+	return math.IsNaN(p[0]) || math.IsNaN(p[1])
+}
+
+// removeEmpty drops the empty components of Multi* and Collection values, so
+// the predicates only see parts that contain points. Geometries without
+// empty components are returned as they are.
+func removeEmpty(g orb.Geometry) orb.Geometry {
+	switch geom := g.(type) {
+	case orb.MultiPoint:
+		for i, p := range geom {
+			if pointEmpty(p) {
+				kept := append(orb.MultiPoint{}, geom[:i]...)
+				for _, p := range geom[i+1:] {
+					if !pointEmpty(p) {
+						kept = append(kept, p)
+					}
+				}
+				return kept
+			}
+		}
+	case orb.MultiLineString:
+		for i, ls := range geom {
+			if len(ls) == 0 {
+				kept := append(orb.MultiLineString{}, geom[:i]...)
+				for _, ls := range geom[i+1:] {
+					if len(ls) > 0 {
+						kept = append(kept, ls)
+					}
+				}
+				return kept
+			}
+		}
+	case orb.MultiPolygon:
+		for i, poly := range geom {
+			if len(poly) == 0 || len(poly[0]) == 0 {
+				kept := append(orb.MultiPolygon{}, geom[:i]...)
+				for _, poly := range geom[i+1:] {
+					if len(poly) > 0 && len(poly[0]) > 0 {
+						kept = append(kept, poly)
+					}
+				}
+				return kept
+			}
+		}
+	case orb.Collection:
+		kept := make(orb.Collection, 0, len(geom))
+		for _, part := range geom {
+			if !IsEmpty(part) {
+				kept = append(kept, removeEmpty(part))
+			}
+		}
+		return kept
+	}
+	return g
+}
+
+// normalize prepares a non-empty geometry for the predicates: registered
+// types are replaced with the geometry they stand for, empty components are
+// dropped and collapsed areas are replaced with the lines or point they cover
+func normalize(g orb.Geometry) orb.Geometry {
+	return collapseAreas(removeEmpty(resolveCustom(g)))
+}
+
+// Envelope returns the bounding box of the non-empty parts of a geometry.
+// Unlike g.Bound(), empty components do not pull the envelope towards the
+// origin. The envelope of an empty geometry is the zero orb.Bound.
+func Envelope(g orb.Geometry) orb.Bound {
+	if IsEmpty(g) {
+		return orb.Bound{}
+	}
+	switch geom := removeEmpty(resolveCustom(g)).(type) {
+	case orb.MultiPoint:
+		return pointsBound(geom)
+	case orb.LineString:
+		return pointsBound(geom)
+	case orb.Ring:
+		return pointsBound(geom)
+	case orb.Polygon:
+		return pointsBound(geom[0])
+	case orb.MultiLineString:
+		bound := pointsBound(geom[0])
+		for _, ls := range geom[1:] {
+			bound = bound.Union(pointsBound(ls))
+		}
+		return bound
+	case orb.MultiPolygon:
+		bound := pointsBound(geom[0][0])
+		for _, poly := range geom[1:] {
+			bound = bound.Union(pointsBound(poly[0]))
+		}
+		return bound
+	case orb.Collection:
+		bound := Envelope(geom[0])
+		for _, part := range geom[1:] {
+			bound = bound.Union(Envelope(part))
+		}
+		return bound
+	default:
+		return geom.Bound()
+	}
+}
+
+// pointsBound returns the bound of a non-empty list of points. It is
+// orb's Bound without the calls to math.Min and math.Max, which matter for
+// the large geometries every predicate takes the envelope of.
+func pointsBound(points []orb.Point) orb.Bound {
+	b := orb.Bound{Min: points[0], Max: points[0]}
+	for _, p := range points[1:] {
+		if p[0] < b.Min[0] {
+			b.Min[0] = p[0]
+		} else if p[0] > b.Max[0] {
+			b.Max[0] = p[0]
+		}
+		if p[1] < b.Min[1] {
+			b.Min[1] = p[1]
+		} else if p[1] > b.Max[1] {
+			b.Max[1] = p[1]
+		}
+	}
+	return b
+}
+
+// Boundary returns the OGC boundary of a geometry:
+//   - Point and MultiPoint have an empty boundary (an empty Collection)
+//   - LineString and MultiLineString return the MultiPoint of end points that
+//     occur an odd number of times (the Mod-2 rule), so closed lines have an
+//     empty boundary
+//   - Ring, Polygon, MultiPolygon and Bound return their rings as a MultiLineString
+//   - Circle returns its circumference as a closed CircularString, and
+//     CircularString its end points like a LineString
+//   - Collection returns the boundary of the union of its parts: the edges
+//     of its areas that no other area covers, and the end points of its
+//     lines (by the Mod-2 rule across all of them) outside the areas
+//
+// Areas that have collapsed to lines or a point return the boundary of those,
+// and a Relater returns its boundary lines as a MultiLineString.
+// Use Evaluator.Boundary for the other boundary node rules.
+func Boundary(g orb.Geometry) orb.Geometry {
+	return boundaryWithRule(g, Mod2Rule)
+}
+
+// boundaryWithRule returns the boundary of g, using rule for the end points of lines
+func boundaryWithRule(g orb.Geometry, rule BoundaryNodeRule) orb.Geometry {
+	switch geom := collapseAreas(resolveCustom(g)).(type) {
+	case orb.Point, orb.MultiPoint:
+		return orb.Collection{}
+	case orb.LineString:
+		return lineBoundary(orb.MultiLineString{geom}, rule)
+	case orb.MultiLineString:
+		return lineBoundary(geom, rule)
+	case orb.Ring:
+		return ringsBoundary(orb.Polygon{geom})
+	case orb.Polygon:
+		return ringsBoundary(geom)
+	case orb.MultiPolygon:
+		var mls orb.MultiLineString
+		for _, poly := range geom {
+			mls = append(mls, ringsBoundary(poly)...)
+		}
+		return mls
+	case orb.Bound:
+		if geom.IsEmpty() {
+			return orb.MultiLineString{}
+		}
+		return ringsBoundary(boundToPolygon(geom))
+	case Circle:
+		return geom.boundaryString()
+	case CircularString:
+		if len(geom.Points) < 2 {
+			return orb.MultiPoint{}
+		}
+		return lineBoundary(orb.MultiLineString{geom.ends()}, rule)
+	case orb.Collection:
+		return collectionBoundary(geom, rule)
+	case Relater:
+		return relaterLinework(geom)
+	}
+	return orb.Collection{}
+}
+
+// lineBoundary returns the end points of the lines that are on the boundary
+// under rule. For Mod2Rule these are the end points that occur an odd number
+// of times.
+func lineBoundary(mls orb.MultiLineString, rule BoundaryNodeRule) orb.MultiPoint {
+	var endpoints []orb.Point
+	for _, ls := range mls {
+		if len(ls) < 2 {
+			continue
+		}
+		endpoints = append(endpoints, ls[0], ls[len(ls)-1])
+	}
+
+	boundary := orb.MultiPoint{}
+	for i, p := range endpoints {
+		// Only count each distinct end point once, at its first occurrence
+		seen := false
+		for _, q := range endpoints[:i] {
+			if pointsEqual(p, q) {
+				seen = true
+				break
+			}
+		}
+		if seen {
+			continue
+		}
+		if rule.isBoundary(countEndpoints(p, endpoints)) {
+			boundary = append(boundary, p)
+		}
+	}
+	return boundary
+}
+
+// countEndpoints counts how many of the end points equal p
+func countEndpoints(p orb.Point, endpoints []orb.Point) int {
+	count := 0
+	for _, q := range endpoints {
+		if pointsEqual(p, q) {
+			count++
+		}
+	}
+	return count
+}
+
+// ringsBoundary returns the rings of a polygon as a MultiLineString
+func ringsBoundary(poly orb.Polygon) orb.MultiLineString {
+	mls := make(orb.MultiLineString, 0, len(poly))
+	for _, ring := range poly {
+		if len(ring) == 0 {
+			continue
+		}
+		mls = append(mls, orb.LineString(ring))
+	}
+	return mls
+}
+
+// collectionBoundary returns the boundary of a collection as the relate
+// engine sees it, the union of its parts: the pieces of the area edges with
+// the union's interior on one side only, and the end points of the lines
+// under rule that are not inside an area. Circles and arcs of them are
+// returned as CircularStrings.
+func collectionBoundary(c orb.Collection, rule BoundaryNodeRule) orb.Geometry {
+	c, _ = removeEmpty(c).(orb.Collection)
+	if len(c) == 0 {
+		return orb.Collection{}
+	}
+	g := newRelateGeometry(c, Envelope(c), rule)
+
+	points := orb.MultiPoint{}
+	for _, p := range g.boundary {
+		if g.locate(p) == locBoundary {
+			points = append(points, p)
+		}
+	}
+
+	// Node the area edges against each other, so that every piece is
+	// either on the boundary of the union or not
+	var nodes []graphNode
+	var edges []graphEdge
+	g.eachAreaEdge(func(e relateEdge, interiorLeft bool) bool {
+		nodes = append(nodes, graphNode{p: e.a}, graphNode{p: e.b})
+		edges = append(edges, graphEdge{relateEdge: e, bound: e.bound(), interiorLeft: interiorLeft})
+		return false
+	})
+	slices.SortFunc(nodes, func(a, b graphNode) int { return cmp.Compare(a.p[0], b.p[0]) })
+	splits := vertexSplits(nil, nodes, edges)
+	splits, _ = crossEdges(splits, edges,
+		func(e, o *graphEdge) bool { return false },
+		func(x orb.Point, e, o *graphEdge) bool { return false })
+
+	// Kept pieces that follow on from each other are joined into one line,
+	// and the first and last line of a ring are joined when they meet
+	var rings orb.MultiLineString
+	var arcs []CircularString
+	var last *graphEdge
+	first, joined := -1, false
+	closeRing := func() {
+		if n := len(rings) - 1; first >= 0 && n > first && pointsEqual(rings[n][len(rings[n])-1], rings[first][0]) {
+			rings[first] = append(rings[n], rings[first][1:]...)
+			rings = rings[:n]
+		}
+		first = -1
+	}
+	eachPiece(edges, splits, func(e *graphEdge, t0, t1 float64) bool {
+		if last != e {
+			if last == nil || last.curved != e.curved || !pointsEqual(last.b, e.a) {
+				closeRing()
+				joined = false
+			}
+			last = e
+		}
+		t := (t0 + t1) / 2
+		mid := e.point(t)
+		if loc, left, right := g.locateEdge(mid, e.tangent(t)); loc != locBoundary || left == right {
+			joined = false
+			return false
+		}
+		a, b := e.point(t0), e.point(t1)
+		switch {
+		case e.curved && joined:
+			arc := &arcs[len(arcs)-1]
+			arc.Points = append(arc.Points, mid, b)
+		case e.curved:
+			arcs = append(arcs, CircularString{Points: []orb.Point{a, mid, b}})
+		case joined:
+			rings[len(rings)-1] = append(rings[len(rings)-1], b)
+		default:
+			if first < 0 {
+				first = len(rings)
+			}
+			rings = append(rings, orb.LineString{a, b})
+		}
+		joined = true
+		return false
+	})
+	closeRing()
+
+	if len(arcs) > 0 {
+		var parts orb.Collection
+		if len(points) > 0 {
+			parts = append(parts, points)
+		}
+		if len(rings) > 0 {
+			parts = append(parts, rings)
+		}
+		for _, arc := range arcs {
+			parts = append(parts, arc)
+		}
+		return parts
+	}
+	switch {
+	case len(points) == 0 && len(rings) == 0:
+		return orb.Collection{}
+	case len(rings) == 0:
+		return points
+	case len(points) == 0:
+		return rings
+	}
+	return orb.Collection{points, rings}
+}
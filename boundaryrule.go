@@ -0,0 +1,177 @@
+package predicates
+
+import (
+	"github.com/paulmach/orb"
+)
+
+// BoundaryNodeRule decides which end points of linear geometries are on their
+// boundary, from the number of line end points that meet at a node.
+// The package-level predicates use Mod2Rule, as required by OGC.
+type BoundaryNodeRule int
+
+const (
+	// Mod2Rule puts a node on the boundary when an odd number of end points
+	// meet there, so closed lines and lines joined end to end have no
+	// boundary at the join
+	Mod2Rule BoundaryNodeRule = iota
+	// EndPointRule puts every end point on the boundary, including the start
+	// of a closed line, as is usual in network analysis
+	EndPointRule
+	// MultiValentRule puts a node on the boundary only when more than one end
+	// point meets there, so a single line has no boundary
+	MultiValentRule
+	// MonoValentRule puts a node on the boundary only when exactly one end
+	// point meets there
+	MonoValentRule
+)
+
+// isBoundary reports whether a node where count end points meet is on the boundary
+func (r BoundaryNodeRule) isBoundary(count int) bool {
+	switch r {
+	case EndPointRule:
+		return count > 0
+	case MultiValentRule:
+		return count > 1
+	case MonoValentRule:
+		return count == 1
+	}
+	return count%2 == 1
+}
+
+// String returns the name of the rule
+func (r BoundaryNodeRule) String() string {
+	switch r {
+	case Mod2Rule:
+		return "Mod2"
+	case EndPointRule:
+		return "EndPoint"
+	case MultiValentRule:
+		return "MultiValent"
+	case MonoValentRule:
+		return "MonoValent"
+	}
+	return "BoundaryNodeRule(?)"
+}
+
+// Evaluator evaluates the predicates with a chosen BoundaryNodeRule. Only
+// linear geometries have boundary nodes, so for points and areas the results
+// are the same as the package-level predicates.
+type Evaluator struct {
+	rule BoundaryNodeRule
+}
+
+// WithBoundaryNodeRule returns an Evaluator that uses rule for the boundary
+// of linear geometries
+//
+//	network := predicates.WithBoundaryNodeRule(predicates.EndPointRule)
+//	network.Touches(road, otherRoad)
+func WithBoundaryNodeRule(rule BoundaryNodeRule) Evaluator {
+	return Evaluator{rule: rule}
+}
+
+// BoundaryNodeRule returns the rule used by the Evaluator
+func (e Evaluator) BoundaryNodeRule() BoundaryNodeRule {
+	return e.rule
+}
+
+// holds evaluates a single predicate under the Evaluator's rule
+func (e Evaluator) holds(a, b orb.Geometry, p PredicateSet) bool {
+	return evaluate(a, b, p, e.rule) != 0
+}
+
+// Relate returns the DE-9IM matrix of a and b under the Evaluator's rule, see Relate
+func (e Evaluator) Relate(a, b orb.Geometry) string {
+	return relateWithRule(normalize(a), normalize(b), e.rule).String()
+}
+
+// Evaluate returns which of the predicates in set hold for a and b under the
+// Evaluator's rule, see Evaluate. Where the boolean predicates read a
+// geometry they do not support as empty, Evaluate returns an error wrapping
+// ErrUnsupportedType for it, so it is the one to use for geometries that
+// come from elsewhere:
+//
+//	r, err := predicates.WithBoundaryNodeRule(predicates.Mod2Rule).Evaluate(a, b, predicates.PredDisjoint)
+func (e Evaluator) Evaluate(a, b orb.Geometry, set PredicateSet) (PredicateResult, error) {
+	if err := CheckGeometry(a); err != nil {
+		return 0, err
+	}
+	if err := CheckGeometry(b); err != nil {
+		return 0, err
+	}
+	return evaluate(a, b, set, e.rule), nil
+}
+
+// Intersects is the same under every rule, see Intersects
+func (e Evaluator) Intersects(a, b orb.Geometry) bool {
+	return Intersects(a, b)
+}
+
+// Disjoint is the same under every rule, see Disjoint
+func (e Evaluator) Disjoint(a, b orb.Geometry) bool {
+	return Disjoint(a, b)
+}
+
+// Covers is the same under every rule, see Covers
+func (e Evaluator) Covers(a, b orb.Geometry) bool {
+	return Covers(a, b)
+}
+
+// CoveredBy is the same under every rule, see CoveredBy
+func (e Evaluator) CoveredBy(a, b orb.Geometry) bool {
+	return CoveredBy(a, b)
+}
+
+// Within returns true if a is within b, see Within
+func (e Evaluator) Within(a, b orb.Geometry) bool {
+	return e.holds(a, b, PredWithin)
+}
+
+// Contains returns true if a contains b, see Contains
+func (e Evaluator) Contains(a, b orb.Geometry) bool {
+	return e.holds(a, b, PredContains)
+}
+
+// ContainsProperly returns true if b lies in the interior of a, see ContainsProperly
+func (e Evaluator) ContainsProperly(a, b orb.Geometry) bool {
+	return e.holds(a, b, PredContainsProperly)
+}
+
+// Touches returns true if only the boundaries of a and b meet, see Touches
+func (e Evaluator) Touches(a, b orb.Geometry) bool {
+	return e.holds(a, b, PredTouches)
+}
+
+// Crosses returns true if a and b cross, see Crosses
+func (e Evaluator) Crosses(a, b orb.Geometry) bool {
+	return e.holds(a, b, PredCrosses)
+}
+
+// Overlaps returns true if a and b overlap, see Overlaps
+func (e Evaluator) Overlaps(a, b orb.Geometry) bool {
+	return e.holds(a, b, PredOverlaps)
+}
+
+// Equals returns true if a and b have the same points, see Equals
+func (e Evaluator) Equals(a, b orb.Geometry) bool {
+	return e.holds(a, b, PredEquals)
+}
+
+// Boundary returns the boundary of g under the Evaluator's rule, see Boundary
+func (e Evaluator) Boundary(g orb.Geometry) orb.Geometry {
+	if e.rule == Mod2Rule || !hasLines(g) {
+		return Boundary(g)
+	}
+	return boundaryWithRule(g, e.rule)
+}
+
+// hasLines reports whether g has any non-empty lines or CircularStrings once
+// collapsed areas are taken as lines
+func hasLines(g orb.Geometry) bool {
+	g = collapseAreas(resolveCustom(g))
+	var lines orb.MultiLineString
+	collectLineStrings(g, &lines)
+	var circles []Circle
+	var strings []CircularString
+	collectCurves(g, &circles, &strings)
+	return len(lines) > 0 || len(strings) > 0
+}
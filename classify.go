@@ -0,0 +1,104 @@
+package predicates
+
+import "github.com/paulmach/orb"
+
+// Relation is the topological relation of one geometry to another, see
+// Classify
+type Relation uint8
+
+// The relations. For two regions they are the eight RCC8 / Egenhofer
+// relations, named here after the predicates they agree with; lines and
+// points also use RelCross.
+const (
+	RelDisjoint  Relation = iota // no point in common (RCC8 DC)
+	RelMeet                      // boundaries meet, interiors do not (EC)
+	RelOverlap                   // some but not all points in common (PO)
+	RelEqual                     // the same point set (EQ)
+	RelInside                    // a in the interior of b, away from its boundary (NTPP)
+	RelContains                  // b in the interior of a, away from its boundary (NTPPi)
+	RelCoveredBy                 // a inside b, touching its boundary (TPP)
+	RelCovers                    // b inside a, touching its boundary (TPPi)
+	RelCross                     // interiors cross, for lines and points
+)
+
+var relationNames = [...]string{
+	"disjoint", "meet", "overlap", "equal", "inside", "contains", "coveredby", "covers", "cross",
+}
+
+// String returns the name of the relation, such as "inside"
+func (r Relation) String() string {
+	if int(r) < len(relationNames) {
+		return relationNames[r]
+	}
+	return "unknown"
+}
+
+// Converse returns the relation of b to a for the relation r of a to b
+func (r Relation) Converse() Relation {
+	switch r {
+	case RelInside:
+		return RelContains
+	case RelContains:
+		return RelInside
+	case RelCoveredBy:
+		return RelCovers
+	case RelCovers:
+		return RelCoveredBy
+	}
+	return r
+}
+
+// Classify returns the single relation of a to b, read from their DE-9IM
+// matrix like the predicates, so that:
+//   - RelDisjoint is Disjoint(a, b) and RelMeet is Touches(a, b)
+//   - RelEqual is Within(a, b) && Contains(a, b)
+//   - RelInside is ContainsProperly(b, a), and RelCoveredBy any other Within(a, b)
+//   - RelContains is ContainsProperly(a, b), and RelCovers any other Contains(a, b)
+//   - RelCross is Crosses(a, b)
+//
+// Any other pair that intersects is RelOverlap, which includes every pair
+// for which Overlaps holds. A line lying along the boundary of a polygon
+// meets it: its interior is not in the polygon's.
+//
+// The relations only tell apart what the predicates do, so for lines and
+// points they do not say which boundary is involved. A point at the end of
+// a line and a point on a polygon's edge both meet it, and lines meet
+// whether they share end points or one ends on the other. Relate gives the
+// full matrix where that matters.
+func Classify(a, b orb.Geometry) Relation {
+	if IsEmpty(a) || IsEmpty(b) {
+		return RelDisjoint
+	}
+	a, b = normalize(a), normalize(b)
+	ea, eb := Envelope(a), Envelope(b)
+	if !boundsOverlap(ea, eb) {
+		return RelDisjoint
+	}
+	im := relateUntil(a, b, ea, eb, Mod2Rule, 0, 0, 0)
+	return im.classify(normalizedDimension(a), normalizedDimension(b))
+}
+
+// classify returns the relation the matrix of geometries of dimension dimA
+// and dimB describes, see Classify
+func (im intersectionMatrix) classify(dimA, dimB int) Relation {
+	within, contains := im.within(), im.contains()
+	switch {
+	case !im.intersects():
+		return RelDisjoint
+	case within && contains:
+		return RelEqual
+	case transpose(im).containsProperly():
+		return RelInside
+	case within:
+		return RelCoveredBy
+	case im.containsProperly():
+		return RelContains
+	case contains:
+		return RelCovers
+	case im.touches(dimA, dimB):
+		return RelMeet
+	case im.crosses(dimA, dimB):
+		return RelCross
+	}
+	return RelOverlap
+}
@@ -0,0 +1,199 @@
+// Command jtsrun runs JTS XML test files against the predicates and exits
+// with status 1 if any case regressed.
+//
+// Usage:
+//
+//	jtsrun [--format text|json] [--baseline file] [--write-baseline file] [-v] path...
+//
+// Each path is an XML file or a directory of them. A case fails when any of
+// its operations gives a result other than the expected one. Without a
+// baseline every failed case is a regression; with one, the cases it lists,
+// one key such as "TestRelateAA.xml#12" per line, are known failures and
+// only other failed cases are regressions. --write-baseline records the
+// failed cases of this run as the baseline for later runs.
+//
+//	jtsrun testdata/jts
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/tingold/orb-predicates/jtsxml"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the command and returns the exit code: 0 without regressions, 1
+// with regressions and 2 for usage errors or files that cannot be read
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("jtsrun", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "text", "output format: text or json")
+	baselinePath := flags.String("baseline", "", "file of known failing case keys")
+	writeBaseline := flags.String("write-baseline", "", "write the keys of the failed cases to this file")
+	verbose := flags.Bool("v", false, "list passed and skipped cases too")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: jtsrun [flags] path...\n\nRuns JTS XML test files, or directories of them.\n\nflags:")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(stderr, "jtsrun: unknown format %q\n", *format)
+		return 2
+	}
+
+	baseline := map[string]bool{}
+	if *baselinePath != "" {
+		var err error
+		if baseline, err = readBaseline(*baselinePath); err != nil {
+			fmt.Fprintf(stderr, "jtsrun: %v\n", err)
+			return 2
+		}
+	}
+
+	report := &jtsxml.Report{}
+	for _, path := range flags.Args() {
+		var r *jtsxml.Report
+		info, err := os.Stat(path)
+		if err == nil && info.IsDir() {
+			r, err = jtsxml.Runner{}.RunDir(path)
+		} else if err == nil {
+			r, err = jtsxml.Runner{}.RunFiles(path)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "jtsrun: %v\n", err)
+			return 2
+		}
+		report.Cases = append(report.Cases, r.Cases...)
+	}
+
+	var regressions, fixed []string
+	failed := map[string]bool{}
+	for _, c := range report.Failed() {
+		failed[c.Key()] = true
+		if !baseline[c.Key()] {
+			regressions = append(regressions, c.Key())
+		}
+	}
+	for key := range baseline {
+		if !failed[key] {
+			fixed = append(fixed, key)
+		}
+	}
+	sort.Strings(fixed)
+
+	if *writeBaseline != "" {
+		if err := writeKeys(*writeBaseline, report.Failed()); err != nil {
+			fmt.Fprintf(stderr, "jtsrun: %v\n", err)
+			return 2
+		}
+	}
+
+	if *format == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		err := enc.Encode(struct {
+			*jtsxml.Report
+			Passed      int      `json:"passed"`
+			Failed      int      `json:"failed"`
+			Skipped     int      `json:"skipped"`
+			Regressions []string `json:"regressions"`
+			Fixed       []string `json:"fixed"`
+		}{report, report.Count(jtsxml.Pass), report.Count(jtsxml.Fail), report.Count(jtsxml.Skip), regressions, fixed})
+		if err != nil {
+			fmt.Fprintf(stderr, "jtsrun: %v\n", err)
+			return 2
+		}
+	} else {
+		writeText(stdout, report, baseline, fixed, *verbose)
+	}
+
+	if len(regressions) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// writeText prints the failed cases, or all cases if verbose, and a summary
+func writeText(w io.Writer, report *jtsxml.Report, baseline map[string]bool, fixed []string, verbose bool) {
+	for _, c := range report.Cases {
+		if c.Status != jtsxml.Fail && !verbose {
+			continue
+		}
+		status := strings.ToUpper(c.Status.String())
+		if c.Status == jtsxml.Fail && baseline[c.Key()] {
+			status = "KNOWN"
+		}
+		fmt.Fprintf(w, "%s %s %s\n", status, c.Key(), c.Desc)
+		for _, op := range c.Ops {
+			if op.Status == jtsxml.Pass || (op.Status == jtsxml.Skip && !verbose) {
+				continue
+			}
+			fmt.Fprintf(w, "    %s %s(%s, %s%s): got %s, expected %s", op.Status, op.Name, op.Arg1, op.Arg2, arg3(op.Arg3), op.Actual, op.Expected)
+			if op.Reason != "" {
+				fmt.Fprintf(w, " (%s)", op.Reason)
+			}
+			fmt.Fprintln(w)
+		}
+	}
+	for _, key := range fixed {
+		fmt.Fprintf(w, "FIXED %s\n", key)
+	}
+	fmt.Fprintf(w, "%d cases: %d passed, %d failed, %d skipped\n",
+		len(report.Cases), report.Count(jtsxml.Pass), report.Count(jtsxml.Fail), report.Count(jtsxml.Skip))
+}
+
+func arg3(s string) string {
+	if s == "" {
+		return ""
+	}
+	return ", " + s
+}
+
+// readBaseline reads case keys, one per line. Blank lines and lines starting
+// with # are ignored.
+func readBaseline(path string) (map[string]bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	keys := map[string]bool{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			keys[line] = true
+		}
+	}
+	return keys, scanner.Err()
+}
+
+// writeKeys writes the keys of the cases as a baseline
+func writeKeys(path string, cases []jtsxml.CaseResult) error {
+	var sb strings.Builder
+	sb.WriteString("# Known failing JTS cases, written by jtsrun --write-baseline\n")
+	for _, c := range cases {
+		fmt.Fprintf(&sb, "%s\n", c.Key())
+	}
+	return os.WriteFile(path, []byte(sb.String()), 0o644)
+}
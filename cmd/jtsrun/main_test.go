@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const fixture = `<run>
+  <case>
+    <desc>point in square</desc>
+    <a>POINT (1 1)</a>
+    <b>POLYGON ((0 0, 2 0, 2 2, 0 2, 0 0))</b>
+    <test><op name="within" arg1="A" arg2="B">true</op></test>
+  </case>
+  <case>
+    <desc>wrong expectation</desc>
+    <a>POINT (5 5)</a>
+    <b>POLYGON ((0 0, 2 0, 2 2, 0 2, 0 0))</b>
+    <test><op name="intersects" arg1="A" arg2="B">true</op></test>
+  </case>
+</run>`
+
+func runArgs(args ...string) (int, string) {
+	var out, errOut bytes.Buffer
+	code := run(args, &out, &errOut)
+	return code, out.String() + errOut.String()
+}
+
+func TestJTSRun(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "cases.xml"), []byte(fixture), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	baseline := filepath.Join(dir, "baseline.txt")
+
+	code, out := runArgs(dir)
+	if code != 1 || !strings.Contains(out, "FAIL cases.xml#2 wrong expectation") || !strings.Contains(out, "2 cases: 1 passed, 1 failed") {
+		t.Errorf("jtsrun = %d:\n%s\nexpected a regression", code, out)
+	}
+
+	// A failure recorded in the baseline is known
+	if code, out := runArgs("--write-baseline", baseline, dir); code != 1 {
+		t.Errorf("jtsrun --write-baseline = %d:\n%s", code, out)
+	}
+	code, out = runArgs("--baseline", baseline, filepath.Join(dir, "cases.xml"))
+	if code != 0 || !strings.Contains(out, "KNOWN cases.xml#2") {
+		t.Errorf("jtsrun --baseline = %d:\n%s\nexpected no regressions", code, out)
+	}
+
+	// A baseline case that passes is reported as fixed
+	if err := os.WriteFile(baseline, []byte("# known\ncases.xml#1\ncases.xml#2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	code, out = runArgs("--baseline", baseline, "--format", "json", dir)
+	var result struct {
+		Passed      int      `json:"passed"`
+		Failed      int      `json:"failed"`
+		Regressions []string `json:"regressions"`
+		Fixed       []string `json:"fixed"`
+		Cases       []struct {
+			Status string `json:"status"`
+		} `json:"cases"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out)
+	}
+	if code != 0 || result.Passed != 1 || result.Failed != 1 || len(result.Regressions) != 0 ||
+		len(result.Fixed) != 1 || result.Fixed[0] != "cases.xml#1" || result.Cases[1].Status != "fail" {
+		t.Errorf("jtsrun --format json = %d, %+v", code, result)
+	}
+}
+
+func TestJTSRunFixtures(t *testing.T) {
+	if code, out := runArgs("../../testdata/jts"); code != 0 {
+		t.Errorf("jtsrun testdata/jts = %d:\n%s", code, out)
+	}
+}
+
+func TestJTSRunErrors(t *testing.T) {
+	for _, args := range [][]string{
+		nil,
+		{"--format", "xml", "."},
+		{"missing.xml"},
+		{"--baseline", "missing.txt", "."},
+	} {
+		if code, _ := runArgs(args...); code != 2 {
+			t.Errorf("jtsrun %v = %d, expected 2", args, code)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Command orbpred-server serves the predicates as a JSON API over HTTP; see
+// package server for the endpoints.
+//
+// Usage:
+//
+//	orbpred-server [--addr :8080] [--max-body bytes] [--timeout 10s] [--max-batch n] [--max-geometries n]
+//
+// The server shuts down gracefully on SIGINT or SIGTERM.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/tingold/orb-predicates/server"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], os.Stderr))
+}
+
+// run serves until ctx is done and returns the exit code: 0 after a clean
+// shutdown, 1 when the server fails and 2 for usage errors
+func run(ctx context.Context, args []string, stderr io.Writer) int {
+	flags := flag.NewFlagSet("orbpred-server", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	addr := flags.String("addr", ":8080", "address to listen on")
+	var opts server.Options
+	flags.Int64Var(&opts.MaxBodyBytes, "max-body", 1<<20, "maximum request body size in bytes")
+	flags.DurationVar(&opts.Timeout, "timeout", 10*time.Second, "deadline for answering a request; evaluations in progress still finish")
+	flags.IntVar(&opts.MaxBatch, "max-batch", 1000, "maximum number of requests in a batch")
+	flags.IntVar(&opts.MaxGeometries, "max-geometries", 10000, "maximum number of registered geometries")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintf(stderr, "orbpred-server: unexpected arguments %v\n", flags.Args())
+		return 2
+	}
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           server.New(opts),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errc := make(chan error, 1)
+	go func() {
+		fmt.Fprintf(stderr, "orbpred-server: listening on %s\n", *addr)
+		errc <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errc:
+		fmt.Fprintf(stderr, "orbpred-server: %v\n", err)
+		return 1
+	case <-ctx.Done():
+	}
+
+	shutdown, cancel := context.WithTimeout(context.Background(), opts.Timeout+5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdown); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(stderr, "orbpred-server: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	if code := run(ctx, []string{"--addr", "127.0.0.1:0", "--timeout", "1s"}, &stderr); code != 0 {
+		t.Errorf("orbpred-server = %d:\n%s\nexpected a clean shutdown", code, stderr.String())
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	for _, args := range [][]string{
+		{"--timeout", "soon"},
+		{"extra"},
+	} {
+		if code := run(context.Background(), args, new(bytes.Buffer)); code != 2 {
+			t.Errorf("orbpred-server %v = %d, expected 2", args, code)
+		}
+	}
+	if code := run(context.Background(), []string{"--addr", "bad:address:1"}, new(bytes.Buffer)); code != 1 {
+		t.Errorf("orbpred-server with a bad address = %d, expected 1", code)
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/paulmach/orb"
+
+	predicates "github.com/tingold/orb-predicates"
+)
+
+// predicate is one of the predicates of the predicates package
+type predicate struct {
+	name string
+	set  predicates.PredicateSet
+}
+
+// fn evaluates the predicate
+func (p predicate) fn(a, b orb.Geometry) bool {
+	return predicates.Evaluate(a, b, p.set) != 0
+}
+
+// allPredicates are the predicates eval runs without --pred, in output order
+var allPredicates = func() []predicate {
+	var preds []predicate
+	for set := predicates.AllPredicates; set != 0; set &= set - 1 {
+		p := set & -set
+		preds = append(preds, predicate{p.String(), p})
+	}
+	return preds
+}()
+
+// lookupPredicate finds a predicate by name, ignoring case and underscores,
+// so "coveredby", "CoveredBy" and "covered_by" are the same
+func lookupPredicate(name string) (predicate, bool) {
+	p, ok := predicates.ParsePredicate(name)
+	return predicate{p.String(), p}, ok
+}
+
+// predicateNames lists the names accepted by --pred
+func predicateNames() string {
+	return strings.ReplaceAll(predicates.AllPredicates.String(), "|", ", ")
+}
+
+// evalResult is the JSON output of eval
+type evalResult struct {
+	A          string          `json:"a"`
+	B          string          `json:"b"`
+	Predicates map[string]bool `json:"predicates"`
+	Relate     string          `json:"relate,omitempty"`
+}
+
+// runEval runs the eval command
+func runEval(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("eval", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	pred := flags.String("pred", "", "evaluate only this predicate: "+predicateNames())
+	relate := flags.Bool("relate", false, "print the DE-9IM matrix")
+	format := flags.String("format", "table", "output format: table or json")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: orbpred eval [flags] a b\n\nReads a geometry from each of a and b (WKT, WKB or GeoJSON; - for stdin).\n\nflags:")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+	if flags.Arg(0) == "-" && flags.Arg(1) == "-" {
+		fmt.Fprintln(stderr, "orbpred eval: only one geometry can be read from stdin")
+		return 2
+	}
+	if *format != "table" && *format != "json" {
+		fmt.Fprintf(stderr, "orbpred eval: unknown format %q\n", *format)
+		return 2
+	}
+
+	preds := allPredicates
+	if *pred != "" {
+		p, ok := lookupPredicate(*pred)
+		if !ok {
+			fmt.Fprintf(stderr, "orbpred eval: unknown predicate %q, expected one of %s\n", *pred, predicateNames())
+			return 2
+		}
+		preds = []predicate{p}
+	}
+
+	a, err := readGeometry(flags.Arg(0), stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "orbpred eval: %v\n", err)
+		return 1
+	}
+	b, err := readGeometry(flags.Arg(1), stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "orbpred eval: %v\n", err)
+		return 1
+	}
+
+	var set predicates.PredicateSet
+	for _, p := range preds {
+		set |= p.set
+	}
+	r := predicates.Evaluate(a, b, set)
+	result := evalResult{A: flags.Arg(0), B: flags.Arg(1), Predicates: make(map[string]bool, len(preds))}
+	for _, p := range preds {
+		result.Predicates[p.name] = r.Has(p.set)
+	}
+	if *relate {
+		result.Relate = predicates.Relate(a, b)
+	}
+
+	if *format == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(result); err != nil {
+			fmt.Fprintf(stderr, "orbpred eval: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	for _, p := range preds {
+		fmt.Fprintf(w, "%s\t%t\n", p.name, result.Predicates[p.name])
+	}
+	if *relate {
+		fmt.Fprintf(w, "relate\t%s\n", result.Relate)
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Fprintf(stderr, "orbpred eval: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+
+	"github.com/tingold/orb-predicates/geojsonpred"
+)
+
+// joinBatch is the number of left features matched before their records are
+// written, which bounds the matches held in memory
+const joinBatch = 4096
+
+// rightPrefix is put before the property names of right features in joined
+// GeoJSON records
+const rightPrefix = "right_"
+
+// runJoin runs the join command
+func runJoin(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("join", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	pred := flags.String("pred", "intersects", "join predicate: "+predicateNames())
+	mode := flags.String("mode", "inner", "join mode: inner, left or anti")
+	format := flags.String("format", "geojson", "output format: geojson or csv")
+	wktColumn := flags.String("wkt-column", "wkt", "geometry column of CSV layers, as WKT or hex WKB")
+	idColumn := flags.String("id-column", "id", "ID column of CSV layers")
+	limit := flags.Int("limit-per-left", 0, "join each left feature to at most this many right features, 0 for all")
+	workers := flags.Int("workers", runtime.GOMAXPROCS(0), "number of parallel workers")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: orbpred join [flags] left right\n\nJoins the features of left to the features of right for which pred(left, right) holds.\nLayers are GeoJSON or CSV (.csv); - reads stdin.\n\nflags:")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+	usageError := func(format string, args ...interface{}) int {
+		fmt.Fprintf(stderr, "orbpred join: "+format+"\n", args...)
+		return 2
+	}
+	p, ok := lookupPredicate(*pred)
+	switch {
+	case !ok:
+		return usageError("unknown predicate %q, expected one of %s", *pred, predicateNames())
+	case *mode != "inner" && *mode != "left" && *mode != "anti":
+		return usageError("unknown mode %q", *mode)
+	case *format != "geojson" && *format != "csv":
+		return usageError("unknown format %q", *format)
+	case *limit < 0:
+		return usageError("--limit-per-left must not be negative")
+	case *workers < 1:
+		return usageError("--workers must be at least 1")
+	case flags.Arg(0) == "-" && flags.Arg(1) == "-":
+		return usageError("only one layer can be read from stdin")
+	}
+
+	opts := layerOptions{wktColumn: *wktColumn, idColumn: *idColumn}
+	left, err := readLayer(flags.Arg(0), stdin, opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "orbpred join: %v\n", err)
+		return 1
+	}
+	right, err := readLayer(flags.Arg(1), stdin, opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "orbpred join: %v\n", err)
+		return 1
+	}
+
+	var w joinWriter
+	if *format == "csv" {
+		w = newCSVJoinWriter(stdout, *mode)
+	} else {
+		w = newGeoJSONJoinWriter(stdout)
+	}
+	j := joiner{
+		right:   right,
+		index:   geojsonpred.NewIndex(right),
+		pred:    p,
+		mode:    *mode,
+		limit:   *limit,
+		workers: *workers,
+	}
+	if err := j.join(left, w); err != nil {
+		fmt.Fprintf(stderr, "orbpred join: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// joiner matches left features against an index of the right layer
+type joiner struct {
+	right   *geojson.FeatureCollection
+	index   *geojsonpred.Index
+	pred    predicate
+	mode    string
+	limit   int
+	workers int
+}
+
+// join writes the joined records of the left features in their order. The
+// left features are matched in batches, each split between the workers.
+func (j joiner) join(left *geojson.FeatureCollection, w joinWriter) error {
+	matches := make([][]int, joinBatch)
+	for start := 0; start < len(left.Features); start += joinBatch {
+		batch := left.Features[start:min(start+joinBatch, len(left.Features))]
+		j.matchBatch(batch, matches)
+
+		for i, f := range batch {
+			m := matches[i]
+			var err error
+			switch {
+			case j.mode == "anti":
+				if len(m) == 0 {
+					err = w.unmatched(f, start+i)
+				}
+			case len(m) == 0:
+				if j.mode == "left" {
+					err = w.unmatched(f, start+i)
+				}
+			default:
+				for _, r := range m {
+					if err = w.matched(f, start+i, j.right.Features[r], r); err != nil {
+						break
+					}
+				}
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return w.close()
+}
+
+// matchBatch sets matches[i] to the right features joined to batch[i]
+func (j joiner) matchBatch(batch []*geojson.Feature, matches [][]int) {
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for n := 0; n < min(j.workers, len(batch)); n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= len(batch) {
+					return
+				}
+				matches[i] = nil
+				if batch[i].Geometry == nil {
+					continue
+				}
+				matches[i] = j.match(batch[i].Geometry)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// match returns the positions of the right features joined to a left
+// geometry. Unless pred can hold for geometries apart, such as disjoint, or
+// for two empty ones, such as equals, only the right features whose boxes
+// meet the box of g are tested.
+func (j joiner) match(g orb.Geometry) []int {
+	candidates := j.index.CandidatesFor(j.pred.set, g)
+	m := candidates[:0]
+	for _, i := range candidates {
+		if j.pred.fn(g, j.right.Features[i].Geometry) {
+			m = append(m, i)
+			if len(m) == j.limit {
+				break
+			}
+		}
+	}
+	return m
+}
+
+// joinWriter writes joined records. Features are passed with their
+// position in their layer, which stands in for a missing ID.
+type joinWriter interface {
+	matched(left *geojson.Feature, leftPos int, right *geojson.Feature, rightPos int) error
+	unmatched(left *geojson.Feature, leftPos int) error
+	close() error
+}
+
+// featureID returns the ID of f, or its position in its layer if it has none
+func featureID(f *geojson.Feature, pos int) string {
+	if f.ID == nil {
+		return strconv.Itoa(pos)
+	}
+	return fmt.Sprint(f.ID)
+}
+
+// csvJoinWriter writes the IDs of joined features, or of the left features
+// alone in an anti join
+type csvJoinWriter struct {
+	w    *csv.Writer
+	anti bool
+	err  error
+}
+
+func newCSVJoinWriter(out io.Writer, mode string) *csvJoinWriter {
+	w := &csvJoinWriter{w: csv.NewWriter(out), anti: mode == "anti"}
+	if w.anti {
+		w.err = w.w.Write([]string{"left_id"})
+	} else {
+		w.err = w.w.Write([]string{"left_id", "right_id"})
+	}
+	return w
+}
+
+func (w *csvJoinWriter) matched(left *geojson.Feature, leftPos int, right *geojson.Feature, rightPos int) error {
+	if w.err == nil {
+		w.err = w.w.Write([]string{featureID(left, leftPos), featureID(right, rightPos)})
+	}
+	return w.err
+}
+
+func (w *csvJoinWriter) unmatched(left *geojson.Feature, leftPos int) error {
+	if w.err != nil {
+		return w.err
+	}
+	if w.anti {
+		w.err = w.w.Write([]string{featureID(left, leftPos)})
+	} else {
+		w.err = w.w.Write([]string{featureID(left, leftPos), ""})
+	}
+	return w.err
+}
+
+func (w *csvJoinWriter) close() error {
+	w.w.Flush()
+	if w.err != nil {
+		return w.err
+	}
+	return w.w.Error()
+}
+
+// geoJSONJoinWriter streams a FeatureCollection of joined records. A record
+// has the geometry and ID of the left feature, its properties, and the
+// properties of the right feature prefixed with rightPrefix, along with
+// right_id.
+type geoJSONJoinWriter struct {
+	w     *bufio.Writer
+	count int
+	err   error
+}
+
+func newGeoJSONJoinWriter(out io.Writer) *geoJSONJoinWriter {
+	w := &geoJSONJoinWriter{w: bufio.NewWriter(out)}
+	_, w.err = w.w.WriteString(`{"type":"FeatureCollection","features":[`)
+	return w
+}
+
+func (w *geoJSONJoinWriter) matched(left *geojson.Feature, leftPos int, right *geojson.Feature, rightPos int) error {
+	props := make(geojson.Properties, len(left.Properties)+len(right.Properties)+1)
+	for k, v := range left.Properties {
+		props[k] = v
+	}
+	for k, v := range right.Properties {
+		props[rightPrefix+k] = v
+	}
+	props[rightPrefix+"id"] = featureID(right, rightPos)
+	return w.write(&geojson.Feature{Type: "Feature", ID: left.ID, Geometry: left.Geometry, Properties: props})
+}
+
+func (w *geoJSONJoinWriter) unmatched(left *geojson.Feature, leftPos int) error {
+	return w.write(left)
+}
+
+func (w *geoJSONJoinWriter) write(f *geojson.Feature) error {
+	if w.err != nil {
+		return w.err
+	}
+	data, err := json.Marshal(f)
+	if err != nil {
+		w.err = err
+		return err
+	}
+	if w.count > 0 {
+		if w.err = w.w.WriteByte(','); w.err != nil {
+			return w.err
+		}
+	}
+	w.count++
+	_, w.err = w.w.Write(data)
+	return w.err
+}
+
+func (w *geoJSONJoinWriter) close() error {
+	if w.err != nil {
+		return w.err
+	}
+	if _, err := w.w.WriteString("]}\n"); err != nil {
+		return err
+	}
+	return w.w.Flush()
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/encoding/wkt"
+	"github.com/paulmach/orb/geojson"
+)
+
+// joinLayers writes a GeoJSON layer of n by n points with a name property
+// and a CSV layer of overlapping squares, and returns their paths
+func joinLayers(t *testing.T, n int) (left, right string) {
+	t.Helper()
+	points := geojson.NewFeatureCollection()
+	for x := 0; x < n; x++ {
+		for y := 0; y < n; y++ {
+			f := geojson.NewFeature(orb.Point{float64(x) + 0.5, float64(y) + 0.5})
+			f.ID = fmt.Sprintf("p%d-%d", x, y)
+			f.Properties["name"] = f.ID
+			points.Append(f)
+		}
+	}
+	data, err := json.Marshal(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var csv strings.Builder
+	csv.WriteString("id,kind,geom\n")
+	for i := 0; i < n; i += 2 {
+		square := orb.Polygon{{{float64(i), 0}, {float64(i) + 3, 0}, {float64(i) + 3, 3}, {float64(i), 3}, {float64(i), 0}}}
+		fmt.Fprintf(&csv, "s%d,square,%q\n", i, wkt.MarshalString(square))
+	}
+	csv.WriteString("none,empty,\n")
+
+	dir := writeFiles(t, map[string]string{"points.geojson": string(data), "squares.csv": csv.String()})
+	return filepath.Join(dir, "points.geojson"), filepath.Join(dir, "squares.csv")
+}
+
+// expectedPairs joins the layers by brute force
+func expectedPairs(t *testing.T, left, right string, pred func(a, b orb.Geometry) bool) [][2]string {
+	t.Helper()
+	opts := layerOptions{wktColumn: "geom", idColumn: "id"}
+	l, err := readLayer(left, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := readLayer(right, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pairs [][2]string
+	for _, lf := range l.Features {
+		for _, rf := range r.Features {
+			if rf.Geometry != nil && pred(lf.Geometry, rf.Geometry) {
+				pairs = append(pairs, [2]string{lf.ID.(string), rf.ID.(string)})
+			}
+		}
+	}
+	return pairs
+}
+
+func csvPairs(out string) [][2]string {
+	var pairs [][2]string
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n")[1:] {
+		ids := strings.SplitN(line, ",", 2)
+		pairs = append(pairs, [2]string{ids[0], ids[len(ids)-1]})
+	}
+	return pairs
+}
+
+func TestJoin(t *testing.T) {
+	// Large enough for several batches
+	left, right := joinLayers(t, 70)
+
+	for _, name := range []string{"intersects", "within", "touches", "disjoint"} {
+		p, _ := lookupPredicate(name)
+		want := expectedPairs(t, left, right, p.fn)
+		for _, workers := range []string{"1", "4"} {
+			t.Run(name+"/"+workers, func(t *testing.T) {
+				args := []string{"join", "--pred", name, "--format", "csv", "--wkt-column", "geom", "--workers", workers, left, right}
+				code, out, errOut := runArgs("", args...)
+				if code != 0 {
+					t.Fatalf("orbpred %v = %d, stderr %q", args, code, errOut)
+				}
+				if got := csvPairs(out); fmt.Sprint(got) != fmt.Sprint(want) {
+					t.Errorf("got %d pairs, expected %d", len(got), len(want))
+				}
+			})
+		}
+	}
+}
+
+func TestJoinModes(t *testing.T) {
+	left, right := joinLayers(t, 6)
+	run := func(args ...string) string {
+		t.Helper()
+		args = append(append([]string{"join", "--format", "csv", "--wkt-column", "geom"}, args...), left, right)
+		code, out, errOut := runArgs("", args...)
+		if code != 0 {
+			t.Fatalf("orbpred %v = %d, stderr %q", args, code, errOut)
+		}
+		return out
+	}
+
+	// Points with y > 3 are outside every square
+	inner := csvPairs(run("--pred", "within"))
+	leftJoin := csvPairs(run("--pred", "within", "--mode", "left"))
+	anti := csvPairs(run("--pred", "within", "--mode", "anti"))
+	if len(anti) != 18 {
+		t.Errorf("anti join has %d rows, expected 18", len(anti))
+	}
+	if len(leftJoin) != len(inner)+len(anti) {
+		t.Errorf("left join has %d rows, expected %d matched and %d unmatched", len(leftJoin), len(inner), len(anti))
+	}
+	for _, pair := range anti {
+		if !strings.HasSuffix(pair[0], "-3") && !strings.HasSuffix(pair[0], "-4") && !strings.HasSuffix(pair[0], "-5") {
+			t.Errorf("anti join row %v should be above the squares", pair)
+		}
+	}
+
+	// p2-0 is within squares s0 and s2
+	var limited [][2]string
+	for _, pair := range csvPairs(run("--pred", "within", "--limit-per-left", "1")) {
+		if pair[0] == "p2-0" {
+			limited = append(limited, pair)
+		}
+	}
+	if fmt.Sprint(limited) != "[[p2-0 s0]]" {
+		t.Errorf("limited join of p2-0 = %v, expected [[p2-0 s0]]", limited)
+	}
+}
+
+func TestJoinGeoJSON(t *testing.T) {
+	left, right := joinLayers(t, 4)
+	args := []string{"join", "--pred", "within", "--wkt-column", "geom", "--mode", "left", left, right}
+	code, out, errOut := runArgs("", args...)
+	if code != 0 {
+		t.Fatalf("orbpred %v = %d, stderr %q", args, code, errOut)
+	}
+	fc, err := geojson.UnmarshalFeatureCollection([]byte(out))
+	if err != nil {
+		t.Fatalf("output is not a FeatureCollection: %v\n%s", err, out)
+	}
+
+	records := make(map[string][]geojson.Properties)
+	for _, f := range fc.Features {
+		records[f.ID.(string)] = append(records[f.ID.(string)], f.Properties)
+	}
+	matched, unmatched := records["p0-0"], records["p0-3"]
+	if len(matched) != 1 || matched[0]["name"] != "p0-0" || matched[0]["right_id"] != "s0" || matched[0]["right_kind"] != "square" {
+		t.Errorf("records of p0-0 = %v, expected one joined to s0", matched)
+	}
+	if len(unmatched) != 1 || unmatched[0]["name"] != "p0-3" || unmatched[0]["right_id"] != nil {
+		t.Errorf("records of p0-3 = %v, expected it unmatched", unmatched)
+	}
+}
+
+func TestJoinEmpty(t *testing.T) {
+	// Empty geometries have no box in the index but are equal to each other
+	dir := writeFiles(t, map[string]string{
+		"left.csv":  "id,wkt\na,POLYGON EMPTY\nb,POINT (1 1)\n",
+		"right.csv": "id,wkt\nx,LINESTRING EMPTY\ny,POINT (1 1)\nz,POINT EMPTY\n",
+	})
+	args := []string{"join", "--pred", "equals", "--format", "csv", filepath.Join(dir, "left.csv"), filepath.Join(dir, "right.csv")}
+	code, out, errOut := runArgs("", args...)
+	if code != 0 {
+		t.Fatalf("orbpred %v = %d, stderr %q", args, code, errOut)
+	}
+	if got := fmt.Sprint(csvPairs(out)); got != "[[a x] [a z] [b y]]" {
+		t.Errorf("equals join = %v, expected [[a x] [a z] [b y]]", got)
+	}
+}
+
+func TestJoinErrors(t *testing.T) {
+	left, right := joinLayers(t, 2)
+	tests := []struct {
+		name     string
+		args     []string
+		expected int
+	}{
+		{"one layer", []string{"join", left}, 2},
+		{"unknown mode", []string{"join", "--mode", "outer", left, right}, 2},
+		{"negative limit", []string{"join", "--limit-per-left", "-1", left, right}, 2},
+		{"missing wkt column", []string{"join", left, right}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code, _, _ := runArgs("", tt.args...); code != tt.expected {
+				t.Errorf("orbpred %v = %d, expected %d", tt.args, code, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/paulmach/orb/geojson"
+
+	"github.com/tingold/orb-predicates/geojsonpred"
+)
+
+// layerOptions says how to read the rows of a CSV layer
+type layerOptions struct {
+	wktColumn string
+	idColumn  string
+}
+
+// readLayer reads the features in the named file, or in stdin if name is
+// "-". A .csv file, or stdin that does not start with '{', is read as CSV
+// with a header row; other files as GeoJSON, where a single Feature or
+// geometry is a layer of one feature.
+func readLayer(name string, stdin io.Reader, opts layerOptions) (*geojson.FeatureCollection, error) {
+	var data []byte
+	var err error
+	if name == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	isCSV := strings.EqualFold(filepath.Ext(name), ".csv")
+	if name == "-" {
+		text := bytes.TrimSpace(data)
+		isCSV = len(text) > 0 && text[0] != '{'
+	}
+
+	var fc *geojson.FeatureCollection
+	if isCSV {
+		fc, err = parseCSVLayer(data, opts)
+	} else {
+		fc, err = parseGeoJSONLayer(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return fc, nil
+}
+
+// parseGeoJSONLayer decodes a FeatureCollection, or a Feature or geometry as
+// a collection of one feature
+func parseGeoJSONLayer(data []byte) (*geojson.FeatureCollection, error) {
+	var object struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	if object.Type == "FeatureCollection" {
+		return geojson.UnmarshalFeatureCollection(data)
+	}
+
+	fc := geojson.NewFeatureCollection()
+	if object.Type == "Feature" {
+		f, err := geojson.UnmarshalFeature(data)
+		if err != nil {
+			return nil, err
+		}
+		return fc.Append(f), nil
+	}
+	g, err := geojsonpred.DecodeGeometry(data)
+	if err != nil {
+		return nil, err
+	}
+	return fc.Append(geojson.NewFeature(g)), nil
+}
+
+// parseCSVLayer reads CSV with a header row. The geometry of each row is
+// read from opts.wktColumn as WKT or hex encoded WKB, and its ID from
+// opts.idColumn if the file has that column. The other columns become
+// string properties. A row with an empty geometry cell has no geometry.
+func parseCSVLayer(data []byte, opts layerOptions) (*geojson.FeatureCollection, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	header, err := r.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	geomColumn, idColumn := -1, -1
+	for i, name := range header {
+		switch name {
+		case opts.wktColumn:
+			geomColumn = i
+		case opts.idColumn:
+			idColumn = i
+		}
+	}
+	if geomColumn < 0 {
+		return nil, fmt.Errorf("no %q column, set --wkt-column", opts.wktColumn)
+	}
+
+	fc := geojson.NewFeatureCollection()
+	for line := 2; ; line++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			return fc, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		f := &geojson.Feature{Type: "Feature", Properties: make(geojson.Properties, len(record))}
+		for i, value := range record {
+			switch i {
+			case geomColumn:
+				if strings.TrimSpace(value) == "" {
+					continue
+				}
+				g, err := parseGeometry([]byte(value), "")
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %w", line, err)
+				}
+				f.Geometry = g
+			case idColumn:
+				f.ID = value
+			default:
+				f.Properties[header[i]] = value
+			}
+		}
+		fc.Append(f)
+	}
+}
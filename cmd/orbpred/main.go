@@ -0,0 +1,61 @@
+// Command orbpred evaluates the spatial predicates on geometries read from
+// WKT, WKB, GeoJSON or CSV files.
+//
+// Usage:
+//
+//	orbpred eval [--pred name] [--relate] [--format table|json] a b
+//	orbpred join [--pred name] [--mode inner|left|anti] [--format geojson|csv] left right
+//
+// eval reads one geometry from each of a and b, where "-" is standard input,
+// and prints the result of the named predicate, or of every predicate, with
+// a as the first argument. --relate also prints the DE-9IM matrix.
+//
+//	orbpred eval --pred within parcel.geojson zone.wkt
+//
+// join reads two layers of features, from GeoJSON or from CSV with a WKT
+// column, and writes a record for every pair of a left and a right feature
+// for which the predicate holds. The right layer is indexed and the left
+// features are matched by parallel workers.
+//
+//	orbpred join --pred intersects --wkt-column geom left.geojson right.csv
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+const usage = `usage: orbpred <command> [flags] [args]
+
+commands:
+  eval    evaluate predicates on two geometries
+  join    join two layers of features by a predicate
+
+Run "orbpred <command> --help" for the flags of a command.
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command in args and returns the exit code: 0 on success, 1
+// when an input cannot be read and 2 for usage errors
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	switch args[0] {
+	case "eval":
+		return runEval(args[1:], stdin, stdout, stderr)
+	case "join":
+		return runJoin(args[1:], stdin, stdout, stderr)
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	}
+	fmt.Fprintf(stderr, "orbpred: unknown command %q\n\n%s", args[0], usage)
+	return 2
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/encoding/wkb"
+)
+
+// writeFiles writes the named files to a temporary directory and returns it
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func runArgs(stdin string, args ...string) (code int, stdout, stderr string) {
+	var out, errOut bytes.Buffer
+	code = run(args, strings.NewReader(stdin), &out, &errOut)
+	return code, out.String(), errOut.String()
+}
+
+func TestEval(t *testing.T) {
+	point := orb.Point{1, 1}
+	dir := writeFiles(t, map[string]string{
+		"square.wkt":     "POLYGON ((0 0, 4 0, 4 4, 0 4, 0 0))\n",
+		"point.geojson":  `{"type": "Feature", "geometry": {"type": "Point", "coordinates": [1, 1]}, "properties": {}}`,
+		"points.geojson": `{"type": "FeatureCollection", "features": [{"type": "Feature", "geometry": {"type": "Point", "coordinates": [1, 1]}, "properties": {}}, {"type": "Feature", "geometry": {"type": "Point", "coordinates": [9, 9]}, "properties": {}}]}`,
+		"point.wkb":      string(wkb.MustMarshal(point)),
+		"point.hex":      wkb.MustMarshalToHex(point),
+		"line":           "LINESTRING (-1 2, 5 2)",
+	})
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	tests := []struct {
+		name     string
+		stdin    string
+		args     []string
+		expected string
+	}{
+		{"geojson within wkt", "", []string{"eval", "--pred", "within", path("point.geojson"), path("square.wkt")}, "within  true\n"},
+		{"predicate name", "", []string{"eval", "--pred", "Covered_By", path("point.geojson"), path("square.wkt")}, "coveredby  true\n"},
+		{"wkb", "", []string{"eval", "--pred", "contains", path("square.wkt"), path("point.wkb")}, "contains  true\n"},
+		{"hex wkb", "", []string{"eval", "--pred", "intersects", path("point.hex"), path("square.wkt")}, "intersects  true\n"},
+		{"sniffed wkt", "", []string{"eval", "--pred", "crosses", path("line"), path("square.wkt")}, "crosses  true\n"},
+		{"feature collection", "", []string{"eval", "--pred", "within", path("points.geojson"), path("square.wkt")}, "within  false\n"},
+		{"stdin", "POINT (9 9)", []string{"eval", "--pred", "disjoint", "-", path("square.wkt")}, "disjoint  true\n"},
+		{"relate", "", []string{"eval", "--pred", "touches", "--relate", path("line"), path("square.wkt")}, "touches  false\nrelate   101FF0212\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, out, errOut := runArgs(tt.stdin, tt.args...)
+			if code != 0 || out != tt.expected {
+				t.Errorf("orbpred %v = %d, %q, expected 0, %q (stderr %q)", tt.args, code, out, tt.expected, errOut)
+			}
+		})
+	}
+}
+
+func TestEvalAll(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.wkt": "POLYGON ((0 0, 2 0, 2 2, 0 2, 0 0))",
+		"b.wkt": "POLYGON ((1 1, 3 1, 3 3, 1 3, 1 1))",
+	})
+	args := []string{"eval", "--format", "json", "--relate", filepath.Join(dir, "a.wkt"), filepath.Join(dir, "b.wkt")}
+	code, out, errOut := runArgs("", args...)
+	if code != 0 {
+		t.Fatalf("orbpred %v = %d, stderr %q", args, code, errOut)
+	}
+
+	var result evalResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out)
+	}
+	if len(result.Predicates) != len(allPredicates) {
+		t.Errorf("got %d predicates, expected %d", len(result.Predicates), len(allPredicates))
+	}
+	for name, want := range map[string]bool{"intersects": true, "overlaps": true, "within": false, "touches": false} {
+		if result.Predicates[name] != want {
+			t.Errorf("%s = %t, expected %t", name, result.Predicates[name], want)
+		}
+	}
+	if result.Relate != "212101212" {
+		t.Errorf("relate = %s, expected 212101212", result.Relate)
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.wkt":    "POINT (1 1)",
+		"bad.wkt":  "POLYGON ((0 0, 1 0",
+		"open.wkt": "POLYGON ((0 0, 1 0, 1 1))",
+	})
+	a := filepath.Join(dir, "a.wkt")
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected int
+	}{
+		{"no command", nil, 2},
+		{"unknown command", []string{"frobnicate"}, 2},
+		{"one argument", []string{"eval", a}, 2},
+		{"unknown predicate", []string{"eval", "--pred", "near", a, a}, 2},
+		{"unknown format", []string{"eval", "--format", "xml", a, a}, 2},
+		{"both stdin", []string{"eval", "-", "-"}, 2},
+		{"missing file", []string{"eval", a, filepath.Join(dir, "missing.wkt")}, 1},
+		{"bad wkt", []string{"eval", a, filepath.Join(dir, "bad.wkt")}, 1},
+		{"unclosed ring", []string{"eval", a, filepath.Join(dir, "open.wkt")}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code, _, _ := runArgs("", tt.args...); code != tt.expected {
+				t.Errorf("orbpred %v = %d, expected %d", tt.args, code, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/encoding/wkb"
+
+	predicates "github.com/tingold/orb-predicates"
+	"github.com/tingold/orb-predicates/geojsonpred"
+)
+
+// readGeometry reads the geometry in the named file, or in stdin if name is "-"
+func readGeometry(name string, stdin io.Reader) (orb.Geometry, error) {
+	var data []byte
+	var err error
+	if name == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := parseGeometry(data, filepath.Ext(name))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if err := predicates.CheckGeometry(g); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return g, nil
+}
+
+// parseGeometry decodes data as GeoJSON, WKB, hex encoded WKB or WKT. The
+// format is taken from the file extension ext, or else from the content.
+func parseGeometry(data []byte, ext string) (orb.Geometry, error) {
+	switch strings.ToLower(ext) {
+	case ".geojson", ".json":
+		return geojsonpred.DecodeGeometry(data)
+	case ".wkb":
+		return parseWKB(data)
+	case ".wkt":
+		return predicates.ParseWKT(string(bytes.TrimSpace(data)))
+	}
+
+	text := bytes.TrimSpace(data)
+	switch {
+	case len(data) > 0 && (data[0] == 0 || data[0] == 1):
+		// The first byte of WKB is its byte order
+		return wkb.Unmarshal(data)
+	case len(text) == 0:
+		return nil, errors.New("no geometry")
+	case text[0] == '{':
+		return geojsonpred.DecodeGeometry(text)
+	case isHex(text):
+		return parseWKB(text)
+	}
+	return predicates.ParseWKT(string(text))
+}
+
+// parseWKB decodes binary or hex encoded WKB
+func parseWKB(data []byte) (orb.Geometry, error) {
+	if text := bytes.TrimSpace(data); isHex(text) {
+		decoded := make([]byte, hex.DecodedLen(len(text)))
+		if _, err := hex.Decode(decoded, text); err != nil {
+			return nil, err
+		}
+		data = decoded
+	}
+	return wkb.Unmarshal(data)
+}
+
+// isHex checks if text is a non-empty, even length run of hex digits
+func isHex(text []byte) bool {
+	if len(text) == 0 || len(text)%2 != 0 {
+		return false
+	}
+	for _, c := range text {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
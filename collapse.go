@@ -0,0 +1,238 @@
+package predicates
+
+import (
+	"math"
+	"sort"
+
+	"github.com/paulmach/orb"
+)
+
+// collapseAreas replaces areal geometries that enclose no area with the lines
+// or point they have collapsed to, so that they are evaluated with their true
+// dimension. A Bound of zero width or height becomes a LineString and a
+// point-sized Bound a Point, as does a Circle of zero radius. A Ring or
+// Polygon whose shell only traces back over itself becomes the lines it traces. Other geometries are returned as
+// they are, and Collections are rebuilt with their parts collapsed.
+func collapseAreas(g orb.Geometry) orb.Geometry {
+	switch geom := g.(type) {
+	case orb.Bound:
+		if c, ok := collapseBound(geom); ok {
+			return c
+		}
+	case orb.Ring:
+		if c, ok := collapsePolygon(orb.Polygon{geom}); ok {
+			return c
+		}
+	case orb.Polygon:
+		if c, ok := collapsePolygon(geom); ok {
+			return c
+		}
+	case Circle:
+		if geom.Radius < epsilon {
+			return geom.Center
+		}
+	case orb.MultiPolygon:
+		var kept orb.MultiPolygon
+		var collapsed orb.Collection
+		for _, poly := range geom {
+			if c, ok := collapsePolygon(poly); ok {
+				collapsed = append(collapsed, c)
+			} else {
+				kept = append(kept, poly)
+			}
+		}
+		switch {
+		case len(collapsed) == 0:
+			return g
+		case len(kept) > 0:
+			return append(orb.Collection{kept}, collapsed...)
+		case len(collapsed) == 1:
+			return collapsed[0]
+		}
+		return collapsed
+	case orb.Collection:
+		parts := make(orb.Collection, len(geom))
+		for i, part := range geom {
+			parts[i] = collapseAreas(part)
+		}
+		return parts
+	}
+	return g
+}
+
+// collapseBound returns the line or point a Bound of zero width or height
+// covers. It is false when the Bound encloses an area.
+func collapseBound(b orb.Bound) (orb.Geometry, bool) {
+	if b.IsEmpty() {
+		return nil, false
+	}
+	zeroWidth := b.Max[0]-b.Min[0] < epsilon
+	zeroHeight := b.Max[1]-b.Min[1] < epsilon
+	switch {
+	case zeroWidth && zeroHeight:
+		return b.Min, true
+	case zeroWidth || zeroHeight:
+		return orb.LineString{b.Min, b.Max}, true
+	}
+	return nil, false
+}
+
+// collapsePolygon returns the lines or point the shell of a polygon has
+// collapsed to. It is false when the shell encloses an area.
+func collapsePolygon(poly orb.Polygon) (orb.Geometry, bool) {
+	if len(poly) == 0 || len(poly[0]) == 0 {
+		return nil, false
+	}
+	// A shell with a clear area cannot have collapsed, which keeps the
+	// exact check off the common path
+	if shellHasArea(poly[0]) {
+		return nil, false
+	}
+	return collapsedLinework(poly[0])
+}
+
+// shellHasArea reports whether a ring clearly encloses an area. The signed
+// area is compared with the sum of the unsigned triangle areas, so the test
+// does not depend on scale. A ring that fails it may still enclose an area;
+// collapsedLinework decides exactly.
+func shellHasArea(shell orb.Ring) bool {
+	if len(shell) == 0 {
+		return false
+	}
+	var area, total float64
+	for i := 2; i < len(shell); i++ {
+		c := cross2D(shell[0], shell[i-1], shell[i])
+		area += c
+		total += math.Abs(c)
+	}
+	return math.Abs(area) > epsilon*total
+}
+
+// linePiece is a piece of a ring edge between two nodes, with the number of
+// times the ring runs along it from a to b less the times it runs back
+type linePiece struct {
+	a, b  orb.Point
+	count int
+}
+
+// collapsedLinework splits the edges of a ring at its vertices and checks
+// that the ring runs back over every piece as often as it runs along it, so
+// that no side of any piece is inside the ring. The pieces are then joined
+// into lines, or the ring's first vertex is returned if it has no length.
+func collapsedLinework(ring orb.Ring) (orb.Geometry, bool) {
+	var pieces []linePiece
+	var params []float64
+	for i := 1; i < len(ring); i++ {
+		p, q := ring[i-1], ring[i]
+		if pointsEqual(p, q) {
+			continue
+		}
+		params = append(params[:0], 0, 1)
+		for _, v := range ring {
+			if pointOnSegmentInterior(v, p, q) {
+				params = append(params, segmentParam(v, p, q))
+			}
+		}
+		sort.Float64s(params)
+
+		for k := 1; k < len(params); k++ {
+			a, b := segmentPoint(p, q, params[k-1]), segmentPoint(p, q, params[k])
+			if pointsEqual(a, b) {
+				continue
+			}
+			pieces = addLinePiece(pieces, a, b)
+		}
+	}
+
+	for _, pc := range pieces {
+		if pc.count != 0 {
+			return nil, false
+		}
+	}
+	if len(pieces) == 0 {
+		return ring[0], true
+	}
+	return joinLinePieces(pieces), true
+}
+
+// addLinePiece counts one run along the piece from a to b
+func addLinePiece(pieces []linePiece, a, b orb.Point) []linePiece {
+	for i, pc := range pieces {
+		if pointsEqual(pc.a, a) && pointsEqual(pc.b, b) {
+			pieces[i].count++
+			return pieces
+		}
+		if pointsEqual(pc.a, b) && pointsEqual(pc.b, a) {
+			pieces[i].count--
+			return pieces
+		}
+	}
+	return append(pieces, linePiece{a: a, b: b, count: 1})
+}
+
+// joinLinePieces joins pieces into lines that only end where the linework
+// stops or branches. Loops with no such node become closed lines.
+func joinLinePieces(pieces []linePiece) orb.Geometry {
+	degree := func(p orb.Point) int {
+		n := 0
+		for _, pc := range pieces {
+			if pointsEqual(pc.a, p) {
+				n++
+			}
+			if pointsEqual(pc.b, p) {
+				n++
+			}
+		}
+		return n
+	}
+
+	used := make([]bool, len(pieces))
+	extend := func(ls orb.LineString) orb.LineString {
+		for {
+			end := ls[len(ls)-1]
+			if degree(end) != 2 {
+				return ls
+			}
+			next := -1
+			for i, pc := range pieces {
+				if !used[i] && (pointsEqual(pc.a, end) || pointsEqual(pc.b, end)) {
+					next = i
+					break
+				}
+			}
+			if next < 0 {
+				return ls
+			}
+			used[next] = true
+			if pointsEqual(pieces[next].a, end) {
+				ls = append(ls, pieces[next].b)
+			} else {
+				ls = append(ls, pieces[next].a)
+			}
+		}
+	}
+
+	var lines orb.MultiLineString
+	// Start from the nodes where lines end or branch, then pick up any loops
+	for _, loops := range []bool{false, true} {
+		for i, pc := range pieces {
+			if used[i] {
+				continue
+			}
+			a, b := pc.a, pc.b
+			if !loops && degree(a) == 2 {
+				if degree(b) == 2 {
+					continue
+				}
+				a, b = b, a
+			}
+			used[i] = true
+			lines = append(lines, extend(orb.LineString{a, b}))
+		}
+	}
+
+	if len(lines) == 1 {
+		return lines[0]
+	}
+	return lines
+}
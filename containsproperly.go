@@ -0,0 +1,12 @@
+package predicates
+
+import (
+	"github.com/paulmach/orb"
+)
+
+// ContainsProperly returns true if every point of geometry b lies in the
+// interior of geometry a. Unlike Contains, b may not touch the boundary of a
+// anywhere, which makes this the cheapest containment test to evaluate.
+func ContainsProperly(a, b orb.Geometry) bool {
+	return holds(a, b, PredContainsProperly)
+}
@@ -0,0 +1,433 @@
+package predicates
+
+import (
+	"math"
+
+	"github.com/paulmach/orb"
+)
+
+// geometryMarker lets the curved types satisfy orb.Geometry, whose method set
+// includes an unexported marker. Its methods are never called.
+type geometryMarker struct{ orb.Geometry }
+
+// Circle is the closed disk of points within Radius of Center. It is an area
+// evaluated exactly by every predicate, so a service area does not need to be
+// approximated by a polygon. A Circle with a zero Radius is the point Center,
+// and one with a negative or NaN Radius is empty.
+type Circle struct {
+	geometryMarker
+	Center orb.Point
+	Radius float64
+}
+
+// GeoJSONType returns "Circle"; GeoJSON has no circle type
+func (c Circle) GeoJSONType() string { return "Circle" }
+
+// Dimensions returns 2 because a Circle is an area
+func (c Circle) Dimensions() int { return 2 }
+
+// Bound returns the square around the circle
+func (c Circle) Bound() orb.Bound {
+	return orb.Bound{
+		Min: orb.Point{c.Center[0] - c.Radius, c.Center[1] - c.Radius},
+		Max: orb.Point{c.Center[0] + c.Radius, c.Center[1] + c.Radius},
+	}
+}
+
+// boundaryString returns the circle as a closed CircularString
+func (c Circle) boundaryString() CircularString {
+	east := orb.Point{c.Center[0] + c.Radius, c.Center[1]}
+	west := orb.Point{c.Center[0] - c.Radius, c.Center[1]}
+	return CircularString{Points: []orb.Point{east, west, east}}
+}
+
+// locate returns the location of p in the disk
+func (c Circle) locate(p orb.Point) int {
+	d := math.Hypot(p[0]-c.Center[0], p[1]-c.Center[1])
+	switch {
+	case math.Abs(d-c.Radius) <= epsilon:
+		return locBoundary
+	case d < c.Radius:
+		return locInterior
+	}
+	return locExterior
+}
+
+// CircularString is a line made of circular arcs, as in SQL/MM and WKT
+// CIRCULARSTRING. Every arc runs from a point through the next one to the one
+// after, so the arcs share end points and there is an odd number of Points.
+// An arc whose three points lie on a line is the straight segment between its
+// end points, and an arc that ends where it starts is the full circle with
+// its middle point opposite.
+type CircularString struct {
+	geometryMarker
+	Points []orb.Point
+}
+
+// GeoJSONType returns "CircularString"; GeoJSON has no curved types
+func (cs CircularString) GeoJSONType() string { return "CircularString" }
+
+// Dimensions returns 1 because a CircularString is a line
+func (cs CircularString) Dimensions() int { return 1 }
+
+// Bound returns the bounding box of the arcs, including the points where
+// they bulge past their end points
+func (cs CircularString) Bound() orb.Bound {
+	edges := cs.edges()
+	if len(edges) == 0 {
+		if len(cs.Points) == 0 {
+			return orb.Bound{Min: orb.Point{1, 1}, Max: orb.Point{-1, -1}}
+		}
+		return orb.MultiPoint(cs.Points).Bound()
+	}
+	bound := edges[0].bound()
+	for _, e := range edges[1:] {
+		bound = bound.Union(e.bound())
+	}
+	return bound
+}
+
+// ends returns the first and last point of the string
+func (cs CircularString) ends() orb.LineString {
+	return orb.LineString{cs.Points[0], cs.Points[len(cs.Points)-1]}
+}
+
+// edges breaks the string into its arcs. A full circle is split into two
+// half arcs so that every edge has distinct end points.
+func (cs CircularString) edges() []relateEdge {
+	var edges []relateEdge
+	for i := 2; i < len(cs.Points); i += 2 {
+		p, m, q := cs.Points[i-2], cs.Points[i-1], cs.Points[i]
+		switch {
+		case pointsEqual(p, q):
+			if pointsEqual(p, m) {
+				continue
+			}
+			centre := orb.Point{(p[0] + m[0]) / 2, (p[1] + m[1]) / 2}
+			edges = append(edges, newArcEdge(centre, p, m, true), newArcEdge(centre, m, p, true))
+		case sign(cross2D(p, m, q)) == 0:
+			edges = append(edges, relateEdge{a: p, b: q})
+		default:
+			ccw := cross2D(p, m, q) > 0
+			edges = append(edges, newArcEdge(circumcentre(p, m, q), p, q, ccw))
+		}
+	}
+	return edges
+}
+
+// circumcentre returns the centre of the circle through three points that
+// are not on a line
+func circumcentre(a, b, c orb.Point) orb.Point {
+	bx, by := b[0]-a[0], b[1]-a[1]
+	cx, cy := c[0]-a[0], c[1]-a[1]
+	d := 2 * (bx*cy - by*cx)
+	ux := (cy*(bx*bx+by*by) - by*(cx*cx+cy*cy)) / d
+	uy := (bx*(cx*cx+cy*cy) - cx*(bx*bx+by*by)) / d
+	return orb.Point{a[0] + ux, a[1] + uy}
+}
+
+// relateEdge is an edge the relate engine splits and locates: the straight
+// segment from a to b, or the arc from a to b when curved is set
+type relateEdge struct {
+	a, b   orb.Point
+	curved bool
+	arc    arc
+}
+
+// arc is a circular arc that starts at angle start around centre and sweeps
+// through sweep radians, counter-clockwise when sweep is positive
+type arc struct {
+	centre orb.Point
+	radius float64
+	start  float64
+	sweep  float64
+}
+
+// newArcEdge returns the arc from a to b around centre in the given direction
+func newArcEdge(centre, a, b orb.Point, ccw bool) relateEdge {
+	start := math.Atan2(a[1]-centre[1], a[0]-centre[0])
+	end := math.Atan2(b[1]-centre[1], b[0]-centre[0])
+	sweep := end - start
+	if ccw {
+		for sweep <= 0 {
+			sweep += 2 * math.Pi
+		}
+	} else {
+		for sweep >= 0 {
+			sweep -= 2 * math.Pi
+		}
+	}
+	radius := math.Hypot(a[0]-centre[0], a[1]-centre[1])
+	return relateEdge{a: a, b: b, curved: true, arc: arc{centre, radius, start, sweep}}
+}
+
+// point returns the point at fraction t along the edge
+func (e relateEdge) point(t float64) orb.Point {
+	if !e.curved || t == 0 || t == 1 {
+		return segmentPoint(e.a, e.b, t)
+	}
+	angle := e.arc.start + t*e.arc.sweep
+	return orb.Point{
+		e.arc.centre[0] + e.arc.radius*math.Cos(angle),
+		e.arc.centre[1] + e.arc.radius*math.Sin(angle),
+	}
+}
+
+// tangent returns the direction of the edge at fraction t
+func (e relateEdge) tangent(t float64) orb.Point {
+	if !e.curved {
+		return orb.Point{e.b[0] - e.a[0], e.b[1] - e.a[1]}
+	}
+	angle := e.arc.start + t*e.arc.sweep
+	if e.arc.sweep > 0 {
+		return orb.Point{-math.Sin(angle), math.Cos(angle)}
+	}
+	return orb.Point{math.Sin(angle), -math.Cos(angle)}
+}
+
+// contains checks if p lies on the edge, end points included
+func (e relateEdge) contains(p orb.Point) bool {
+	if !e.curved {
+		return pointOnSegment(p, e.a, e.b)
+	}
+	if pointsEqual(p, e.a) || pointsEqual(p, e.b) {
+		return true
+	}
+	d := math.Hypot(p[0]-e.arc.centre[0], p[1]-e.arc.centre[1])
+	if math.Abs(d-e.arc.radius) > epsilon {
+		return false
+	}
+	return e.arc.angleParam(p) <= 1
+}
+
+// param returns the fraction along the edge of a point on it
+func (e relateEdge) param(p orb.Point) float64 {
+	if !e.curved {
+		return segmentParam(p, e.a, e.b)
+	}
+	switch {
+	case pointsEqual(p, e.a):
+		return 0
+	case pointsEqual(p, e.b):
+		return 1
+	}
+	return math.Min(1, e.arc.angleParam(p))
+}
+
+// angleParam returns how far round the arc the direction of p lies, as a
+// fraction of the sweep; directions outside the arc give more than 1
+func (a arc) angleParam(p orb.Point) float64 {
+	delta := math.Atan2(p[1]-a.centre[1], p[0]-a.centre[0]) - a.start
+	if a.sweep < 0 {
+		delta = -delta
+	}
+	delta = math.Mod(delta, 2*math.Pi)
+	if delta < 0 {
+		delta += 2 * math.Pi
+	}
+	// Directions just before the start are at the start
+	if 2*math.Pi-delta < epsilon/math.Max(a.radius, epsilon) {
+		delta = 0
+	}
+	return delta / math.Abs(a.sweep)
+}
+
+// bound returns the bounding box of the edge
+func (e relateEdge) bound() orb.Bound {
+	b := orb.Bound{
+		Min: orb.Point{min(e.a[0], e.b[0]), min(e.a[1], e.b[1])},
+		Max: orb.Point{max(e.a[0], e.b[0]), max(e.a[1], e.b[1])},
+	}
+	if !e.curved {
+		return b
+	}
+	// The arc reaches the circle's extremes at the quarter angles it sweeps over
+	for k := 0; k < 4; k++ {
+		angle := float64(k) * math.Pi / 2
+		extreme := orb.Point{
+			e.arc.centre[0] + e.arc.radius*math.Cos(angle),
+			e.arc.centre[1] + e.arc.radius*math.Sin(angle),
+		}
+		if e.arc.angleParam(extreme) <= 1 {
+			b = b.Extend(extreme)
+		}
+	}
+	return b
+}
+
+// edgeIntersections appends the points where a curved edge meets another
+// edge. Straight segments are handled by the caller. Arcs on the same
+// circle meet along their overlap, whose end points are vertices already.
+func edgeIntersections(points []orb.Point, e, o relateEdge) []orb.Point {
+	if !e.curved {
+		e, o = o, e
+	}
+	var candidates [2]orb.Point
+	var n int
+	if o.curved {
+		n = circleCircleIntersections(e.arc.centre, e.arc.radius, o.arc.centre, o.arc.radius, &candidates)
+	} else {
+		n = circleLineIntersections(e.arc.centre, e.arc.radius, o.a, o.b, &candidates)
+	}
+	for _, p := range candidates[:n] {
+		if e.contains(p) && o.contains(p) {
+			points = append(points, p)
+		}
+	}
+	return points
+}
+
+// circleLineIntersections finds the points where the circle meets the line
+// through a and b. A line within epsilon of touching the circle touches it
+// at the foot of the perpendicular from the centre.
+func circleLineIntersections(centre orb.Point, r float64, a, b orb.Point, out *[2]orb.Point) int {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	length := math.Hypot(dx, dy)
+	if length < epsilon {
+		return 0
+	}
+	ux, uy := dx/length, dy/length
+	t := (centre[0]-a[0])*ux + (centre[1]-a[1])*uy
+	foot := orb.Point{a[0] + t*ux, a[1] + t*uy}
+	dist := math.Hypot(foot[0]-centre[0], foot[1]-centre[1])
+
+	switch {
+	case dist > r+epsilon:
+		return 0
+	case math.Abs(dist-r) <= epsilon:
+		out[0] = foot
+		return 1
+	}
+	h := math.Sqrt(r*r - dist*dist)
+	out[0] = orb.Point{foot[0] - h*ux, foot[1] - h*uy}
+	out[1] = orb.Point{foot[0] + h*ux, foot[1] + h*uy}
+	return 2
+}
+
+// circleCircleIntersections finds the points where two circles meet.
+// Circles within epsilon of touching touch at one point, and circles with
+// the same centre share no isolated points.
+func circleCircleIntersections(c1 orb.Point, r1 float64, c2 orb.Point, r2 float64, out *[2]orb.Point) int {
+	dx, dy := c2[0]-c1[0], c2[1]-c1[1]
+	d := math.Hypot(dx, dy)
+	if d < epsilon || d > r1+r2+epsilon || d < math.Abs(r1-r2)-epsilon {
+		return 0
+	}
+	ux, uy := dx/d, dy/d
+	along := (d*d + r1*r1 - r2*r2) / (2 * d)
+	base := orb.Point{c1[0] + along*ux, c1[1] + along*uy}
+	if math.Abs(d-(r1+r2)) <= epsilon || math.Abs(d-math.Abs(r1-r2)) <= epsilon {
+		out[0] = base
+		return 1
+	}
+	h := math.Sqrt(math.Max(r1*r1-along*along, 0))
+	out[0] = orb.Point{base[0] - h*uy, base[1] + h*ux}
+	out[1] = orb.Point{base[0] + h*uy, base[1] - h*ux}
+	return 2
+}
+
+// circleMatrix computes the DE-9IM matrix directly when a point or circle is
+// related to a circle, which needs no noding. It is false for other inputs.
+func circleMatrix(a, b orb.Geometry) (intersectionMatrix, bool) {
+	ca, aCircle := a.(Circle)
+	cb, bCircle := b.(Circle)
+	switch {
+	case aCircle && bCircle:
+		return circleCircleMatrix(ca, cb), true
+	case aCircle:
+		if p, ok := b.(orb.Point); ok {
+			return transpose(pointCircleMatrix(p, ca)), true
+		}
+	case bCircle:
+		if p, ok := a.(orb.Point); ok {
+			return pointCircleMatrix(p, cb), true
+		}
+	}
+	return intersectionMatrix{}, false
+}
+
+// emptyMatrix returns a matrix in which only the exteriors meet
+func emptyMatrix() intersectionMatrix {
+	var im intersectionMatrix
+	for i := range im {
+		for j := range im[i] {
+			im[i][j] = dimFalse
+		}
+	}
+	im.set(locExterior, locExterior, 2)
+	return im
+}
+
+// transpose swaps the roles of a and b in a matrix
+func transpose(im intersectionMatrix) intersectionMatrix {
+	var t intersectionMatrix
+	for i := range im {
+		for j := range im[i] {
+			t[j][i] = im[i][j]
+		}
+	}
+	return t
+}
+
+// pointCircleMatrix relates a point to a circle
+func pointCircleMatrix(p orb.Point, c Circle) intersectionMatrix {
+	im := emptyMatrix()
+	im.set(locInterior, c.locate(p), 0)
+	im.set(locExterior, locInterior, 2)
+	im.set(locExterior, locBoundary, 1)
+	return im
+}
+
+// circleCircleMatrix relates two circles from the distance between their
+// centres and their radii
+func circleCircleMatrix(a, b Circle) intersectionMatrix {
+	im := emptyMatrix()
+	d := math.Hypot(b.Center[0]-a.Center[0], b.Center[1]-a.Center[1])
+	outside := func(im *intersectionMatrix) {
+		im.set(locInterior, locExterior, 2)
+		im.set(locBoundary, locExterior, 1)
+	}
+	inside := func(im *intersectionMatrix) {
+		im.set(locExterior, locInterior, 2)
+		im.set(locExterior, locBoundary, 1)
+	}
+
+	switch {
+	case d <= epsilon && math.Abs(a.Radius-b.Radius) <= epsilon:
+		// Equal circles
+		im.set(locInterior, locInterior, 2)
+		im.set(locBoundary, locBoundary, 1)
+	case d >= a.Radius+b.Radius-epsilon:
+		// Apart, or touching from outside
+		outside(&im)
+		inside(&im)
+		if d <= a.Radius+b.Radius+epsilon {
+			im.set(locBoundary, locBoundary, 0)
+		}
+	case d <= a.Radius-b.Radius+epsilon:
+		// b inside a, perhaps touching it from within
+		im.set(locInterior, locInterior, 2)
+		im.set(locInterior, locBoundary, 1)
+		outside(&im)
+		if d >= a.Radius-b.Radius-epsilon {
+			im.set(locBoundary, locBoundary, 0)
+		}
+	case d <= b.Radius-a.Radius+epsilon:
+		// a inside b, perhaps touching it from within
+		im.set(locInterior, locInterior, 2)
+		im.set(locBoundary, locInterior, 1)
+		inside(&im)
+		if d >= b.Radius-a.Radius-epsilon {
+			im.set(locBoundary, locBoundary, 0)
+		}
+	default:
+		// The boundaries cross at two points
+		im.set(locInterior, locInterior, 2)
+		im.set(locInterior, locBoundary, 1)
+		im.set(locBoundary, locInterior, 1)
+		im.set(locBoundary, locBoundary, 0)
+		outside(&im)
+		inside(&im)
+	}
+	return im
+}
@@ -0,0 +1,230 @@
+package predicates
+
+import (
+	"math"
+	"strings"
+
+	"github.com/paulmach/orb"
+)
+
+// Direction is a set of the nine tiles the envelope of a reference geometry
+// divides the plane into, as a bitmask: the envelope itself (DirSame) and
+// the eight cardinal directions around it
+type Direction uint16
+
+// The tiles, row by row from north west to south east
+const (
+	DirNW Direction = 1 << iota
+	DirN
+	DirNE
+	DirW
+	DirSame
+	DirE
+	DirSW
+	DirS
+	DirSE
+
+	// The rows and columns of tiles, for geometries partly in a direction
+	DirNorthward = DirNW | DirN | DirNE
+	DirSouthward = DirSW | DirS | DirSE
+	DirWestward  = DirNW | DirW | DirSW
+	DirEastward  = DirNE | DirE | DirSE
+)
+
+var directionNames = [...]string{"nw", "n", "ne", "w", "same", "e", "sw", "s", "se"}
+
+// String returns the names of the tiles in the set joined by "|"
+func (d Direction) String() string {
+	if d == 0 {
+		return "none"
+	}
+	var names []string
+	for i, name := range directionNames {
+		if d&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, "|")
+}
+
+// Matrix returns the set as a direction-relation matrix, with rows from
+// north to south and columns from west to east
+func (d Direction) Matrix() [3][3]bool {
+	var m [3][3]bool
+	for i := range 9 {
+		m[i/3][i%3] = d&(1<<i) != 0
+	}
+	return m
+}
+
+// DirectionMatrix returns the tiles of the envelope of b that a lies in,
+// the projection-based direction-relation matrix of a to b. The tiles are
+// closed, and a part of a is in a tile when the part of it in the tile has
+// its dimension: a point on the line between two tiles is in both, but a
+// polygon that only touches a tile is not in it. DirectionMatrix(a, b) ==
+// DirN when a is strictly north of b and no wider than it.
+//
+// When the envelope of b has no width or height, the middle column or row
+// is the line it spans, and the tiles beside it stop short of it. A part of
+// a is in such a line or point tile when its interior meets it, so a line
+// that crosses north of a point is in DirN as well as DirNW and DirNE.
+func DirectionMatrix(a, b orb.Geometry) Direction {
+	if IsEmpty(a) || IsEmpty(b) {
+		return 0
+	}
+	a = normalize(a)
+	ea, eb := Envelope(a), Envelope(normalize(b))
+	outer := ea.Union(eb)
+	flatX, flatY := eb.Max[0]-eb.Min[0] < epsilon, eb.Max[1]-eb.Min[1] < epsilon
+	cols := [3]directionSpan{
+		{lo: outer.Min[0], hi: eb.Min[0], openHi: flatX},
+		{lo: eb.Min[0], hi: eb.Max[0]},
+		{lo: eb.Max[0], hi: outer.Max[0], openLo: flatX},
+	}
+	rows := [3]directionSpan{
+		{lo: eb.Max[1], hi: outer.Max[1], openLo: flatY},
+		{lo: eb.Min[1], hi: eb.Max[1]},
+		{lo: outer.Min[1], hi: eb.Min[1], openHi: flatY},
+	}
+
+	var d Direction
+	var add func(g orb.Geometry)
+	add = func(g orb.Geometry) {
+		if c, ok := g.(orb.Collection); ok {
+			for _, part := range c {
+				add(part)
+			}
+			return
+		}
+		env, dim := Envelope(g), normalizedDimension(g)
+		for i := range 9 {
+			col, row := cols[i%3], rows[i/3]
+			if d&(1<<i) != 0 || col.empty() || row.empty() {
+				continue
+			}
+			tile := orb.Bound{Min: orb.Point{col.lo, row.lo}, Max: orb.Point{col.hi, row.hi}}
+			if !boundsOverlap(env, tile) {
+				continue
+			}
+			// The interior of g against the tile, and against the edges
+			// of the tile that belong to it
+			in := relate(g, normalize(tile))[locInterior][locInterior]
+			if edges := tileEdges(col, row); edges != nil {
+				in = max(in, relate(g, edges)[locInterior][locInterior])
+			}
+			onReference := (flatX && i%3 == 1) || (flatY && i/3 == 1)
+			if in == dim || (onReference && in >= 0) {
+				d |= 1 << i
+			}
+		}
+	}
+	add(a)
+	return d
+}
+
+// directionSpan is the extent of a row or column of tiles along one axis.
+// It is open at an end where it meets a reference of zero width or height.
+type directionSpan struct {
+	lo, hi         float64
+	openLo, openHi bool
+}
+
+// empty checks if the span is open and has no length, which leaves nothing
+func (s directionSpan) empty() bool {
+	return (s.openLo || s.openHi) && s.hi-s.lo < epsilon
+}
+
+// tileEdges returns the parts of the boundary of the tile of col and row
+// that are in the tile: its closed sides, or the closed ends of a line
+// tile. Sides are separate lines so that the corners between two of them
+// are in their interior. It is nil when the tile is a point or has no
+// closed edges.
+func tileEdges(col, row directionSpan) orb.Geometry {
+	flatX, flatY := col.hi-col.lo < epsilon, row.hi-row.lo < epsilon
+	var ends orb.MultiPoint
+	switch {
+	case flatX && flatY:
+		return nil
+	case flatY:
+		if !col.openLo {
+			ends = append(ends, orb.Point{col.lo, row.lo})
+		}
+		if !col.openHi {
+			ends = append(ends, orb.Point{col.hi, row.lo})
+		}
+	case flatX:
+		if !row.openLo {
+			ends = append(ends, orb.Point{col.lo, row.lo})
+		}
+		if !row.openHi {
+			ends = append(ends, orb.Point{col.lo, row.hi})
+		}
+	default:
+		corners := [5]orb.Point{{col.lo, row.lo}, {col.hi, row.lo}, {col.hi, row.hi}, {col.lo, row.hi}, {col.lo, row.lo}}
+		var sides orb.MultiLineString
+		for k, open := range [4]bool{row.openLo, col.openHi, row.openHi, col.openLo} {
+			if !open {
+				sides = append(sides, orb.LineString{corners[k], corners[k+1]})
+			}
+		}
+		return sides
+	}
+	if len(ends) == 0 {
+		return nil
+	}
+	return ends
+}
+
+// NorthOf checks if a is strictly north of (above) b: every part of a is
+// north of the envelope of b, at most touching its northern edge
+func NorthOf(a, b orb.Geometry) bool {
+	return strictly(a, b, DirNorthward, func(ea, eb orb.Bound) float64 { return ea.Min[1] - eb.Max[1] })
+}
+
+// SouthOf checks if a is strictly south of (below) b, see NorthOf
+func SouthOf(a, b orb.Geometry) bool {
+	return strictly(a, b, DirSouthward, func(ea, eb orb.Bound) float64 { return eb.Min[1] - ea.Max[1] })
+}
+
+// EastOf checks if a is strictly east of (right of) b, see NorthOf
+func EastOf(a, b orb.Geometry) bool {
+	return strictly(a, b, DirEastward, func(ea, eb orb.Bound) float64 { return ea.Min[0] - eb.Max[0] })
+}
+
+// WestOf checks if a is strictly west of (left of) b, see NorthOf
+func WestOf(a, b orb.Geometry) bool {
+	return strictly(a, b, DirWestward, func(ea, eb orb.Bound) float64 { return eb.Min[0] - ea.Max[0] })
+}
+
+// strictly checks that a lies only in the tiles of b in side. gap is how
+// far a's envelope is past the edge of b's on that side; only envelopes
+// that meet at the edge need the tiles to tell.
+func strictly(a, b orb.Geometry, side Direction, gap func(ea, eb orb.Bound) float64) bool {
+	if IsEmpty(a) || IsEmpty(b) {
+		return false
+	}
+	switch g := gap(Envelope(a), Envelope(b)); {
+	case g > epsilon:
+		return true
+	case g < -epsilon:
+		return false
+	}
+	return DirectionMatrix(a, b)&^side == 0
+}
+
+// ConeDirection returns the cone-based direction of a from b: the tile of
+// the eight 45° cones around the centre of the envelope of b that the centre
+// of the envelope of a is in, or DirSame when the centres are equal. A
+// centre on the line between two cones is in the one clockwise from it.
+func ConeDirection(a, b orb.Geometry) Direction {
+	if IsEmpty(a) || IsEmpty(b) {
+		return 0
+	}
+	ca, cb := Envelope(a).Center(), Envelope(b).Center()
+	if pointsEqual(ca, cb) {
+		return DirSame
+	}
+	cones := [8]Direction{DirE, DirNE, DirN, DirNW, DirW, DirSW, DirS, DirSE}
+	angle := math.Atan2(ca[1]-cb[1], ca[0]-cb[0])
+	return cones[int(math.Ceil(angle/(math.Pi/4)-0.5)+8)%8]
+}
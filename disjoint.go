@@ -5,7 +5,8 @@ import (
 )
 
 // Disjoint returns true if the geometries have no points in common.
-// This is the complement of Intersects.
+// This is the complement of Intersects, so an empty geometry is disjoint
+// from everything, including another empty geometry.
 func Disjoint(a, b orb.Geometry) bool {
-	return !Intersects(a, b)
+	return holds(a, b, PredDisjoint)
 }
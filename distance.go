@@ -0,0 +1,157 @@
+package predicates
+
+import (
+	"math"
+
+	"github.com/paulmach/orb"
+)
+
+// Distance returns the least distance between a and b in the plane, which
+// is 0 when they intersect. Circles and CircularStrings are measured along
+// their arcs, and areas to their boundaries. An empty geometry is infinitely
+// far from any other, and types that CheckGeometry rejects return its error.
+func Distance(a, b orb.Geometry) (float64, error) {
+	if err := CheckGeometry(a); err != nil {
+		return 0, err
+	}
+	if err := CheckGeometry(b); err != nil {
+		return 0, err
+	}
+	if IsEmpty(a) || IsEmpty(b) {
+		return math.Inf(1), nil
+	}
+	if Intersects(a, b) {
+		return 0, nil
+	}
+
+	// Geometries that do not intersect are closest between their linework
+	pa, pb := distanceParts(normalize(a), nil), distanceParts(normalize(b), nil)
+	d := math.Inf(1)
+	for _, p := range pa {
+		for _, q := range pb {
+			d = math.Min(d, p.distance(q))
+		}
+	}
+	return d, nil
+}
+
+// distancePart is an edge of the linework of a geometry, a point when its
+// ends are equal. A Circle is its centre grown by its radius.
+type distancePart struct {
+	edge relateEdge
+	grow float64
+}
+
+// distanceParts appends the parts of a normalized geometry to parts. Areas
+// are their boundaries, which is enough as Distance has ruled out overlap.
+func distanceParts(g orb.Geometry, parts []distancePart) []distancePart {
+	switch g := g.(type) {
+	case orb.Point:
+		parts = append(parts, distancePart{edge: relateEdge{a: g, b: g}})
+	case orb.MultiPoint:
+		for _, p := range g {
+			parts = distanceParts(p, parts)
+		}
+	case orb.LineString:
+		if len(g) == 1 {
+			return distanceParts(g[0], parts)
+		}
+		for i := 1; i < len(g); i++ {
+			parts = append(parts, distancePart{edge: relateEdge{a: g[i-1], b: g[i]}})
+		}
+	case orb.MultiLineString:
+		for _, ls := range g {
+			parts = distanceParts(ls, parts)
+		}
+	case orb.Ring:
+		parts = distanceParts(orb.LineString(g), parts)
+	case orb.Polygon:
+		for _, r := range g {
+			parts = distanceParts(orb.LineString(r), parts)
+		}
+	case orb.MultiPolygon:
+		for _, poly := range g {
+			parts = distanceParts(poly, parts)
+		}
+	case orb.Bound:
+		parts = distanceParts(orb.LineString(g.ToRing()), parts)
+	case Circle:
+		parts = append(parts, distancePart{edge: relateEdge{a: g.Center, b: g.Center}, grow: g.Radius})
+	case CircularString:
+		edges := g.edges()
+		if len(edges) == 0 {
+			return distanceParts(g.Points[0], parts)
+		}
+		for _, e := range edges {
+			parts = append(parts, distancePart{edge: e})
+		}
+	case Relater:
+		parts = distanceParts(relaterLinework(g), parts)
+	case orb.Collection:
+		for _, member := range g {
+			parts = distanceParts(member, parts)
+		}
+	}
+	return parts
+}
+
+// distance returns the distance between two parts that do not cross. The
+// closest points are ends of the edges, or, on an arc, points whose
+// direction from its centre is normal to the other edge.
+func (p distancePart) distance(q distancePart) float64 {
+	d := math.Min(
+		math.Min(edgeDistance(q.edge, p.edge.a), edgeDistance(q.edge, p.edge.b)),
+		math.Min(edgeDistance(p.edge, q.edge.a), edgeDistance(p.edge, q.edge.b)),
+	)
+	for _, pair := range [2][2]relateEdge{{p.edge, q.edge}, {q.edge, p.edge}} {
+		e, other := pair[0], pair[1]
+		if !e.curved {
+			continue
+		}
+		// Along the normal of a segment, or the line through the centres
+		// of two arcs
+		var dx, dy float64
+		if other.curved {
+			dx, dy = other.arc.centre[0]-e.arc.centre[0], other.arc.centre[1]-e.arc.centre[1]
+		} else {
+			dx, dy = other.a[1]-other.b[1], other.b[0]-other.a[0]
+		}
+		if dx == 0 && dy == 0 {
+			continue
+		}
+		for _, angle := range []float64{math.Atan2(dy, dx), math.Atan2(-dy, -dx)} {
+			pt := orb.Point{
+				e.arc.centre[0] + e.arc.radius*math.Cos(angle),
+				e.arc.centre[1] + e.arc.radius*math.Sin(angle),
+			}
+			if e.arc.angleParam(pt) <= 1 {
+				d = math.Min(d, edgeDistance(other, pt))
+			}
+		}
+	}
+	return math.Max(d-p.grow-q.grow, 0)
+}
+
+// edgeDistance returns the distance from pt to an edge
+func edgeDistance(e relateEdge, pt orb.Point) float64 {
+	if !e.curved {
+		return segmentDistance(pt, e.a, e.b)
+	}
+	if !pointsEqual(pt, e.arc.centre) && e.arc.angleParam(pt) <= 1 {
+		return math.Abs(math.Hypot(pt[0]-e.arc.centre[0], pt[1]-e.arc.centre[1]) - e.arc.radius)
+	}
+	return math.Min(
+		math.Hypot(pt[0]-e.a[0], pt[1]-e.a[1]),
+		math.Hypot(pt[0]-e.b[0], pt[1]-e.b[1]),
+	)
+}
+
+// segmentDistance returns the distance from p to the segment ab
+func segmentDistance(p, a, b orb.Point) float64 {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	t := 0.0
+	if l := dx*dx + dy*dy; l > 0 {
+		t = math.Max(0, math.Min(1, ((p[0]-a[0])*dx+(p[1]-a[1])*dy)/l))
+	}
+	return math.Hypot(p[0]-(a[0]+t*dx), p[1]-(a[1]+t*dy))
+}
@@ -0,0 +1,14 @@
+package predicates
+
+import (
+	"github.com/paulmach/orb"
+)
+
+// Equals returns true if the geometries have the same points, whatever their
+// vertices: a square with an extra vertex on one side equals the square. It
+// is Within(a, b) && Contains(a, b), the DE-9IM pattern T*F**FFF*, except that
+// two empty geometries are equal, as in JTS's equalsTopo and PostGIS's
+// ST_Equals.
+func Equals(a, b orb.Geometry) bool {
+	return holds(a, b, PredEquals)
+}
@@ -0,0 +1,400 @@
+package predicates
+
+import (
+	"math"
+	"strings"
+
+	"github.com/paulmach/orb"
+)
+
+// PredicateSet is a set of predicates for Evaluate, as a bitmask
+type PredicateSet uint16
+
+// The predicates, one bit each
+const (
+	PredIntersects PredicateSet = 1 << iota
+	PredDisjoint
+	PredContains
+	PredWithin
+	PredCovers
+	PredCoveredBy
+	PredContainsProperly
+	PredCrosses
+	PredOverlaps
+	PredTouches
+	PredEquals
+
+	// AllPredicates is the set of every predicate
+	AllPredicates PredicateSet = 1<<iota - 1
+)
+
+var predicateNames = [...]string{
+	"intersects", "disjoint", "contains", "within", "covers",
+	"coveredby", "containsproperly", "crosses", "overlaps", "touches", "equals",
+}
+
+// ParsePredicate returns the predicate of the given name, ignoring case and
+// underscores, so "coveredby", "CoveredBy" and "covered_by" are the same
+func ParsePredicate(name string) (PredicateSet, bool) {
+	name = strings.ToLower(strings.ReplaceAll(name, "_", ""))
+	for i, n := range predicateNames {
+		if n == name {
+			return 1 << i, true
+		}
+	}
+	return 0, false
+}
+
+// String returns the names of the predicates in the set joined by "|"
+func (s PredicateSet) String() string {
+	if s == 0 {
+		return "none"
+	}
+	var names []string
+	for i, name := range predicateNames {
+		if s&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, "|")
+}
+
+// PredicateResult is the set of the predicates asked of Evaluate that hold
+type PredicateResult PredicateSet
+
+// Has reports whether every predicate in set holds
+func (r PredicateResult) Has(set PredicateSet) bool {
+	return PredicateSet(r)&set == set
+}
+
+// String returns the names of the predicates that hold joined by "|"
+func (r PredicateResult) String() string {
+	return PredicateSet(r).String()
+}
+
+// Evaluate returns which of the predicates in set hold for a and b, with the
+// same answers as calling them one by one. Every predicate is read from the
+// DE-9IM matrix of a and b, so the arguments are normalized and related only
+// once, and the relate engine stops as soon as the matrix it has built
+// decides every predicate asked for.
+//
+//	r := predicates.Evaluate(a, b, predicates.PredIntersects|predicates.PredTouches|predicates.PredWithin)
+//	if r.Has(predicates.PredTouches) { ... }
+func Evaluate(a, b orb.Geometry, set PredicateSet) PredicateResult {
+	return evaluate(a, b, set, Mod2Rule)
+}
+
+// holds evaluates a single predicate
+func holds(a, b orb.Geometry, p PredicateSet) bool {
+	return evaluate(a, b, p, Mod2Rule) != 0
+}
+
+// evaluate is Evaluate with rule for the end points of lines
+func evaluate(a, b orb.Geometry, set PredicateSet, rule BoundaryNodeRule) PredicateResult {
+	set &= AllPredicates
+	if emptyA, emptyB := IsEmpty(a), IsEmpty(b); emptyA || emptyB {
+		if emptyA && emptyB {
+			return PredicateResult(set & (PredDisjoint | PredEquals))
+		}
+		return PredicateResult(set & PredDisjoint)
+	}
+	if im, ok := pointAreaMatrix(a, b); ok {
+		return im.result(set, 0, 2)
+	}
+	if im, ok := pointAreaMatrix(b, a); ok {
+		return transpose(im).result(set, 2, 0)
+	}
+	a, b = normalize(a), normalize(b)
+	ea, eb := Envelope(a), Envelope(b)
+	if !boundsOverlap(ea, eb) {
+		return PredicateResult(set & PredDisjoint)
+	}
+	dimA, dimB := normalizedDimension(a), normalizedDimension(b)
+
+	// Leave the engine only the predicates the envelopes and dimensions
+	// have not already ruled out
+	set &^= ruledOut(ea, eb, dimA, dimB)
+	if set == 0 {
+		return 0
+	}
+	if r, ok := quickResult(a, b, ea, eb, set); ok {
+		return r
+	}
+	if r, ok := crossingResult(a, b, set); ok {
+		return r
+	}
+	return relateUntil(a, b, ea, eb, rule, set, dimA, dimB).result(set, dimA, dimB)
+}
+
+// result returns the predicates in set that hold by the matrix for
+// geometries of dimension dimA and dimB
+func (im intersectionMatrix) result(set PredicateSet, dimA, dimB int) PredicateResult {
+	var result PredicateResult
+	for s := set; s != 0; {
+		p := s & -s
+		s &^= p
+		if im.holds(p, dimA, dimB) {
+			result |= PredicateResult(p)
+		}
+	}
+	return result
+}
+
+// pointAreaMatrix relates a point to a Polygon, MultiPolygon or Bound that
+// encloses an area straight from the arguments, as normalize would leave
+// both as they are. It is false for other pairs, and for a point on the
+// boundaries of several polygons.
+func pointAreaMatrix(a, b orb.Geometry) (intersectionMatrix, bool) {
+	p, ok := a.(orb.Point)
+	if !ok {
+		return intersectionMatrix{}, false
+	}
+	var loc int
+	switch g := b.(type) {
+	case orb.Polygon:
+		if !polygonIsArea(g) {
+			return intersectionMatrix{}, false
+		}
+		loc = locateInPolygon(p, g)
+	case orb.MultiPolygon:
+		for _, poly := range g {
+			if !polygonIsArea(poly) {
+				return intersectionMatrix{}, false
+			}
+		}
+		var ok bool
+		if loc, ok = locateInMultiPolygon(p, g); !ok {
+			return intersectionMatrix{}, false
+		}
+	case orb.Bound:
+		if !boundIsArea(g) {
+			return intersectionMatrix{}, false
+		}
+		loc = locateInBound(p, g)
+	default:
+		return intersectionMatrix{}, false
+	}
+	im := emptyMatrix()
+	im.set(locExterior, locInterior, 2)
+	im.set(locExterior, locBoundary, 1)
+	im.set(locInterior, loc, 0)
+	return im, true
+}
+
+// quickResult answers set without the relate engine when it only asks
+// whether the geometries meet and they are simple enough to test directly,
+// or when it only asks for covering by a box that holds the envelope of
+// the other geometry. It is false when set needs the engine.
+func quickResult(a, b orb.Geometry, ea, eb orb.Bound, set PredicateSet) (PredicateResult, bool) {
+	const meets = PredIntersects | PredDisjoint
+	if set&^(meets|PredCovers|PredCoveredBy) != 0 {
+		return 0, false
+	}
+	_, boxA := a.(orb.Bound)
+	_, boxB := b.(orb.Bound)
+	coveredBy := boxB && boundCoversBound(eb, ea)
+	covers := boxA && boundCoversBound(ea, eb)
+	switch {
+	case (covers || set&PredCovers == 0) && (coveredBy || set&PredCoveredBy == 0) && (covers || coveredBy):
+		return PredicateResult(set &^ PredDisjoint), true
+	case set&^meets != 0:
+		return 0, false
+	}
+	if meet, ok := quickIntersects(a, b); ok {
+		if meet {
+			return PredicateResult(set & PredIntersects), true
+		}
+		return PredicateResult(set & PredDisjoint), true
+	}
+	return 0, false
+}
+
+// crossingResult answers set for two short LineStrings whose segments only
+// meet where they cross properly, away from every vertex. Such lines cross
+// if they meet at all, and no predicate but intersects, disjoint and crosses
+// can hold. It is false when a vertex is on or near the other line, which
+// the relate engine decides.
+func crossingResult(a, b orb.Geometry, set PredicateSet) (PredicateResult, bool) {
+	la, okA := a.(orb.LineString)
+	lb, okB := b.(orb.LineString)
+	if !okA || !okB || len(la) < 2 || len(lb) < 2 || len(la) > indexMinSegments || len(lb) > indexMinSegments {
+		return 0, false
+	}
+	var bounds [indexMinSegments]orb.Bound
+	for j := 1; j < len(lb); j++ {
+		bounds[j-1] = pointsBound(lb[j-1 : j+1])
+	}
+	meet := false
+	for i := 1; i < len(la); i++ {
+		p, q := la[i-1], la[i]
+		bound := pointsBound(la[i-1 : i+1])
+		for j := 1; j < len(lb); j++ {
+			r, s := lb[j-1], lb[j]
+			if !boundsOverlap(bound, bounds[j-1]) {
+				continue
+			}
+			if nearSegment(p, r, s) || nearSegment(q, r, s) || nearSegment(r, p, q) || nearSegment(s, p, q) {
+				return 0, false
+			}
+			meet = meet || segmentsCrossProper(p, q, r, s)
+		}
+	}
+	if meet {
+		return PredicateResult(set & (PredIntersects | PredCrosses)), true
+	}
+	return PredicateResult(set & PredDisjoint), true
+}
+
+// nearSegment checks if p is on segment ab or within epsilon of it
+func nearSegment(p, a, b orb.Point) bool {
+	if p[0] < math.Min(a[0], b[0])-epsilon || p[0] > math.Max(a[0], b[0])+epsilon ||
+		p[1] < math.Min(a[1], b[1])-epsilon || p[1] > math.Max(a[1], b[1])+epsilon {
+		return false
+	}
+	return pointOnSegment(p, a, b) || segmentDistance(p, a, b) <= epsilon
+}
+
+// quickIntersects checks if normalized geometries meet when they are made
+// of points, lines, polygons and boxes whose intersection the typed
+// predicates test. It is false for other geometries, and for points that
+// would need to be located on a line.
+func quickIntersects(a, b orb.Geometry) (meet, ok bool) {
+	switch g := a.(type) {
+	case orb.MultiPoint:
+		for _, p := range g {
+			if meet, ok := quickIntersects(p, b); !ok || meet {
+				return meet, ok
+			}
+		}
+		return false, true
+	case orb.MultiLineString:
+		for _, ls := range g {
+			if meet, ok := quickIntersects(ls, b); !ok || meet {
+				return meet, ok
+			}
+		}
+		return false, true
+	case orb.MultiPolygon:
+		for _, poly := range g {
+			if meet, ok := quickIntersects(poly, b); !ok || meet {
+				return meet, ok
+			}
+		}
+		return false, true
+	case orb.Collection:
+		for _, part := range g {
+			if meet, ok := quickIntersects(part, b); !ok || meet {
+				return meet, ok
+			}
+		}
+		return false, true
+	}
+	switch b.(type) {
+	case orb.MultiPoint, orb.MultiLineString, orb.MultiPolygon, orb.Collection:
+		return quickIntersects(b, a)
+	}
+	return simpleIntersects(a, b)
+}
+
+// simpleIntersects is quickIntersects for two of a point, line, polygon,
+// ring or box
+func simpleIntersects(a, b orb.Geometry) (meet, ok bool) {
+	if simpleRank(a) > simpleRank(b) {
+		a, b = b, a
+	}
+	if r, isRing := b.(orb.Ring); isRing {
+		b = orb.Polygon{r}
+	}
+	switch o := b.(type) {
+	case orb.LineString:
+		if len(o) < 2 {
+			return false, false
+		}
+	case orb.Polygon:
+		if !polygonIsArea(o) {
+			return false, false
+		}
+	}
+
+	switch g := a.(type) {
+	case orb.Point:
+		switch o := b.(type) {
+		case orb.Polygon:
+			loc, ok := locateInArea(g, o, o.Bound())
+			return loc != locExterior, ok
+		case orb.Bound:
+			return locateInBound(g, o) != locExterior, true
+		}
+	case orb.LineString:
+		if len(g) < 2 {
+			return false, false
+		}
+		switch o := b.(type) {
+		case orb.LineString:
+			return lineStringsIntersect(g, o), true
+		case orb.Polygon:
+			return lineStringIntersectsPolygon(g, o), true
+		case orb.Bound:
+			return boundIntersectsLineString(o, g), true
+		}
+	case orb.Ring, orb.Polygon:
+		poly, isPoly := g.(orb.Polygon)
+		if !isPoly {
+			poly = orb.Polygon{g.(orb.Ring)}
+		}
+		if !polygonIsArea(poly) {
+			return false, false
+		}
+		switch o := b.(type) {
+		case orb.Polygon:
+			return polygonsIntersect(poly, o), true
+		case orb.Bound:
+			return boundIntersectsPolygon(o, poly), true
+		}
+	case orb.Bound:
+		// Boxes whose envelopes overlap meet
+		_, isBound := b.(orb.Bound)
+		return isBound, isBound
+	}
+	return false, false
+}
+
+// simpleRank orders the types simpleIntersects takes, so that it only
+// handles each pair one way round
+func simpleRank(g orb.Geometry) int {
+	switch g.(type) {
+	case orb.Point:
+		return 0
+	case orb.LineString:
+		return 1
+	case orb.Ring, orb.Polygon:
+		return 2
+	case orb.Bound:
+		return 3
+	}
+	return 4
+}
+
+// ruledOut returns the predicates that cannot hold for geometries with
+// envelopes ea and eb and dimensions dimA and dimB. Nothing of higher
+// dimension fits inside something of lower dimension, and nothing fits
+// inside an envelope it does not fit in.
+func ruledOut(ea, eb orb.Bound, dimA, dimB int) PredicateSet {
+	var out PredicateSet
+	if dimA > dimB || !boundCoversBound(eb, ea) {
+		out |= PredWithin | PredCoveredBy | PredEquals
+	}
+	if dimA < dimB || !boundCoversBound(ea, eb) {
+		out |= PredContains | PredCovers | PredContainsProperly | PredEquals
+	}
+	if dimA == dimB && dimA != 1 {
+		out |= PredCrosses
+	}
+	if dimA != dimB {
+		out |= PredOverlaps
+	}
+	if dimA == 0 && dimB == 0 {
+		out |= PredTouches
+	}
+	return out
+}
@@ -0,0 +1,423 @@
+// Package geofence tracks moving objects against polygonal fences and
+// reports when they enter, leave or dwell in them.
+//
+// Fences are registered with an Engine, which keeps them prepared for
+// point queries behind a bounding box index, with the edges of each fence
+// indexed by height. Position updates of objects,
+// such as the GPS fixes of a vehicle, come in through Update, which
+// returns the events the update causes:
+//
+//	e := geofence.New()
+//	e.Add(geofence.Fence{ID: "depot", Geometry: depot, Tolerance: 5, Dwell: 10 * time.Minute})
+//	for _, ev := range e.Update("truck-7", fix.Time, fix.Point) {
+//		log.Printf("%s %s %s", ev.Object, ev.Type, ev.Fence)
+//	}
+//
+// An object is inside a fence when it is in it or on its boundary. With a
+// Tolerance, it only leaves once it is further than that outside, so that
+// positions jittering along the boundary do not enter and leave over and
+// over.
+//
+// All methods are safe for concurrent use. Updates of different objects run
+// in parallel; those of one object are applied one at a time, and an update
+// older than the last one applied for the object is ignored.
+package geofence
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/planar"
+
+	predicates "github.com/tingold/orb-predicates"
+	"github.com/tingold/orb-predicates/internal/bandindex"
+	"github.com/tingold/orb-predicates/internal/boxtree"
+)
+
+// Fence is a polygonal area objects are tracked against
+type Fence struct {
+	ID string
+
+	// Geometry is an orb.Polygon, orb.MultiPolygon, orb.Ring, orb.Bound or
+	// predicates.Circle
+	Geometry orb.Geometry
+
+	// Tolerance is how far outside the fence an object inside it can go
+	// before it leaves, in the units of the coordinates
+	Tolerance float64
+
+	// Dwell is how long an object must stay inside before a Dwell event;
+	// zero for no Dwell events
+	Dwell time.Duration
+}
+
+// EventType is what happened to an object
+type EventType int
+
+const (
+	Enter EventType = iota + 1 // the object came into the fence
+	Exit                       // the object left the fence
+	Dwell                      // the object has been inside for the fence's Dwell
+)
+
+// String returns "enter", "exit" or "dwell"
+func (t EventType) String() string {
+	switch t {
+	case Enter:
+		return "enter"
+	case Exit:
+		return "exit"
+	case Dwell:
+		return "dwell"
+	}
+	return "unknown"
+}
+
+// Event is an object entering, leaving or dwelling in a fence, at the time
+// and position of the update that caused it
+type Event struct {
+	Object string
+	Fence  string
+	Type   EventType
+	Time   time.Time
+	Point  orb.Point
+}
+
+// Engine tracks objects against a set of fences
+type Engine struct {
+	mu     sync.Mutex // serializes changes to the fences
+	fences atomic.Pointer[fenceSet]
+	serial uint64 // of the last fence added under a new ID
+
+	objectsMu sync.Mutex
+	objects   map[string]*object
+}
+
+// New returns an Engine without fences
+func New() *Engine {
+	e := &Engine{objects: make(map[string]*object)}
+	e.fences.Store(newFenceSet(nil))
+	return e
+}
+
+// Add registers a fence, replacing any fence with the same ID. Objects
+// inside the fence it replaces stay inside until an update finds them out
+// of the new one.
+func (e *Engine) Add(f Fence) error {
+	pf, err := prepare(f)
+	if err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	set := e.fences.Load()
+	if i, ok := set.byID[f.ID]; ok {
+		pf.serial = set.fences[i].serial
+	} else {
+		e.serial++
+		pf.serial = e.serial
+	}
+	fences := slices.DeleteFunc(slices.Clone(set.fences), func(old *fence) bool { return old.ID == f.ID })
+	e.fences.Store(newFenceSet(append(fences, pf)))
+	return nil
+}
+
+// Remove unregisters a fence and reports whether there was one. Objects
+// inside it are forgotten without an Exit event, and a fence added later
+// with the same ID is entered afresh.
+func (e *Engine) Remove(id string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	set := e.fences.Load()
+	if _, ok := set.byID[id]; !ok {
+		return false
+	}
+	fences := slices.DeleteFunc(slices.Clone(set.fences), func(f *fence) bool { return f.ID == id })
+	e.fences.Store(newFenceSet(fences))
+	return true
+}
+
+// Update moves object to p at time t and returns the events that causes:
+// Exit events first, then Enter, then Dwell, each ordered by fence ID
+func (e *Engine) Update(object string, t time.Time, p orb.Point) []Event {
+	set := e.fences.Load()
+	o := e.object(object)
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.updated && t.Before(o.last) {
+		return nil
+	}
+	o.updated, o.last = true, t
+
+	var events []Event
+	add := func(f string, typ EventType) {
+		events = append(events, Event{Object: object, Fence: f, Type: typ, Time: t, Point: p})
+	}
+
+	// Fences whose widened envelopes miss p are further than their
+	// tolerance away, so only the others are tested
+	candidates := set.tree.Search(orb.Bound{Min: p, Max: p}, nil)
+	tested := make(map[string]bool, len(candidates))
+	for _, i := range candidates {
+		f := set.fences[i]
+		tested[f.ID] = true
+		v := o.visits[f.ID]
+		if v != nil && v.serial != f.serial {
+			// A visit of a removed fence of the same ID
+			v = nil
+		}
+		switch inside := f.covers(p); {
+		case inside && v == nil:
+			v = &visit{serial: f.serial, entered: t}
+			o.visits[f.ID] = v
+			add(f.ID, Enter)
+		case !inside && v != nil && !f.near(p):
+			delete(o.visits, f.ID)
+			add(f.ID, Exit)
+			continue
+		}
+		if v != nil && f.Dwell > 0 && !v.dwelled && t.Sub(v.entered) >= f.Dwell {
+			v.dwelled = true
+			add(f.ID, Dwell)
+		}
+	}
+	for id, v := range o.visits {
+		if tested[id] {
+			continue
+		}
+		delete(o.visits, id)
+		if set.current(id, v) {
+			add(id, Exit)
+		}
+	}
+
+	slices.SortFunc(events, func(a, b Event) int {
+		if rank(a.Type) != rank(b.Type) {
+			return rank(a.Type) - rank(b.Type)
+		}
+		return strings.Compare(a.Fence, b.Fence)
+	})
+	return events
+}
+
+// rank orders the events of one update
+func rank(t EventType) int {
+	if t == Exit {
+		return 0
+	}
+	return int(t)
+}
+
+// Inside returns the IDs of the fences object is inside, in order
+func (e *Engine) Inside(object string) []string {
+	set := e.fences.Load()
+	e.objectsMu.Lock()
+	o := e.objects[object]
+	e.objectsMu.Unlock()
+	if o == nil {
+		return nil
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	ids := make([]string, 0, len(o.visits))
+	for id, v := range o.visits {
+		if set.current(id, v) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// Forget drops what is known about object, such as a vehicle taken out of
+// service. Its next update is treated as its first.
+func (e *Engine) Forget(object string) {
+	e.objectsMu.Lock()
+	defer e.objectsMu.Unlock()
+	delete(e.objects, object)
+}
+
+// object returns the state of an object, adding it when it is new
+func (e *Engine) object(id string) *object {
+	e.objectsMu.Lock()
+	defer e.objectsMu.Unlock()
+	o := e.objects[id]
+	if o == nil {
+		o = &object{visits: make(map[string]*visit)}
+		e.objects[id] = o
+	}
+	return o
+}
+
+// object is the state of a tracked object
+type object struct {
+	mu      sync.Mutex
+	updated bool
+	last    time.Time
+	visits  map[string]*visit // by fence ID
+}
+
+// visit is an object's stay in a fence. Visits of removed fences are left
+// to the object's next update, and are told apart from those of a fence
+// added again under the same ID by its serial.
+type visit struct {
+	serial  uint64
+	entered time.Time
+	dwelled bool
+}
+
+// fenceSet is an immutable set of prepared fences, replaced as a whole when
+// fences are added or removed so that updates never wait for a change
+type fenceSet struct {
+	fences []*fence
+	byID   map[string]int
+	tree   *boxtree.Tree
+}
+
+func newFenceSet(fences []*fence) *fenceSet {
+	set := &fenceSet{fences: fences, byID: make(map[string]int, len(fences))}
+	bounds := make([]orb.Bound, len(fences))
+	items := make([]int, len(fences))
+	for i, f := range fences {
+		set.byID[f.ID] = i
+		bounds[i] = f.env
+		items[i] = i
+	}
+	set.tree = boxtree.New(items, bounds)
+	return set
+}
+
+// current checks if a visit of fence id is of a fence in the set
+func (set *fenceSet) current(id string, v *visit) bool {
+	i, ok := set.byID[id]
+	return ok && set.fences[i].serial == v.serial
+}
+
+// fence is a Fence prepared for point queries: its indexed polygons, or
+// circle, and its envelope widened by its tolerance. Its serial is kept
+// when it is replaced, and is new when its ID is added after a Remove.
+type fence struct {
+	Fence
+	serial uint64
+	env    orb.Bound
+	polys  []*polygonIndex
+	circle *predicates.Circle
+}
+
+// prepare checks a fence and prepares it
+func prepare(f Fence) (*fence, error) {
+	if f.Tolerance < 0 || math.IsNaN(f.Tolerance) || math.IsInf(f.Tolerance, 0) {
+		return nil, fmt.Errorf("geofence: fence %q: bad tolerance %v", f.ID, f.Tolerance)
+	}
+	if f.Dwell < 0 {
+		return nil, fmt.Errorf("geofence: fence %q: negative dwell %v", f.ID, f.Dwell)
+	}
+	if err := predicates.CheckGeometry(f.Geometry); err != nil {
+		return nil, fmt.Errorf("geofence: fence %q: %w", f.ID, err)
+	}
+	if predicates.IsEmpty(f.Geometry) || predicates.Dimension(f.Geometry) != 2 {
+		return nil, fmt.Errorf("geofence: fence %q: %w", f.ID, errNotArea)
+	}
+
+	pf := &fence{Fence: f, env: predicates.Envelope(f.Geometry).Pad(f.Tolerance)}
+	var polys orb.MultiPolygon
+	switch g := f.Geometry.(type) {
+	case orb.Polygon:
+		polys = orb.MultiPolygon{g}
+	case orb.MultiPolygon:
+		polys = g
+	case orb.Ring:
+		polys = orb.MultiPolygon{{g}}
+	case orb.Bound:
+		polys = orb.MultiPolygon{g.ToPolygon()}
+	case predicates.Circle:
+		pf.circle = &g
+	default:
+		return nil, fmt.Errorf("geofence: fence %q: %w: %T", f.ID, predicates.ErrUnsupportedType, g)
+	}
+	for _, poly := range polys {
+		if !predicates.IsEmpty(poly) {
+			pf.polys = append(pf.polys, newPolygonIndex(poly))
+		}
+	}
+	return pf, nil
+}
+
+// errNotArea is returned for fences that enclose no area
+var errNotArea = errors.New("not an area")
+
+// covers checks if p is in the fence or on its boundary
+func (f *fence) covers(p orb.Point) bool {
+	if f.circle != nil {
+		return predicates.CircleCoversPoint(*f.circle, p)
+	}
+	for _, idx := range f.polys {
+		if idx.covers(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// near checks if p, outside the fence, is within its tolerance of it
+func (f *fence) near(p orb.Point) bool {
+	if f.circle != nil {
+		return planar.Distance(p, f.circle.Center)-f.circle.Radius <= f.Tolerance
+	}
+	for _, idx := range f.polys {
+		if idx.near(p, f.Tolerance) {
+			return true
+		}
+	}
+	return false
+}
+
+// polygonIndex is a polygon prepared for point queries, with its ring
+// edges indexed by height
+type polygonIndex struct {
+	env   orb.Bound
+	edges *bandindex.Index
+}
+
+// newPolygonIndex indexes the edges of a non-empty polygon
+func newPolygonIndex(poly orb.Polygon) *polygonIndex {
+	count := 0
+	for _, ring := range poly {
+		count += max(len(ring)-1, 0)
+	}
+	env := poly.Bound()
+	return &polygonIndex{env: env, edges: bandindex.New(env, count, func(visit func(bandindex.Segment)) {
+		for _, ring := range poly {
+			for i := 1; i < len(ring); i++ {
+				visit(bandindex.Segment{A: ring[i-1], B: ring[i]})
+			}
+		}
+	})}
+}
+
+// covers checks if p is in the polygon or on its boundary
+func (idx *polygonIndex) covers(p orb.Point) bool {
+	if p[0] < idx.env.Min[0]-bandindex.Epsilon || p[0] > idx.env.Max[0]+bandindex.Epsilon ||
+		p[1] < idx.env.Min[1]-bandindex.Epsilon || p[1] > idx.env.Max[1]+bandindex.Epsilon {
+		return false
+	}
+	return bandindex.Covers(idx.edges.Near(p[1]), p)
+}
+
+// near checks if p is within d of an edge of the polygon. Only the bands
+// within d of the height of p can hold such an edge.
+func (idx *polygonIndex) near(p orb.Point, d float64) bool {
+	for _, e := range idx.edges.Between(p[1]-d-bandindex.Epsilon, p[1]+d+bandindex.Epsilon) {
+		if planar.DistanceFromSegment(e.A, e.B, p) <= d {
+			return true
+		}
+	}
+	return false
+}
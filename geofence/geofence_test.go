@@ -0,0 +1,262 @@
+package geofence
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/planar"
+
+	predicates "github.com/tingold/orb-predicates"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func square(x, y, size float64) orb.Polygon {
+	return orb.Polygon{{{x, y}, {x + size, y}, {x + size, y + size}, {x, y + size}, {x, y}}}
+}
+
+// fix is a position update
+type fix struct {
+	t time.Time
+	p orb.Point
+}
+
+// track returns the fixes of a straight track from a to b in n steps, one
+// a minute
+func track(a, b orb.Point, n int) []fix {
+	fixes := make([]fix, n+1)
+	for i := range fixes {
+		f := float64(i) / float64(n)
+		fixes[i] = fix{start.Add(time.Duration(i) * time.Minute), orb.Point{a[0] + f*(b[0]-a[0]), a[1] + f*(b[1]-a[1])}}
+	}
+	return fixes
+}
+
+// run feeds the fixes of object and returns the events as "type fence" at
+// the minute they happened
+func run(e *Engine, object string, fixes []fix) []string {
+	var out []string
+	for _, f := range fixes {
+		for _, ev := range e.Update(object, f.t, f.p) {
+			out = append(out, fmt.Sprintf("%s %s @%d", ev.Type, ev.Fence, int(ev.Time.Sub(start).Minutes())))
+		}
+	}
+	return out
+}
+
+func TestEnterExitDwell(t *testing.T) {
+	e := New()
+	for _, f := range []Fence{
+		{ID: "a", Geometry: square(10, -5, 10), Dwell: 5 * time.Minute},
+		{ID: "b", Geometry: square(20, -5, 10)},
+		{ID: "c", Geometry: predicates.Circle{Center: orb.Point{50, 0}, Radius: 5}},
+		{ID: "far", Geometry: orb.Bound{Min: orb.Point{100, 100}, Max: orb.Point{110, 110}}},
+	} {
+		if err := e.Add(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Driving east along y = 0 one unit a minute through a, b, and c; a and
+	// b share the edge at x = 20
+	got := run(e, "truck", track(orb.Point{0, 0}, orb.Point{60, 0}, 60))
+	want := []string{
+		"enter a @10", "dwell a @15",
+		"enter b @20",
+		"exit a @21",
+		"exit b @31",
+		"enter c @45", "exit c @56",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %q, expected %q", got, want)
+	}
+	if inside := e.Inside("truck"); len(inside) != 0 {
+		t.Errorf("Inside = %v, expected none", inside)
+	}
+}
+
+func TestTolerance(t *testing.T) {
+	e := New()
+	if err := e.Add(Fence{ID: "yard", Geometry: square(0, 0, 10), Tolerance: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Jitter across the northern edge only enters once, and the object
+	// only leaves once it is more than 2 outside
+	var fixes []fix
+	for i, y := range []float64{15, 9, 11, 9.5, 11.9, 10, 12.5, 11, 9} {
+		fixes = append(fixes, fix{start.Add(time.Duration(i) * time.Minute), orb.Point{5, y}})
+	}
+	got := run(e, "v", fixes)
+	want := []string{"enter yard @1", "exit yard @6", "enter yard @8"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %q, expected %q", got, want)
+	}
+
+	// Without the tolerance every crossing counts
+	e = New()
+	e.Add(Fence{ID: "yard", Geometry: square(0, 0, 10)})
+	if got := run(e, "v", fixes); len(got) != 7 {
+		t.Errorf("events without tolerance = %q, expected 7", got)
+	}
+}
+
+func TestUpdateOrderAndFences(t *testing.T) {
+	e := New()
+	e.Add(Fence{ID: "z", Geometry: square(0, 0, 10)})
+	e.Add(Fence{ID: "y", Geometry: orb.Ring{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}})
+
+	// Both fences enter in ID order
+	evs := e.Update("v", start, orb.Point{5, 5})
+	if len(evs) != 2 || evs[0].Fence != "y" || evs[1].Fence != "z" || evs[0].Object != "v" || evs[0].Point != (orb.Point{5, 5}) {
+		t.Fatalf("events = %v", evs)
+	}
+
+	// An older update is ignored
+	if evs := e.Update("v", start.Add(-time.Minute), orb.Point{50, 50}); evs != nil {
+		t.Errorf("stale update gave %v", evs)
+	}
+	if inside := e.Inside("v"); !reflect.DeepEqual(inside, []string{"y", "z"}) {
+		t.Errorf("Inside = %v", inside)
+	}
+
+	// A removed fence is left without an event, and a replaced one is
+	// left when the object is outside the new geometry
+	if !e.Remove("y") || e.Remove("y") {
+		t.Error("Remove should report whether the fence was there")
+	}
+	if inside := e.Inside("v"); !reflect.DeepEqual(inside, []string{"z"}) {
+		t.Errorf("Inside after Remove = %v, expected [z]", inside)
+	}
+	e.Add(Fence{ID: "z", Geometry: square(100, 100, 10)})
+	evs = e.Update("v", start.Add(time.Minute), orb.Point{5, 5})
+	if len(evs) != 1 || evs[0].Type != Exit || evs[0].Fence != "z" {
+		t.Errorf("events = %v, expected exit z", evs)
+	}
+
+	// A fence added again after a Remove is entered afresh
+	e.Add(Fence{ID: "y", Geometry: square(0, 0, 10)})
+	e.Update("v", start.Add(2*time.Minute), orb.Point{5, 5})
+	e.Remove("y")
+	e.Add(Fence{ID: "y", Geometry: square(0, 0, 10)})
+	evs = e.Update("v", start.Add(3*time.Minute), orb.Point{5, 5})
+	if len(evs) != 1 || evs[0].Type != Enter || evs[0].Fence != "y" {
+		t.Errorf("events = %v, expected enter y", evs)
+	}
+
+	// A forgotten object starts over
+	e.Update("w", start, orb.Point{105, 105})
+	e.Forget("w")
+	if evs := e.Update("w", start.Add(-time.Hour), orb.Point{105, 105}); len(evs) != 1 || evs[0].Type != Enter {
+		t.Errorf("events after Forget = %v, expected enter z", evs)
+	}
+}
+
+func TestAddErrors(t *testing.T) {
+	e := New()
+	for _, f := range []Fence{
+		{ID: "line", Geometry: orb.LineString{{0, 0}, {1, 1}}},
+		{ID: "empty", Geometry: orb.Polygon{}},
+		{ID: "collapsed", Geometry: orb.Bound{Max: orb.Point{0, 1}}},
+		{ID: "tolerance", Geometry: square(0, 0, 1), Tolerance: -1},
+		{ID: "dwell", Geometry: square(0, 0, 1), Dwell: -time.Second},
+		{ID: "collection", Geometry: orb.Collection{square(0, 0, 1)}},
+	} {
+		if err := e.Add(f); err == nil {
+			t.Errorf("Add(%s) succeeded", f.ID)
+		}
+	}
+	err := e.Add(Fence{ID: "collection", Geometry: orb.Collection{square(0, 0, 1)}})
+	if !errors.Is(err, predicates.ErrUnsupportedType) {
+		t.Errorf("Add(collection) = %v, expected ErrUnsupportedType", err)
+	}
+}
+
+func TestIndexedFence(t *testing.T) {
+	// A star with a square hole, with enough edges to be banded
+	var shell orb.Ring
+	for i := range 200 {
+		a, r := float64(i)*math.Pi/100, 40.0
+		if i%2 == 1 {
+			r = 25
+		}
+		shell = append(shell, orb.Point{50 + r*math.Cos(a), 50 + r*math.Sin(a)})
+	}
+	shell = append(shell, shell[0])
+	hole := orb.Ring{{45, 45}, {45, 55}, {55, 55}, {55, 45}, {45, 45}}
+	poly := orb.Polygon{shell, hole}
+	f, err := prepare(Fence{ID: "star", Geometry: poly, Tolerance: 1.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(f.polys[0].edges.Near(50)); n > 50 {
+		t.Fatalf("%d edges near y = 50, expected the edges to be banded", n)
+	}
+
+	// The index gives what testing every edge does
+	for x := 0.0; x <= 100; x += 0.5 {
+		for y := 0.0; y <= 100; y += 0.5 {
+			p := orb.Point{x, y}
+			if got, want := f.covers(p), predicates.PolygonCoversPoint(poly, p); got != want {
+				t.Errorf("covers(%v) = %v, expected %v", p, got, want)
+			}
+			d := math.Inf(1)
+			for _, ring := range poly {
+				for i := 1; i < len(ring); i++ {
+					d = math.Min(d, planar.DistanceFromSegment(ring[i-1], ring[i], p))
+				}
+			}
+			if got, want := f.near(p), d <= f.Tolerance; got != want {
+				t.Errorf("near(%v) = %v, expected %v (distance %v)", p, got, want, d)
+			}
+		}
+	}
+}
+
+func TestConcurrentUpdates(t *testing.T) {
+	e, sequential := New(), New()
+	for i := range 100 {
+		f := Fence{ID: fmt.Sprintf("f%02d", i), Geometry: square(float64(i%10)*10, float64(i/10)*10, 10), Tolerance: 1}
+		e.Add(f)
+		sequential.Add(f)
+	}
+
+	// Every vehicle drives a diagonal through the grid, fed by its own
+	// producer while another goroutine changes fences far away, and sees
+	// what a single vehicle does on its own
+	fixes := track(orb.Point{-5, -5}, orb.Point{105, 105}, 220)
+	want := run(sequential, "v", fixes)
+	var wg sync.WaitGroup
+	results := make([][]string, 20)
+	for v := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[v] = run(e, fmt.Sprintf("v%d", v), fixes)
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := range 50 {
+			e.Add(Fence{ID: "other", Geometry: square(500+float64(i), 500, 10)})
+		}
+		e.Remove("other")
+	}()
+	wg.Wait()
+
+	if len(want) == 0 {
+		t.Fatal("no events")
+	}
+	for v, got := range results {
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("vehicle %d events = %q, expected %q", v, got, want)
+		}
+	}
+}
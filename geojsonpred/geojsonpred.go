@@ -0,0 +1,306 @@
+// Package geojsonpred applies the spatial predicates to the features of a
+// geojson.FeatureCollection.
+//
+// Filter and Partition select the features for which one of a set of
+// predicates holds, evaluated with the feature's geometry first and the
+// query geometry second, and Annotate records each feature's relationship
+// to g as a property. The features in the results are the features of the
+// input, so their IDs and properties are kept.
+//
+//	inside := geojsonpred.Filter(parcels, predicates.PredWithin, floodZone)
+//
+// Large collections are searched through a bounding box index, so only the
+// features whose boxes meet g are tested when every predicate of the set
+// needs the geometries to intersect. A set with PredDisjoint, or with
+// PredEquals and an empty g, is tested on every feature. Build an Index once
+// to run many queries against the same collection; Index.Candidates gives
+// the features to test for predicates of the caller's own.
+package geojsonpred
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+
+	predicates "github.com/tingold/orb-predicates"
+	"github.com/tingold/orb-predicates/internal/boxtree"
+)
+
+// RelationProperty is the feature property Annotate sets
+const RelationProperty = "relation"
+
+// indexThreshold is the size from which the package-level functions build an
+// index; smaller collections are scanned
+const indexThreshold = 64
+
+// Filter returns a FeatureCollection of the features of fc for which one of
+// the predicates of set holds with g, in their original order
+func Filter(fc *geojson.FeatureCollection, set predicates.PredicateSet, g orb.Geometry) *geojson.FeatureCollection {
+	return newQuery(fc).filter(set, g)
+}
+
+// Partition splits fc into the features for which one of the predicates of
+// set holds with g and the rest, each in their original order. Features
+// without a geometry are in the rest.
+func Partition(fc *geojson.FeatureCollection, set predicates.PredicateSet, g orb.Geometry) (matching, rest *geojson.FeatureCollection) {
+	return newQuery(fc).partition(set, g)
+}
+
+// Annotate sets the RelationProperty of every feature of fc to its
+// relationship with g, as named by Relation. Features without a geometry
+// are left as they are.
+func Annotate(fc *geojson.FeatureCollection, g orb.Geometry) {
+	newQuery(fc).annotate(g)
+}
+
+// Relation names the most specific relationship of a to b: "disjoint",
+// "equals", "within", "contains", "coveredby", "covers", "touches",
+// "crosses", "overlaps" or "intersects". The predicates are read from a
+// single relate run.
+func Relation(a, b orb.Geometry) string {
+	r := predicates.Evaluate(a, b, predicates.AllPredicates)
+	switch {
+	case !r.Has(predicates.PredIntersects):
+		return "disjoint"
+	case r.Has(predicates.PredEquals):
+		return "equals"
+	case r.Has(predicates.PredWithin):
+		return "within"
+	case r.Has(predicates.PredContains):
+		return "contains"
+	case r.Has(predicates.PredCoveredBy):
+		return "coveredby"
+	case r.Has(predicates.PredCovers):
+		return "covers"
+	case r.Has(predicates.PredTouches):
+		return "touches"
+	case r.Has(predicates.PredCrosses):
+		return "crosses"
+	case r.Has(predicates.PredOverlaps):
+		return "overlaps"
+	}
+	return "intersects"
+}
+
+// Index is a bounding box index over the features of a FeatureCollection
+// for running many queries against it. The collection must not change
+// while the Index is in use.
+type Index struct {
+	fc   *geojson.FeatureCollection
+	tree *boxtree.Tree
+}
+
+// NewIndex indexes the bounding boxes of the features of fc
+func NewIndex(fc *geojson.FeatureCollection) *Index {
+	bounds := make([]orb.Bound, len(fc.Features))
+	items := make([]int, 0, len(fc.Features))
+	for i, f := range fc.Features {
+		if f.Geometry == nil || predicates.IsEmpty(f.Geometry) {
+			continue
+		}
+		bounds[i] = predicates.Envelope(f.Geometry)
+		items = append(items, i)
+	}
+	return &Index{fc: fc, tree: boxtree.New(items, bounds)}
+}
+
+// Filter is like the package-level Filter on the indexed collection
+func (ix *Index) Filter(set predicates.PredicateSet, g orb.Geometry) *geojson.FeatureCollection {
+	return query{fc: ix.fc, index: ix}.filter(set, g)
+}
+
+// Partition is like the package-level Partition on the indexed collection
+func (ix *Index) Partition(set predicates.PredicateSet, g orb.Geometry) (matching, rest *geojson.FeatureCollection) {
+	return query{fc: ix.fc, index: ix}.partition(set, g)
+}
+
+// Annotate is like the package-level Annotate on the indexed collection
+func (ix *Index) Annotate(g orb.Geometry) {
+	query{fc: ix.fc, index: ix}.annotate(g)
+}
+
+// Matches returns the positions in the indexed collection of the features
+// for which one of the predicates of set holds with g, in collection order.
+// It is Filter for callers that keep their own records alongside the
+// features.
+func (ix *Index) Matches(set predicates.PredicateSet, g orb.Geometry) []int {
+	items := ix.CandidatesFor(set, g)
+	n := 0
+	for _, i := range items {
+		if predicates.Evaluate(ix.fc.Features[i].Geometry, g, set) != 0 {
+			items[n] = i
+			n++
+		}
+	}
+	return items[:n]
+}
+
+// Candidates returns the positions in the indexed collection of the features
+// whose boxes meet the box of g, in collection order. These are the only
+// features a predicate that needs the geometries to intersect can hold for,
+// so callers evaluating predicates of their own only need to test them.
+func (ix *Index) Candidates(g orb.Geometry) []int {
+	return query{fc: ix.fc, index: ix}.candidates(g, false)
+}
+
+// CandidatesFor returns the positions in the indexed collection of the
+// features a predicate of set can hold for with g, in either order: the
+// Candidates of g when every predicate of set needs the geometries to
+// intersect, and otherwise every feature with a geometry.
+func (ix *Index) CandidatesFor(set predicates.PredicateSet, g orb.Geometry) []int {
+	return query{fc: ix.fc, index: ix}.candidates(g, !intersecting(set, g))
+}
+
+// query evaluates a predicate over a collection, through an index if it has one
+type query struct {
+	fc    *geojson.FeatureCollection
+	index *Index
+}
+
+// newQuery indexes fc if it is large enough for the index to pay off
+func newQuery(fc *geojson.FeatureCollection) query {
+	if len(fc.Features) >= indexThreshold {
+		return query{fc: fc, index: NewIndex(fc)}
+	}
+	return query{fc: fc}
+}
+
+// matches returns which features one of the predicates of set holds for.
+// Features whose boxes are apart from g are only skipped when the set
+// needs them to intersect, see intersecting.
+func (q query) matches(set predicates.PredicateSet, g orb.Geometry) []bool {
+	matched := make([]bool, len(q.fc.Features))
+	for _, i := range q.candidates(g, !intersecting(set, g)) {
+		matched[i] = predicates.Evaluate(q.fc.Features[i].Geometry, g, set) != 0
+	}
+	return matched
+}
+
+// candidates returns the features that can be related to g: those that have a
+// geometry, and unless all is set, whose boxes meet the box of g
+func (q query) candidates(g orb.Geometry, all bool) []int {
+	var items []int
+	if q.index != nil && !all {
+		if predicates.IsEmpty(g) {
+			return nil
+		}
+		bound := predicates.Envelope(g).Pad(boundPadding)
+		// The index finds features in tree order; keep them in collection order
+		items = q.index.tree.Search(bound, items)
+		sort.Ints(items)
+		return items
+	}
+	for i, f := range q.fc.Features {
+		if f.Geometry != nil {
+			items = append(items, i)
+		}
+	}
+	return items
+}
+
+// boundPadding widens the query box by more than the tolerance of the
+// predicates, so features they would count as touching are not missed
+const boundPadding = 1e-9
+
+// intersecting checks if every predicate of set needs the geometries to
+// intersect, so that features whose boxes are apart from g can be skipped.
+// Disjoint holds for geometries apart, and Equals for two empty ones.
+func intersecting(set predicates.PredicateSet, g orb.Geometry) bool {
+	return set&predicates.PredDisjoint == 0 && (set&predicates.PredEquals == 0 || !predicates.IsEmpty(g))
+}
+
+func (q query) filter(set predicates.PredicateSet, g orb.Geometry) *geojson.FeatureCollection {
+	out := newCollection(q.fc)
+	for i, ok := range q.matches(set, g) {
+		if ok {
+			out.Append(q.fc.Features[i])
+		}
+	}
+	return out
+}
+
+func (q query) partition(set predicates.PredicateSet, g orb.Geometry) (matching, rest *geojson.FeatureCollection) {
+	matching, rest = newCollection(q.fc), newCollection(q.fc)
+	for i, ok := range q.matches(set, g) {
+		if ok {
+			matching.Append(q.fc.Features[i])
+		} else {
+			rest.Append(q.fc.Features[i])
+		}
+	}
+	return matching, rest
+}
+
+func (q query) annotate(g orb.Geometry) {
+	near := make([]bool, len(q.fc.Features))
+	for _, i := range q.candidates(g, false) {
+		near[i] = true
+	}
+	for i, f := range q.fc.Features {
+		if f.Geometry == nil {
+			continue
+		}
+		if f.Properties == nil {
+			f.Properties = geojson.Properties{}
+		}
+		if near[i] {
+			f.Properties[RelationProperty] = Relation(f.Geometry, g)
+		} else {
+			f.Properties[RelationProperty] = "disjoint"
+		}
+	}
+}
+
+// newCollection returns an empty FeatureCollection with the foreign members of fc
+func newCollection(fc *geojson.FeatureCollection) *geojson.FeatureCollection {
+	out := geojson.NewFeatureCollection()
+	out.ExtraMembers = fc.ExtraMembers
+	return out
+}
+
+// DecodeGeometry decodes a GeoJSON geometry, Feature or FeatureCollection.
+// The geometries of a FeatureCollection are returned as one orb.Collection,
+// and a Feature without a geometry is an error.
+func DecodeGeometry(data []byte) (orb.Geometry, error) {
+	var object struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+
+	switch object.Type {
+	case "FeatureCollection":
+		fc, err := geojson.UnmarshalFeatureCollection(data)
+		if err != nil {
+			return nil, err
+		}
+		c := make(orb.Collection, 0, len(fc.Features))
+		for _, f := range fc.Features {
+			if f.Geometry != nil {
+				c = append(c, f.Geometry)
+			}
+		}
+		return c, nil
+	case "Feature":
+		f, err := geojson.UnmarshalFeature(data)
+		if err != nil {
+			return nil, err
+		}
+		if f.Geometry == nil {
+			return nil, errors.New("feature has no geometry")
+		}
+		return f.Geometry, nil
+	case "":
+		return nil, errors.New("object has no type")
+	}
+
+	g, err := geojson.UnmarshalGeometry(data)
+	if err != nil {
+		return nil, err
+	}
+	return g.Geometry(), nil
+}
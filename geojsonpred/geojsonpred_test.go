@@ -0,0 +1,226 @@
+package geojsonpred
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+
+	predicates "github.com/tingold/orb-predicates"
+)
+
+func square(x, y, size float64) orb.Polygon {
+	return orb.Polygon{{{x, y}, {x + size, y}, {x + size, y + size}, {x, y + size}, {x, y}}}
+}
+
+// grid returns n by n unit squares with IDs and a name property
+func grid(n int) *geojson.FeatureCollection {
+	fc := geojson.NewFeatureCollection()
+	for x := 0; x < n; x++ {
+		for y := 0; y < n; y++ {
+			f := geojson.NewFeature(square(float64(x), float64(y), 1))
+			f.ID = fmt.Sprintf("%d-%d", x, y)
+			f.Properties["name"] = f.ID
+			fc.Append(f)
+		}
+	}
+	return fc
+}
+
+func ids(fc *geojson.FeatureCollection) []interface{} {
+	var out []interface{}
+	for _, f := range fc.Features {
+		out = append(out, f.ID)
+	}
+	return out
+}
+
+func TestFilter(t *testing.T) {
+	query := square(2.5, 2.5, 3)
+	preds := map[string]predicates.PredicateSet{
+		"intersects":     predicates.PredIntersects,
+		"within":         predicates.PredWithin,
+		"touches":        predicates.PredTouches,
+		"disjoint":       predicates.PredDisjoint,
+		"within|touches": predicates.PredWithin | predicates.PredTouches,
+	}
+
+	// A small collection is scanned and a large one indexed
+	for _, n := range []int{4, 20} {
+		fc := grid(n)
+		for name, set := range preds {
+			t.Run(fmt.Sprintf("%s/%d", name, n), func(t *testing.T) {
+				var want []interface{}
+				for _, f := range fc.Features {
+					if predicates.Evaluate(f.Geometry, query, set) != 0 {
+						want = append(want, f.ID)
+					}
+				}
+
+				got := Filter(fc, set, query)
+				if fmt.Sprint(ids(got)) != fmt.Sprint(want) {
+					t.Errorf("Filter() = %v, expected %v", ids(got), want)
+				}
+				matching, rest := Partition(fc, set, query)
+				if fmt.Sprint(ids(matching)) != fmt.Sprint(want) || len(matching.Features)+len(rest.Features) != len(fc.Features) {
+					t.Errorf("Partition() = %d and %d features, expected %v", len(matching.Features), len(rest.Features), want)
+				}
+			})
+		}
+	}
+}
+
+func TestFilterEmpty(t *testing.T) {
+	// Empty geometries are equal to each other but have no box in the
+	// index, so they are found whether or not the collection is indexed
+	for _, n := range []int{63, 64} {
+		fc := geojson.NewFeatureCollection()
+		for i := range n {
+			if i%8 == 0 {
+				fc.Append(geojson.NewFeature(orb.LineString{}))
+			} else {
+				fc.Append(geojson.NewFeature(orb.Point{float64(i), 0}))
+			}
+		}
+		if got := len(Filter(fc, predicates.PredEquals, orb.Polygon{}).Features); got != 8 {
+			t.Errorf("Filter(equals) of an empty geometry over %d features = %d features, expected 8", n, got)
+		}
+		if got := len(Filter(fc, predicates.PredEquals, orb.Point{8, 0}).Features); got != 0 {
+			t.Errorf("Filter(equals) of a point over %d features = %d features, expected 0", n, got)
+		}
+	}
+}
+
+func TestFilterKeepsFeatures(t *testing.T) {
+	fc := grid(10)
+	fc.ExtraMembers = geojson.Properties{"name": "grid"}
+	fc.Append(&geojson.Feature{ID: "no geometry", Properties: geojson.Properties{}})
+
+	got := Filter(fc, predicates.PredWithin, square(-0.5, -0.5, 2.6))
+	if len(got.Features) != 4 {
+		t.Fatalf("Filter() = %v, expected 4 features", ids(got))
+	}
+	for _, f := range got.Features {
+		if f.Properties["name"] != f.ID {
+			t.Errorf("feature %v lost its properties: %v", f.ID, f.Properties)
+		}
+	}
+	if got.ExtraMembers["name"] != "grid" {
+		t.Errorf("ExtraMembers = %v, expected them kept", got.ExtraMembers)
+	}
+
+	_, rest := Partition(fc, predicates.PredIntersects, square(-0.5, -0.5, 2.6))
+	if last := rest.Features[len(rest.Features)-1]; last.ID != "no geometry" {
+		t.Errorf("feature without geometry should be in the rest, last is %v", last.ID)
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	fc := grid(10)
+	Annotate(fc, orb.MultiPolygon{square(1.5, 1.5, 3), square(7, 7, 1)})
+
+	expected := map[string]string{
+		"2-2": "within",
+		"3-3": "within",
+		"1-1": "overlaps",
+		"4-4": "overlaps",
+		"6-7": "touches",
+		"8-8": "touches",
+		"7-7": "within",
+		"5-5": "disjoint",
+		"9-9": "disjoint",
+	}
+	for _, f := range fc.Features {
+		if want, ok := expected[f.ID.(string)]; ok && f.Properties[RelationProperty] != want {
+			t.Errorf("feature %v relation = %v, expected %v", f.ID, f.Properties[RelationProperty], want)
+		}
+	}
+
+	single := geojson.NewFeatureCollection()
+	single.Append(&geojson.Feature{Geometry: square(0, 0, 1)})
+	Annotate(single, square(0, 0, 1))
+	if got := single.Features[0].Properties[RelationProperty]; got != "equals" {
+		t.Errorf("relation of an equal square = %v, expected equals", got)
+	}
+}
+
+func TestIndexReuse(t *testing.T) {
+	fc := grid(20)
+	ix := NewIndex(fc)
+	for _, q := range []orb.Polygon{square(0, 0, 3), square(10.5, 10.5, 2), square(30, 30, 1)} {
+		got, want := ix.Filter(predicates.PredIntersects, q), Filter(fc, predicates.PredIntersects, q)
+		if fmt.Sprint(ids(got)) != fmt.Sprint(ids(want)) {
+			t.Errorf("Index.Filter(%v) = %v, expected %v", q, ids(got), ids(want))
+		}
+	}
+}
+
+func TestIndexMatches(t *testing.T) {
+	fc := grid(20)
+	ix := NewIndex(fc)
+	for name, set := range map[string]predicates.PredicateSet{"intersects": predicates.PredIntersects, "disjoint": predicates.PredDisjoint} {
+		q := square(3.5, 3.5, 2)
+		var want []interface{}
+		for _, i := range ix.Matches(set, q) {
+			want = append(want, fc.Features[i].ID)
+		}
+		if got := ids(ix.Filter(set, q)); fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("%s: Matches() = %v, expected the features of Filter() %v", name, want, got)
+		}
+	}
+
+	// The candidates are the features whose boxes meet the query's
+	if got := ix.Candidates(square(3.5, 3.5, 1)); fmt.Sprint(got) != "[63 64 83 84]" {
+		t.Errorf("Candidates() = %v, expected [63 64 83 84]", got)
+	}
+	if got := ix.CandidatesFor(predicates.PredWithin|predicates.PredEquals, square(3.5, 3.5, 1)); fmt.Sprint(got) != "[63 64 83 84]" {
+		t.Errorf("CandidatesFor(within|equals) = %v, expected [63 64 83 84]", got)
+	}
+	if got := ix.CandidatesFor(predicates.PredDisjoint, square(3.5, 3.5, 1)); len(got) != len(fc.Features) {
+		t.Errorf("CandidatesFor(disjoint) = %d features, expected all %d", len(got), len(fc.Features))
+	}
+	if got := ix.CandidatesFor(predicates.PredEquals, orb.Polygon{}); len(got) != len(fc.Features) {
+		t.Errorf("CandidatesFor(equals) of an empty geometry = %d features, expected all %d", len(got), len(fc.Features))
+	}
+}
+
+func TestRelation(t *testing.T) {
+	tests := []struct {
+		a, b     orb.Geometry
+		expected string
+	}{
+		{square(0, 0, 1), square(5, 5, 1), "disjoint"},
+		{square(0, 0, 1), square(0, 0, 1), "equals"},
+		{square(1, 1, 1), square(0, 0, 4), "within"},
+		{square(0, 0, 4), square(1, 1, 1), "contains"},
+		{square(0, 0, 1), square(0, 0, 4), "within"},
+		{orb.LineString{{0, 0}, {1, 0}}, square(0, 0, 4), "coveredby"},
+		{square(0, 0, 4), orb.LineString{{0, 0}, {1, 0}}, "covers"},
+		{square(0, 0, 1), square(1, 0, 1), "touches"},
+		{orb.LineString{{-1, 0.5}, {2, 0.5}}, square(0, 0, 1), "crosses"},
+		{square(0, 0, 2), square(1, 1, 2), "overlaps"},
+	}
+	for _, tt := range tests {
+		if got := Relation(tt.a, tt.b); got != tt.expected {
+			t.Errorf("Relation(%v, %v) = %s, expected %s", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
+
+func BenchmarkFilter(b *testing.B) {
+	fc := grid(100)
+	query := square(40.5, 40.5, 5)
+	b.Run("package", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Filter(fc, predicates.PredIntersects, query)
+		}
+	})
+	b.Run("index", func(b *testing.B) {
+		ix := NewIndex(fc)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			ix.Filter(predicates.PredIntersects, query)
+		}
+	})
+}
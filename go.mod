@@ -3,3 +3,5 @@ module github.com/tingold/orb-predicates
 go 1.24.4
 
 require github.com/paulmach/orb v0.12.0
+
+require go.mongodb.org/mongo-driver v1.11.4 // indirect
@@ -0,0 +1,394 @@
+package predicates
+
+import (
+	"cmp"
+	"slices"
+	"sort"
+
+	"github.com/paulmach/orb"
+)
+
+// relateRun builds the topology graph of two geometries and records what it
+// finds in their DE-9IM matrix. The graph is made of:
+//   - nodes: every vertex, point and crossing (dimension 0)
+//   - edges: every piece of a segment or arc split at the nodes on it,
+//     located by its midpoint (dimension 1)
+//   - faces: both sides of every piece, located from the rings on either
+//     side (dimension 2)
+//
+// Every node and piece is labelled Interior, Boundary or Exterior for each
+// input. Matrix entries only ever rise as the graph is built, so the run
+// stops as soon as the predicates in set have the same value on every
+// matrix it could still grow into.
+type relateRun struct {
+	ga, gb     *relateGeometry
+	im         intersectionMatrix
+	set        PredicateSet
+	dimA, dimB int
+}
+
+// graphNode is a vertex of one of the geometries
+type graphNode struct {
+	p     orb.Point
+	owner int
+}
+
+// graphEdge is a segment or arc of one of the geometries, with the side of
+// it the polygon interior is on when it is a ring edge
+type graphEdge struct {
+	relateEdge
+	bound        orb.Bound
+	owner        int
+	interiorLeft bool
+}
+
+// edgeSplit is a node that splits edge at fraction t
+type edgeSplit struct {
+	edge int
+	t    float64
+}
+
+// add raises the entry for (locA, locB) to at least dim and reports whether
+// the predicates are decided
+func (r *relateRun) add(locA, locB, dim int) bool {
+	if r.im[locA][locB] >= dim {
+		return false
+	}
+	r.im[locA][locB] = dim
+	return r.set != 0 && r.im.decided(r.set, r.dimA, r.dimB)
+}
+
+// node locates a node that lies on the linework of a, of b, or of both
+func (r *relateRun) node(p orb.Point, onA, onB bool) bool {
+	var locA, locB int
+	if onA {
+		locA = r.ga.locateOnLinework(p)
+	} else {
+		locA = r.ga.locate(p)
+	}
+	if onB {
+		locB = r.gb.locateOnLinework(p)
+	} else {
+		locB = r.gb.locate(p)
+	}
+	return r.add(locA, locB, 0)
+}
+
+// run builds the graph, returning early once the predicates are decided
+func (r *relateRun) run() {
+	nodes, edges, decided := r.collect(0, nil, nil)
+	if decided {
+		return
+	}
+	if nodes, edges, decided = r.collect(1, nodes, edges); decided {
+		return
+	}
+
+	// Nodes: the vertices of both geometries, sorted by x so the vertices on
+	// an edge can be found from its bound
+	slices.SortFunc(nodes, func(m, n graphNode) int { return cmp.Compare(m.p[0], n.p[0]) })
+	for _, n := range nodes {
+		if r.node(n.p, n.owner == 0, n.owner == 1) {
+			return
+		}
+	}
+
+	// Crossings: a geometry of one of the simple kinds crossing itself
+	// changes none of its locations, so those pairs are skipped
+	splits := vertexSplits(nil, nodes, edges)
+	splits, decided = crossEdges(splits, edges,
+		func(e, o *graphEdge) bool { return e.owner == o.owner && r.geometry(e.owner).kind != mixedKind },
+		func(x orb.Point, e, o *graphEdge) bool {
+			return r.node(x, e.owner == 0 || o.owner == 0, e.owner == 1 || o.owner == 1)
+		})
+	if decided {
+		return
+	}
+
+	// Edges and the faces on either side of them
+	eachPiece(edges, splits, r.piece)
+}
+
+// vertexSplits appends the splits of edges at the nodes, sorted by x, that
+// lie inside them
+func vertexSplits(splits []edgeSplit, nodes []graphNode, edges []graphEdge) []edgeSplit {
+	for i := range edges {
+		e := &edges[i]
+		k := sort.Search(len(nodes), func(k int) bool { return nodes[k].p[0] >= e.bound.Min[0]-epsilon })
+		for ; k < len(nodes) && nodes[k].p[0] <= e.bound.Max[0]+epsilon; k++ {
+			v := nodes[k].p
+			if v[1] < e.bound.Min[1]-epsilon || v[1] > e.bound.Max[1]+epsilon || !e.contains(v) {
+				continue
+			}
+			if t := e.param(v); t > 0 && t < 1 {
+				splits = append(splits, edgeSplit{i, t})
+			}
+		}
+	}
+	return splits
+}
+
+// crossEdges appends the splits of edges where they cross each other and
+// visits each crossing until visit returns true, reporting whether it did.
+// It sweeps the edges from left to right and only compares those whose
+// bounds overlap, and pairs for which skip is true.
+func crossEdges(splits []edgeSplit, edges []graphEdge, skip func(e, o *graphEdge) bool, visit func(x orb.Point, e, o *graphEdge) bool) ([]edgeSplit, bool) {
+	order := make([]int, len(edges))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortFunc(order, func(i, j int) int { return cmp.Compare(edges[i].bound.Min[0], edges[j].bound.Min[0]) })
+	var crossings []orb.Point
+	for n, i := range order {
+		e := &edges[i]
+		for _, j := range order[n+1:] {
+			o := &edges[j]
+			if o.bound.Min[0] > e.bound.Max[0]+epsilon {
+				break
+			}
+			if !boundsOverlap(e.bound, o.bound) || skip(e, o) {
+				continue
+			}
+			crossings = edgeCrossings(crossings[:0], e.relateEdge, o.relateEdge)
+			for _, x := range crossings {
+				if t := e.param(x); t > 0 && t < 1 {
+					splits = append(splits, edgeSplit{i, t})
+				}
+				if t := o.param(x); t > 0 && t < 1 {
+					splits = append(splits, edgeSplit{j, t})
+				}
+				if visit(x, e, o) {
+					return splits, true
+				}
+			}
+		}
+	}
+	return splits, false
+}
+
+// eachPiece visits the pieces the splits cut the edges into, in order
+// along each edge, until visit returns true, and reports whether it did
+func eachPiece(edges []graphEdge, splits []edgeSplit, visit func(e *graphEdge, t0, t1 float64) bool) bool {
+	slices.SortFunc(splits, func(s, u edgeSplit) int {
+		if c := cmp.Compare(s.edge, u.edge); c != 0 {
+			return c
+		}
+		return cmp.Compare(s.t, u.t)
+	})
+	next := 0
+	for i := range edges {
+		e := &edges[i]
+		prev := 0.0
+		for {
+			t := 1.0
+			if next < len(splits) && splits[next].edge == i {
+				t = splits[next].t
+				next++
+			}
+			if t > prev && !pointsEqual(e.point(prev), e.point(t)) {
+				if visit(e, prev, t) {
+					return true
+				}
+				prev = t
+			}
+			if t == 1 {
+				break
+			}
+		}
+	}
+	return false
+}
+
+// geometry returns a or b by owner
+func (r *relateRun) geometry(owner int) *relateGeometry {
+	if owner == 0 {
+		return r.ga
+	}
+	return r.gb
+}
+
+// collect appends the vertices and edges of geometry owner to the graph.
+// Those of a geometry of one of the simple kinds that are away from the
+// envelope of the other are outside it, and their locations in their own
+// geometry are known without noding, so they are recorded straight away.
+func (r *relateRun) collect(owner int, nodes []graphNode, edges []graphEdge) ([]graphNode, []graphEdge, bool) {
+	g, other := r.geometry(owner), r.geometry(1-owner)
+	noded := func(b orb.Bound) bool { return g.kind == mixedKind || boundsOverlap(b, other.env) }
+	add := func(loc, dim int) bool {
+		if owner == 0 {
+			return r.add(loc, locExterior, dim)
+		}
+		return r.add(locExterior, loc, dim)
+	}
+
+	decided := g.eachNode(func(p orb.Point) bool {
+		if noded(orb.Bound{Min: p, Max: p}) {
+			nodes = append(nodes, graphNode{p, owner})
+			return false
+		}
+		return add(g.locateOnLinework(p), 0)
+	})
+	if decided {
+		return nil, nil, true
+	}
+	// Every edge away from the other adds the same entries, so only the
+	// first is located
+	away := false
+	decided = g.eachEdge(func(e relateEdge, interiorLeft bool) bool {
+		b := e.bound()
+		if noded(b) {
+			edges = append(edges, graphEdge{e, b, owner, interiorLeft})
+			return false
+		}
+		if away {
+			return false
+		}
+		away = true
+		loc, left, right := g.locatePiece(&graphEdge{relateEdge: e, interiorLeft: interiorLeft}, true, orb.Point{}, orb.Point{})
+		return add(loc, 1) || add(left, 2) || add(right, 2)
+	})
+	return nodes, edges, decided
+}
+
+// piece locates the part of edge e between fractions t0 and t1, which no
+// node splits, together with the faces on either side of it
+func (r *relateRun) piece(e *graphEdge, t0, t1 float64) bool {
+	t := (t0 + t1) / 2
+	mid, dir := e.point(t), e.tangent(t)
+	locA, leftA, rightA := r.ga.locatePiece(e, e.owner == 0, mid, dir)
+	locB, leftB, rightB := r.gb.locatePiece(e, e.owner == 1, mid, dir)
+	return r.add(locA, locB, 1) ||
+		r.add(leftA, leftB, 2) ||
+		r.add(rightA, rightB, 2)
+}
+
+// edgeCrossings appends the points where two edges cross away from their
+// end points, or meet on a curve. The edges are put in a fixed order first
+// so that the points are the same whichever geometry is a, which keeps the
+// matrix of b and a the transpose of that of a and b.
+func edgeCrossings(points []orb.Point, e, o relateEdge) []orb.Point {
+	if edgeLess(o, e) {
+		e, o = o, e
+	}
+	if e.curved || o.curved {
+		return edgeIntersections(points, e, o)
+	}
+	if !segmentsCrossProper(e.a, e.b, o.a, o.b) {
+		return points
+	}
+	ca, cb := cross2D(o.a, o.b, e.a), cross2D(o.a, o.b, e.b)
+	return append(points, segmentPoint(e.a, e.b, ca/(ca-cb)))
+}
+
+// edgeLess orders edges by their end points
+func edgeLess(e, o relateEdge) bool {
+	for _, c := range [4][2]float64{{e.a[0], o.a[0]}, {e.a[1], o.a[1]}, {e.b[0], o.b[0]}, {e.b[1], o.b[1]}} {
+		if c[0] != c[1] {
+			return c[0] < c[1]
+		}
+	}
+	return false
+}
+
+// eachNode visits every point and vertex of the geometry until visit
+// returns true, and reports whether it did. The closing vertex of a ring is
+// the same as its first and is left out.
+func (g *relateGeometry) eachNode(visit func(p orb.Point) bool) bool {
+	each := func(points []orb.Point) bool {
+		for _, p := range points {
+			if visit(p) {
+				return true
+			}
+		}
+		return false
+	}
+	if each(g.points) {
+		return true
+	}
+	for _, ls := range g.lines {
+		if each(ls) {
+			return true
+		}
+	}
+	for _, ls := range g.customLines {
+		if each(ls) {
+			return true
+		}
+	}
+	for _, ring := range g.rings {
+		points := []orb.Point(ring.ring)
+		if len(points) > 1 && pointsEqual(points[0], points[len(points)-1]) {
+			points = points[:len(points)-1]
+		}
+		if each(points) {
+			return true
+		}
+	}
+	for _, curve := range g.curves {
+		for _, e := range curve {
+			if visit(e.a) || visit(e.b) {
+				return true
+			}
+		}
+	}
+	for _, c := range g.circles {
+		if each(c.boundaryString().Points[:2]) {
+			return true
+		}
+	}
+	return false
+}
+
+// eachEdge visits every non-degenerate segment of the lines and rings, and
+// every arc of the curves and circles, until visit returns true, and
+// reports whether it did
+func (g *relateGeometry) eachEdge(visit func(e relateEdge, interiorLeft bool) bool) bool {
+	for _, curve := range g.curves {
+		for _, e := range curve {
+			if visit(e, false) {
+				return true
+			}
+		}
+	}
+	for _, ls := range g.lines {
+		if eachSegment(ls, false, visit) {
+			return true
+		}
+	}
+	return g.eachAreaEdge(visit)
+}
+
+// eachAreaEdge is eachEdge for the edges of the areas: the circles, the
+// boundary lines of the custom areas and the rings. The edges of each come
+// one after the other, in order along it.
+func (g *relateGeometry) eachAreaEdge(visit func(e relateEdge, interiorLeft bool) bool) bool {
+	g.orientRings()
+	for _, c := range g.circles {
+		for _, e := range c.boundaryString().edges() {
+			if visit(e, false) {
+				return true
+			}
+		}
+	}
+	for _, ls := range g.customLines {
+		if eachSegment(ls, false, visit) {
+			return true
+		}
+	}
+	for _, ring := range g.rings {
+		if eachSegment(ring.ring, ring.interiorLeft, visit) {
+			return true
+		}
+	}
+	return false
+}
+
+// eachSegment visits the non-degenerate segments of ls, see eachEdge
+func eachSegment(ls []orb.Point, interiorLeft bool, visit func(e relateEdge, interiorLeft bool) bool) bool {
+	for i := 0; i < len(ls)-1; i++ {
+		if !pointsEqual(ls[i], ls[i+1]) && visit(relateEdge{a: ls[i], b: ls[i+1]}, interiorLeft) {
+			return true
+		}
+	}
+	return false
+}
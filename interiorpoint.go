@@ -0,0 +1,275 @@
+package predicates
+
+import (
+	"math"
+	"sort"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/planar"
+)
+
+// InteriorPoint returns a point that is guaranteed to lie in the interior of g.
+// The result is false only for empty geometries.
+//
+// For areas the point is found with the scan-line bisector used by JTS
+// InteriorPointArea: a horizontal line is placed between the vertex
+// ordinates nearest the middle of the polygon, and the midpoint of its
+// widest section inside the polygon is returned. Unlike a centroid, this
+// point is strictly inside C-shaped and holed polygons. A Circle, whose widest
+// section is its diameter, gives its centre.
+//
+// For lines the interior vertex nearest the centroid is returned (a segment
+// midpoint if the lines have no interior vertices), and for points the point nearest
+// the centroid. Collections use their components of the highest dimension,
+// and areas that have collapsed to lines or a point are treated as those.
+func InteriorPoint(g orb.Geometry) (orb.Point, bool) {
+	if IsEmpty(g) {
+		return orb.Point{}, false
+	}
+	g = normalize(g)
+
+	switch Dimension(g) {
+	case 2:
+		var polys []orb.Polygon
+		collectPolygons(g, &polys)
+		var custom []Relater
+		collectRelaters(g, &custom)
+		for _, r := range custom {
+			polys = append(polys, relaterPolygon(r))
+		}
+		best, bestWidth := widestInteriorPoint(polys)
+
+		// A circle's widest section is its diameter through the centre
+		var circles []Circle
+		var strings []CircularString
+		collectCurves(g, &circles, &strings)
+		for _, c := range circles {
+			if 2*c.Radius > bestWidth {
+				best, bestWidth = c.Center, 2*c.Radius
+			}
+		}
+		return best, bestWidth >= 0
+	case 1:
+		var lines orb.MultiLineString
+		collectLineStrings(g, &lines)
+		// The points of a CircularString all lie on it, and every point but
+		// the first and last is in its interior
+		var circles []Circle
+		var strings []CircularString
+		collectCurves(g, &circles, &strings)
+		for _, cs := range strings {
+			lines = append(lines, orb.LineString(cs.Points))
+		}
+		return lineInteriorPoint(lines)
+	case 0:
+		var points orb.MultiPoint
+		collectPoints(g, &points)
+		return pointInteriorPoint(points)
+	}
+	return orb.Point{}, false
+}
+
+// widestInteriorPoint returns the interior point of the polygon with the
+// widest scan-line section and the width of that section, which is -1 if no
+// polygon has one
+func widestInteriorPoint(polys []orb.Polygon) (orb.Point, float64) {
+	var best orb.Point
+	bestWidth := -1.0
+	for _, poly := range polys {
+		p, width, ok := polygonInteriorPoint(poly)
+		if ok && width > bestWidth {
+			best, bestWidth = p, width
+		}
+	}
+	return best, bestWidth
+}
+
+// polygonInteriorPoint finds the midpoint of the widest section of a horizontal
+// scan line inside the polygon. It also returns the width of that section.
+func polygonInteriorPoint(poly orb.Polygon) (orb.Point, float64, bool) {
+	if len(poly) == 0 || len(poly[0]) < 4 {
+		return orb.Point{}, 0, false
+	}
+
+	scanY := scanLineY(poly)
+
+	var crossings []float64
+	for _, ring := range poly {
+		for i := 1; i < len(ring); i++ {
+			if x, ok := scanLineCrossing(ring[i-1], ring[i], scanY); ok {
+				crossings = append(crossings, x)
+			}
+		}
+	}
+	sort.Float64s(crossings)
+
+	// Crossings pair up into sections that lie inside the polygon
+	found := false
+	var best orb.Point
+	bestWidth := 0.0
+	for i := 0; i+1 < len(crossings); i += 2 {
+		width := crossings[i+1] - crossings[i]
+		if width > bestWidth {
+			best = orb.Point{(crossings[i] + crossings[i+1]) / 2, scanY}
+			bestWidth = width
+			found = true
+		}
+	}
+	return best, bestWidth, found
+}
+
+// scanLineY picks a y ordinate midway between the two vertex ordinates closest
+// to the middle of the polygon, so the scan line never passes through a vertex
+func scanLineY(poly orb.Polygon) float64 {
+	bound := poly[0].Bound()
+	centreY := (bound.Min[1] + bound.Max[1]) / 2
+	loY, hiY := bound.Min[1], bound.Max[1]
+
+	for _, ring := range poly {
+		for _, p := range ring {
+			y := p[1]
+			if y <= centreY {
+				if y > loY {
+					loY = y
+				}
+			} else if y < hiY {
+				hiY = y
+			}
+		}
+	}
+	return (loY + hiY) / 2
+}
+
+// scanLineCrossing returns the x ordinate where segment (p0, p1) crosses the
+// horizontal line at y. Vertices on the line are only counted once.
+func scanLineCrossing(p0, p1 orb.Point, y float64) (float64, bool) {
+	y0, y1 := p0[1], p1[1]
+	if (y0 > y && y1 > y) || (y0 < y && y1 < y) {
+		return 0, false
+	}
+	// Horizontal segments do not cross
+	if y0 == y1 {
+		return 0, false
+	}
+	// A downward segment does not include its start point and an upward
+	// segment does not include its end point
+	if y0 == y && y1 < y {
+		return 0, false
+	}
+	if y1 == y && y0 < y {
+		return 0, false
+	}
+
+	if p0[0] == p1[0] {
+		return p0[0], true
+	}
+	return p0[0] + (y-y0)*(p1[0]-p0[0])/(y1-y0), true
+}
+
+// lineInteriorPoint returns the interior vertex closest to the centroid of the
+// lines, or the closest segment midpoint if there are no interior vertices
+func lineInteriorPoint(mls orb.MultiLineString) (orb.Point, bool) {
+	centroid, _ := planar.CentroidArea(mls)
+
+	var best orb.Point
+	bestDist := math.Inf(1)
+	for _, ls := range mls {
+		for i := 1; i < len(ls)-1; i++ {
+			if d := planar.DistanceSquared(ls[i], centroid); d < bestDist {
+				best, bestDist = ls[i], d
+			}
+		}
+	}
+	if !math.IsInf(bestDist, 1) {
+		return best, true
+	}
+
+	// Without interior vertices, the midpoint of a segment is still interior
+	for _, ls := range mls {
+		if len(ls) < 2 {
+			continue
+		}
+		mid := orb.Point{(ls[0][0] + ls[1][0]) / 2, (ls[0][1] + ls[1][1]) / 2}
+		if d := planar.DistanceSquared(mid, centroid); d < bestDist {
+			best, bestDist = mid, d
+		}
+	}
+	return best, !math.IsInf(bestDist, 1)
+}
+
+// pointInteriorPoint returns the point closest to the centroid of the points
+func pointInteriorPoint(mp orb.MultiPoint) (orb.Point, bool) {
+	centroid, _ := planar.CentroidArea(mp)
+
+	var best orb.Point
+	bestDist := math.Inf(1)
+	for _, p := range mp {
+		if d := planar.DistanceSquared(p, centroid); d < bestDist {
+			best, bestDist = p, d
+		}
+	}
+	return best, !math.IsInf(bestDist, 1)
+}
+
+// collectPolygons appends the non-empty areal components of g as polygons
+func collectPolygons(g orb.Geometry, polys *[]orb.Polygon) {
+	if IsEmpty(g) {
+		return
+	}
+	switch geom := g.(type) {
+	case orb.Ring:
+		*polys = append(*polys, orb.Polygon{geom})
+	case orb.Polygon:
+		*polys = append(*polys, geom)
+	case orb.MultiPolygon:
+		for _, poly := range geom {
+			collectPolygons(poly, polys)
+		}
+	case orb.Bound:
+		*polys = append(*polys, boundToPolygon(geom))
+	case orb.Collection:
+		for _, part := range geom {
+			collectPolygons(part, polys)
+		}
+	}
+}
+
+// collectLineStrings appends the non-empty lineal components of g
+func collectLineStrings(g orb.Geometry, lines *orb.MultiLineString) {
+	if IsEmpty(g) {
+		return
+	}
+	switch geom := g.(type) {
+	case orb.LineString:
+		*lines = append(*lines, geom)
+	case orb.MultiLineString:
+		for _, ls := range geom {
+			collectLineStrings(ls, lines)
+		}
+	case orb.Collection:
+		for _, part := range geom {
+			collectLineStrings(part, lines)
+		}
+	}
+}
+
+// collectPoints appends the non-empty puntal components of g
+func collectPoints(g orb.Geometry, points *orb.MultiPoint) {
+	if IsEmpty(g) {
+		return
+	}
+	switch geom := g.(type) {
+	case orb.Point:
+		*points = append(*points, geom)
+	case orb.MultiPoint:
+		for _, p := range geom {
+			if !pointEmpty(p) {
+				*points = append(*points, p)
+			}
+		}
+	case orb.Collection:
+		for _, part := range geom {
+			collectPoints(part, points)
+		}
+	}
+}
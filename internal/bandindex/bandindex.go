@@ -0,0 +1,109 @@
+// Package bandindex indexes the segments of lines and rings by height, and
+// holds the segment tests used to locate points against them, for the
+// relate engine and the subpackages that locate many points
+package bandindex
+
+import (
+	"math"
+
+	"github.com/paulmach/orb"
+)
+
+// Epsilon is the tolerance of the segment tests, as in the predicates
+const Epsilon = 1e-10
+
+// Segment is an indexed segment. Ring is free for the caller to say which
+// ring or line it belongs to.
+type Segment struct {
+	A, B orb.Point
+	Ring int32
+}
+
+// Index divides the y extent of a geometry into bands of equal height and
+// lists the segments that reach into each, widened by Epsilon
+type Index struct {
+	minY, height float64
+	offsets      []int // segments of band i are segments[offsets[i]:offsets[i+1]]
+	segments     []Segment
+}
+
+// New indexes count segments within env in bands that hold a handful of
+// segments each. each visits the segments, and is called twice: once to
+// count the segments in each band, and once to fill them in.
+func New(env orb.Bound, count int, each func(visit func(Segment))) *Index {
+	bands := min(max(count/8, 1), 4096)
+	minY, maxY := env.Min[1]-Epsilon, env.Max[1]+Epsilon
+	idx := &Index{minY: minY, height: (maxY - minY) / float64(bands)}
+	if !(idx.height > 0) {
+		bands, idx.height = 1, 1
+	}
+	idx.offsets = make([]int, bands+1)
+
+	each(func(s Segment) {
+		for band := idx.band(min(s.A[1], s.B[1]) - Epsilon); band <= idx.band(max(s.A[1], s.B[1])+Epsilon); band++ {
+			idx.offsets[band+1]++
+		}
+	})
+	for band := 1; band <= bands; band++ {
+		idx.offsets[band] += idx.offsets[band-1]
+	}
+	idx.segments = make([]Segment, idx.offsets[bands])
+	fill := append([]int(nil), idx.offsets[:bands]...)
+	each(func(s Segment) {
+		for band := idx.band(min(s.A[1], s.B[1]) - Epsilon); band <= idx.band(max(s.A[1], s.B[1])+Epsilon); band++ {
+			idx.segments[fill[band]] = s
+			fill[band]++
+		}
+	})
+	return idx
+}
+
+// band returns the band that y falls in, clamped to the bands there are
+func (idx *Index) band(y float64) int {
+	b := int((y - idx.minY) / idx.height)
+	return min(max(b, 0), len(idx.offsets)-2)
+}
+
+// Near returns the segments of the band that y falls in
+func (idx *Index) Near(y float64) []Segment {
+	return idx.Between(y, y)
+}
+
+// Between returns the segments of the bands from the one lo falls in to the
+// one hi falls in. A segment that reaches into several of them is listed
+// once for each.
+func (idx *Index) Between(lo, hi float64) []Segment {
+	return idx.segments[idx.offsets[idx.band(lo)]:idx.offsets[idx.band(hi)+1]]
+}
+
+// OnSegment checks if p lies on segment ab, within Epsilon
+func OnSegment(p, a, b orb.Point) bool {
+	cross := (b[0]-a[0])*(p[1]-a[1]) - (b[1]-a[1])*(p[0]-a[0])
+	return math.Abs(cross) <= Epsilon &&
+		p[0] >= math.Min(a[0], b[0])-Epsilon && p[0] <= math.Max(a[0], b[0])+Epsilon &&
+		p[1] >= math.Min(a[1], b[1])-Epsilon && p[1] <= math.Max(a[1], b[1])+Epsilon
+}
+
+// CrossesRay checks if segment ab, which p is not on, crosses the ray from p
+// to the right. A vertex at the height of p counts for the segment above it,
+// so a ray through a vertex crosses the ring there once or not at all.
+func CrossesRay(p, a, b orb.Point) bool {
+	return (a[1] > p[1]) != (b[1] > p[1]) && p[0] < a[0]+(p[1]-a[1])*(b[0]-a[0])/(b[1]-a[1])
+}
+
+// Covers checks if p is in the rings the segments are from or on one of
+// them, counting the segments a ray from p to the right crosses. The
+// segments must be all those of the rings that reach the height of p, such
+// as the ones an Index has near it.
+func Covers(segments []Segment, p orb.Point) bool {
+	inside := false
+	for _, s := range segments {
+		if OnSegment(p, s.A, s.B) {
+			return true
+		}
+		if CrossesRay(p, s.A, s.B) {
+			inside = !inside
+		}
+	}
+	return inside
+}
@@ -0,0 +1,94 @@
+package bandindex
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestIndex(t *testing.T) {
+	// A star with many edges, and a square hole
+	var shell []orb.Point
+	for i := range 200 {
+		a, r := float64(i)*math.Pi/100, 40.0
+		if i%2 == 1 {
+			r = 25
+		}
+		shell = append(shell, orb.Point{50 + r*math.Cos(a), 50 + r*math.Sin(a)})
+	}
+	shell = append(shell, shell[0])
+	rings := [][]orb.Point{shell, {{45, 45}, {45, 55}, {55, 55}, {55, 45}, {45, 45}}}
+	var all []Segment
+	for ri, ring := range rings {
+		for i := 1; i < len(ring); i++ {
+			all = append(all, Segment{ring[i-1], ring[i], int32(ri)})
+		}
+	}
+	env := orb.Bound{Min: orb.Point{10, 10}, Max: orb.Point{90, 90}}
+	idx := New(env, len(all), func(visit func(Segment)) {
+		for _, s := range all {
+			visit(s)
+		}
+	})
+	if n := len(idx.Near(50)); n > len(all)/4 {
+		t.Fatalf("%d of %d segments near y = 50, expected them to be banded", n, len(all))
+	}
+
+	// Every segment that reaches a height is near it, and the bands give
+	// what testing every segment does
+	r := rand.New(rand.NewPCG(1, 2))
+	for range 2000 {
+		p := orb.Point{r.Float64() * 100, r.Float64() * 100}
+		near := idx.Near(p[1])
+		for _, s := range all {
+			if min(s.A[1], s.B[1]) <= p[1] && p[1] <= max(s.A[1], s.B[1]) && !contains(near, s) {
+				t.Fatalf("segment %v is not near y = %v", s, p[1])
+			}
+		}
+		if got, want := Covers(near, p), Covers(all, p); got != want {
+			t.Errorf("Covers(%v) = %v with the index, %v without", p, got, want)
+		}
+	}
+	if got := idx.Between(0, 100); len(got) < len(all) {
+		t.Errorf("Between the ends has %d segments, expected at least %d", len(got), len(all))
+	}
+}
+
+func contains(segments []Segment, s Segment) bool {
+	for _, o := range segments {
+		if o == s {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSegmentTests(t *testing.T) {
+	a, b := orb.Point{0, 0}, orb.Point{10, 10}
+	for _, tt := range []struct {
+		p    orb.Point
+		want bool
+	}{
+		{orb.Point{5, 5}, true},
+		{a, true},
+		{b, true},
+		{orb.Point{5, 5 + Epsilon/100}, true},
+		{orb.Point{5, 6}, false},
+		{orb.Point{11, 11}, false},
+	} {
+		if got := OnSegment(tt.p, a, b); got != tt.want {
+			t.Errorf("OnSegment(%v) = %v, expected %v", tt.p, got, tt.want)
+		}
+	}
+
+	// A ray through a vertex crosses only the segment above it
+	p := orb.Point{0, 5}
+	if !CrossesRay(p, orb.Point{5, 0}, orb.Point{5, 10}) || CrossesRay(p, orb.Point{-5, 0}, orb.Point{-5, 10}) {
+		t.Error("CrossesRay should only count segments to the right")
+	}
+	if !CrossesRay(p, orb.Point{5, 5}, orb.Point{5, 10}) || CrossesRay(p, orb.Point{5, 0}, orb.Point{5, 5}) {
+		t.Error("CrossesRay should count a vertex at the height of p for the segment above it")
+	}
+}
@@ -0,0 +1,110 @@
+// Package boxtree is a static R-tree of bounding boxes, for the subpackages
+// that search many geometries by their envelopes
+package boxtree
+
+import (
+	"sort"
+
+	"github.com/paulmach/orb"
+)
+
+// nodeSize is the number of entries in each node of the bounding box tree
+const nodeSize = 16
+
+// Tree is a static R-tree of bounding boxes, packed with the
+// Sort-Tile-Recursive method so that nearby boxes share nodes
+type Tree struct {
+	root   *boxNode
+	bounds []orb.Bound
+}
+
+// boxNode is a node of a Tree. Leaves hold item numbers, other nodes children.
+type boxNode struct {
+	bound    orb.Bound
+	children []*boxNode
+	items    []int
+}
+
+// New packs the boxes of the given items into a tree. Items are indexes
+// into bounds.
+func New(items []int, bounds []orb.Bound) *Tree {
+	if len(items) == 0 {
+		return &Tree{bounds: bounds}
+	}
+	var level []*boxNode
+	for _, group := range strGroups(len(items), func(i int) orb.Bound { return bounds[items[i]] }) {
+		leaf := &boxNode{bound: bounds[items[group[0]]]}
+		for _, i := range group {
+			leaf.items = append(leaf.items, items[i])
+			leaf.bound = leaf.bound.Union(bounds[items[i]])
+		}
+		level = append(level, leaf)
+	}
+	for len(level) > 1 {
+		var next []*boxNode
+		for _, group := range strGroups(len(level), func(i int) orb.Bound { return level[i].bound }) {
+			parent := &boxNode{bound: level[group[0]].bound}
+			for _, i := range group {
+				parent.children = append(parent.children, level[i])
+				parent.bound = parent.bound.Union(level[i].bound)
+			}
+			next = append(next, parent)
+		}
+		level = next
+	}
+	return &Tree{root: level[0], bounds: bounds}
+}
+
+// strGroups splits n entries into groups of up to nodeSize: the entries are
+// sorted by x into vertical slices, and each slice by y
+func strGroups(n int, bound func(i int) orb.Bound) [][]int {
+	centre := func(i, axis int) float64 {
+		b := bound(i)
+		return b.Min[axis] + b.Max[axis]
+	}
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return centre(order[i], 0) < centre(order[j], 0) })
+
+	nodes := (n + nodeSize - 1) / nodeSize
+	slices := 1
+	for slices*slices < nodes {
+		slices++
+	}
+	perSlice := slices * nodeSize
+
+	var groups [][]int
+	for start := 0; start < n; start += perSlice {
+		slice := order[start:min(start+perSlice, n)]
+		sort.Slice(slice, func(i, j int) bool { return centre(slice[i], 1) < centre(slice[j], 1) })
+		for k := 0; k < len(slice); k += nodeSize {
+			groups = append(groups, slice[k:min(k+nodeSize, len(slice))])
+		}
+	}
+	return groups
+}
+
+// Search appends the items whose boxes overlap b
+func (t *Tree) Search(b orb.Bound, items []int) []int {
+	if t.root == nil {
+		return items
+	}
+	var visit func(n *boxNode)
+	visit = func(n *boxNode) {
+		if !n.bound.Intersects(b) {
+			return
+		}
+		for _, child := range n.children {
+			visit(child)
+		}
+		for _, item := range n.items {
+			if t.bounds[item].Intersects(b) {
+				items = append(items, item)
+			}
+		}
+	}
+	visit(t.root)
+	return items
+}
@@ -0,0 +1,47 @@
+package boxtree
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestSearch(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	box := func() orb.Bound {
+		p := orb.Point{r.Float64() * 100, r.Float64() * 100}
+		return orb.Bound{Min: p, Max: orb.Point{p[0] + r.Float64()*5, p[1] + r.Float64()*5}}
+	}
+
+	// Every item but the first is indexed
+	bounds := make([]orb.Bound, 1000)
+	var items []int
+	for i := range bounds {
+		bounds[i] = box()
+		if i > 0 {
+			items = append(items, i)
+		}
+	}
+	tree := New(items, bounds)
+
+	for range 100 {
+		query := box()
+		var want []int
+		for _, i := range items {
+			if bounds[i].Intersects(query) {
+				want = append(want, i)
+			}
+		}
+		got := tree.Search(query, nil)
+		slices.Sort(got)
+		if !slices.Equal(got, want) {
+			t.Fatalf("Search(%v) = %v, expected %v", query, got, want)
+		}
+	}
+
+	if got := New(nil, nil).Search(box(), nil); len(got) != 0 {
+		t.Errorf("empty tree found %v", got)
+	}
+}
@@ -0,0 +1,106 @@
+// Package jtsxml reads and runs the XML test files of the JTS Topology
+// Suite, so that the predicates, or wrappers around them, can be checked
+// against any directory of fixtures.
+//
+// A file is a <run> of <case> elements, each with geometries a and b in WKT
+// and <test> elements naming an operation, its arguments and the expected
+// result:
+//
+//	<run>
+//	  <case>
+//	    <desc>point in polygon</desc>
+//	    <a>POINT (1 1)</a>
+//	    <b>POLYGON ((0 0, 2 0, 2 2, 0 2, 0 0))</b>
+//	    <test><op name="within" arg1="A" arg2="B">true</op></test>
+//	  </case>
+//	</run>
+//
+// A Runner evaluates the operations through an OpTable, which maps names to
+// OpFuncs, and reports every case as passed, failed or skipped.
+package jtsxml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+
+	predicates "github.com/tingold/orb-predicates"
+)
+
+// Run is the root element of a JTS test file
+type Run struct {
+	XMLName xml.Name `xml:"run"`
+
+	// Desc describes the file
+	Desc string `xml:"desc,omitempty"`
+
+	// BoundaryNodeRule names the rule the cases are evaluated with
+	// (Mod2, EndPoint, MultiValent or MonoValent). It defaults to Mod2.
+	BoundaryNodeRule string `xml:"boundaryNodeRule,omitempty"`
+
+	Cases []Case `xml:"case"`
+}
+
+// Case is a test case: two geometries in WKT and the operations on them
+type Case struct {
+	Desc  string `xml:"desc"`
+	A     string `xml:"a"`
+	B     string `xml:"b,omitempty"`
+	Tests []Test `xml:"test"`
+}
+
+// Test holds one operation of a case
+type Test struct {
+	Op Op `xml:"op"`
+}
+
+// Op is an operation and its expected result. Arg1 and Arg2 name the
+// geometries, "A" or "B", and Arg3 holds further arguments such as the
+// pattern of relate.
+type Op struct {
+	Name     string `xml:"name,attr"`
+	Arg1     string `xml:"arg1,attr"`
+	Arg2     string `xml:"arg2,attr,omitempty"`
+	Arg3     string `xml:"arg3,attr,omitempty"`
+	Expected string `xml:",chardata"`
+}
+
+// Parse decodes a JTS test file
+func Parse(data []byte) (*Run, error) {
+	var run Run
+	if err := xml.Unmarshal(data, &run); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// ParseFile reads and decodes a JTS test file
+func ParseFile(path string) (*Run, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	run, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return run, nil
+}
+
+// ParseBoundaryNodeRule parses the boundaryNodeRule element of a run. The
+// empty string is Mod2Rule.
+func ParseBoundaryNodeRule(s string) (predicates.BoundaryNodeRule, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return predicates.Mod2Rule, nil
+	}
+	for _, rule := range []predicates.BoundaryNodeRule{
+		predicates.Mod2Rule, predicates.EndPointRule, predicates.MultiValentRule, predicates.MonoValentRule,
+	} {
+		if strings.EqualFold(s, rule.String()) {
+			return rule, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown boundary node rule %q", s)
+}
@@ -0,0 +1,96 @@
+package jtsxml
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/paulmach/orb"
+
+	predicates "github.com/tingold/orb-predicates"
+)
+
+// ErrSkip is returned, possibly wrapped, by an OpFunc that cannot evaluate
+// its arguments, so that the operation is skipped rather than failed
+var ErrSkip = errors.New("jtsxml: skipped")
+
+// Args are the arguments of an operation
+type Args struct {
+	// A and B are the geometries named by arg1 and arg2. B is nil when the
+	// operation has no arg2.
+	A, B orb.Geometry
+
+	// Arg3 is the third argument as written, such as the pattern of relate
+	Arg3 string
+
+	// Rule is the boundary node rule of the run
+	Rule predicates.BoundaryNodeRule
+}
+
+// OpFunc evaluates an operation. It returns a bool for predicates, an
+// orb.Geometry for overlay operations such as intersection, or a string,
+// which is compared with the expected result.
+type OpFunc func(args Args) (interface{}, error)
+
+// OpTable maps lower case operation names to their implementations.
+// Operations missing from the table are skipped.
+type OpTable map[string]OpFunc
+
+// Predicate returns an OpFunc for a binary predicate, which is evaluated the
+// same under every boundary node rule
+//
+//	ops := jtsxml.DefaultOps()
+//	ops["within"] = jtsxml.Predicate(myWithin)
+func Predicate(pred func(a, b orb.Geometry) bool) OpFunc {
+	return func(args Args) (interface{}, error) {
+		if args.B == nil {
+			return nil, ErrSkip
+		}
+		return pred(args.A, args.B), nil
+	}
+}
+
+// Overlay returns an OpFunc for an overlay operation such as intersection,
+// whose result is compared with the expected geometry by Runner.Equal
+func Overlay(op func(a, b orb.Geometry) orb.Geometry) OpFunc {
+	return func(args Args) (interface{}, error) {
+		if args.B == nil {
+			return nil, ErrSkip
+		}
+		return op(args.A, args.B), nil
+	}
+}
+
+// predicateOp returns an OpFunc for a predicate under the run's boundary
+// node rule
+func predicateOp(p predicates.PredicateSet) OpFunc {
+	return func(args Args) (interface{}, error) {
+		if args.B == nil {
+			return nil, ErrSkip
+		}
+		r, err := predicates.WithBoundaryNodeRule(args.Rule).Evaluate(args.A, args.B, p)
+		return r != 0, err
+	}
+}
+
+// DefaultOps returns a new table of the operations of the predicates
+// package: the predicates by their names in the package, including equals
+// and its JTS name equalstopo, and relate with a pattern in arg3
+func DefaultOps() OpTable {
+	ops := OpTable{"relate": relateOp}
+	for set := predicates.AllPredicates; set != 0; set &= set - 1 {
+		p := set & -set
+		ops[p.String()] = predicateOp(p)
+	}
+	ops["equalstopo"] = ops["equals"]
+	return ops
+}
+
+// relateOp matches the DE-9IM matrix of the arguments against the pattern in arg3
+func relateOp(args Args) (interface{}, error) {
+	pattern := strings.TrimSpace(args.Arg3)
+	if args.B == nil || pattern == "" {
+		return nil, ErrSkip
+	}
+	matrix := predicates.WithBoundaryNodeRule(args.Rule).Relate(args.A, args.B)
+	return predicates.RelateMatch(matrix, pattern), nil
+}
@@ -0,0 +1,109 @@
+package jtsxml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/paulmach/orb"
+
+	predicates "github.com/tingold/orb-predicates"
+)
+
+// Recorder collects predicate cases, such as suspicious answers seen in
+// production, and writes them as a JTS test file that Runner, jtsrun and
+// TestJTSPredicates replay. The geometries are written with predicates.FormatWKT, so
+// the cases reproduce exactly. A Recorder is safe for concurrent use; the
+// zero Recorder records cases under the Mod-2 rule.
+//
+//	var rec jtsxml.Recorder
+//	if got := predicates.Within(a, b); got != want {
+//		rec.Record("parcel 1234 in zone 7", "within", a, b, want)
+//	}
+//	rec.WriteFile("testdata/jts/TestRegressions.xml")
+type Recorder struct {
+	// Desc describes the file
+	Desc string
+
+	// Rule is the boundary node rule the cases are evaluated with
+	Rule predicates.BoundaryNodeRule
+
+	mu    sync.Mutex
+	cases []Case
+}
+
+// Record adds a case in which op(a, b), such as "within", is expected to be
+// expected. It returns an error if a geometry has no WKT form.
+func (r *Recorder) Record(desc, op string, a, b orb.Geometry, expected bool) error {
+	return r.record(desc, Op{Name: op, Arg1: "A", Arg2: "B", Expected: strconv.FormatBool(expected)}, a, b)
+}
+
+// RecordRelate adds a case in which the DE-9IM matrix of a and b is expected
+// to match pattern, or not
+func (r *Recorder) RecordRelate(desc string, a, b orb.Geometry, pattern string, expected bool) error {
+	return r.record(desc, Op{Name: "relate", Arg1: "A", Arg2: "B", Arg3: pattern, Expected: strconv.FormatBool(expected)}, a, b)
+}
+
+func (r *Recorder) record(desc string, op Op, a, b orb.Geometry) error {
+	if strings.TrimSpace(op.Name) == "" {
+		return fmt.Errorf("jtsxml: record %q without an operation", desc)
+	}
+	wktA, err := predicates.FormatWKT(a)
+	if err != nil {
+		return fmt.Errorf("jtsxml: record %q: geometry A: %w", desc, err)
+	}
+	wktB, err := predicates.FormatWKT(b)
+	if err != nil {
+		return fmt.Errorf("jtsxml: record %q: geometry B: %w", desc, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cases = append(r.cases, Case{Desc: desc, A: wktA, B: wktB, Tests: []Test{{Op: op}}})
+	return nil
+}
+
+// Len returns the number of recorded cases
+func (r *Recorder) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.cases)
+}
+
+// Run returns the recorded cases as a Run
+func (r *Recorder) Run() *Run {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	run := &Run{Desc: r.Desc, Cases: append([]Case(nil), r.cases...)}
+	if r.Rule != predicates.Mod2Rule {
+		run.BoundaryNodeRule = r.Rule.String()
+	}
+	return run
+}
+
+// WriteTo writes the recorded cases as a JTS test file
+func (r *Recorder) WriteTo(w io.Writer) (int64, error) {
+	data, err := xml.MarshalIndent(r.Run(), "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(append(data, '\n'))
+	return int64(n), err
+}
+
+// WriteFile writes the recorded cases as a JTS test file at path
+func (r *Recorder) WriteFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := r.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,126 @@
+package jtsxml
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/paulmach/orb"
+
+	predicates "github.com/tingold/orb-predicates"
+)
+
+func TestRecorder(t *testing.T) {
+	// Coordinates that do not survive %g with a fixed precision
+	a := orb.Point{0.30000000000000004, 1.0 / 3}
+	b := orb.Polygon{{{0, 0}, {0.30000000000000004, 0}, {0.30000000000000004, 1}, {0, 1}, {0, 0}}}
+	line := orb.LineString{{-1e-300, 123456789.12345679}, {math.Nextafter(1, 2), -2.5e21}}
+
+	rec := Recorder{Desc: "recorded", Rule: predicates.EndPointRule}
+	records := []struct {
+		op       string
+		a, b     orb.Geometry
+		expected bool
+	}{
+		{"within", a, b, false},
+		{"touches", a, b, true},
+		{"intersects", line, b, true},
+		{"coveredBy", orb.Ring(b[0]), orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{0.30000000000000004, 1}}, true},
+	}
+	for _, r := range records {
+		if err := rec.Record(r.op, r.op, r.a, r.b, r.expected); err != nil {
+			t.Fatalf("Record(%s) error: %v", r.op, err)
+		}
+	}
+	if err := rec.RecordRelate("relate", a, b, "F0FFFF212", true); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Len() != 5 {
+		t.Errorf("Len() = %d, expected 5", rec.Len())
+	}
+
+	path := filepath.Join(t.TempDir(), "TestRecorded.xml")
+	if err := rec.WriteFile(path); err != nil {
+		t.Fatal(err)
+	}
+	run, err := ParseFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Desc != "recorded" || run.BoundaryNodeRule != "EndPoint" || len(run.Cases) != 5 {
+		t.Fatalf("ParseFile() = %+v, expected the recorded run", run)
+	}
+
+	// The geometries are read back exactly
+	for i, r := range records {
+		gotA, errA := predicates.ParseWKT(run.Cases[i].A)
+		gotB, errB := predicates.ParseWKT(run.Cases[i].B)
+		if errA != nil || errB != nil {
+			t.Fatalf("case %d: %v, %v", i, errA, errB)
+		}
+		if want, ok := r.a.(orb.Ring); ok {
+			r.a = orb.Polygon{want}
+		}
+		if want, ok := r.b.(orb.Bound); ok {
+			r.b = want.ToPolygon()
+		}
+		if !orb.Equal(gotA, r.a) || !orb.Equal(gotB, r.b) {
+			t.Errorf("case %d read back as %v and %v, expected %v and %v", i, gotA, gotB, r.a, r.b)
+		}
+	}
+
+	// and replay with the recorded expectations
+	report, err := Runner{}.RunFiles(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range report.Cases {
+		if c.Status != Pass {
+			t.Errorf("recorded case %s = %v: %+v", c.Key(), c.Status, c.Ops)
+		}
+	}
+}
+
+func TestRecorderErrors(t *testing.T) {
+	var rec Recorder
+	circle := predicates.Circle{Center: orb.Point{0, 0}, Radius: 1}
+	if err := rec.Record("circle", "within", orb.Point{0, 0}, circle, true); !errors.Is(err, predicates.ErrUnsupportedType) {
+		t.Errorf("Record(circle) error = %v, expected ErrUnsupportedType", err)
+	}
+	if err := rec.Record("no op", "", orb.Point{0, 0}, orb.Point{0, 0}, true); err == nil {
+		t.Error("Record without an operation expected an error")
+	}
+	if rec.Len() != 0 {
+		t.Errorf("Len() = %d after failed records, expected 0", rec.Len())
+	}
+}
+
+func TestRecorderConcurrent(t *testing.T) {
+	var rec Recorder
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				rec.Record("point", "intersects", orb.Point{float64(i), float64(j)}, orb.Point{float64(i), float64(j)}, true)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	var buf bytes.Buffer
+	if _, err := rec.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(buf.String(), "<case>"); n != 80 {
+		t.Errorf("wrote %d cases, expected 80", n)
+	}
+	if strings.Contains(buf.String(), "boundaryNodeRule") {
+		t.Error("the Mod-2 rule should not be written")
+	}
+}
@@ -0,0 +1,297 @@
+package jtsxml
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/paulmach/orb"
+
+	predicates "github.com/tingold/orb-predicates"
+)
+
+// Status is the outcome of an operation or a case
+type Status int
+
+const (
+	// Pass means the result was the expected one
+	Pass Status = iota
+	// Fail means the result differed from the expected one, or the
+	// operation returned an error
+	Fail
+	// Skip means the operation was not evaluated: it is not in the
+	// OpTable, a geometry could not be parsed, or the OpFunc returned ErrSkip
+	Skip
+)
+
+// String returns the name of the status
+func (s Status) String() string {
+	switch s {
+	case Pass:
+		return "pass"
+	case Fail:
+		return "fail"
+	case Skip:
+		return "skip"
+	}
+	return "Status(?)"
+}
+
+// MarshalText encodes the status by name
+func (s Status) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// OpResult is the outcome of one operation of a case
+type OpResult struct {
+	Name     string `json:"name"`
+	Arg1     string `json:"arg1"`
+	Arg2     string `json:"arg2,omitempty"`
+	Arg3     string `json:"arg3,omitempty"`
+	Status   Status `json:"status"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual,omitempty"`
+
+	// Reason says why the operation failed or was skipped
+	Reason string `json:"reason,omitempty"`
+}
+
+// CaseResult is the outcome of a case. It failed if any operation failed,
+// was skipped if no operation passed or failed, and passed otherwise.
+type CaseResult struct {
+	File   string     `json:"file"`
+	Index  int        `json:"index"`
+	Desc   string     `json:"desc"`
+	A      string     `json:"a"`
+	B      string     `json:"b,omitempty"`
+	Status Status     `json:"status"`
+	Ops    []OpResult `json:"ops"`
+}
+
+// Key identifies the case by the base name of its file and its number in
+// the file, counted from 1, e.g. "TestRelateAA.xml#12"
+func (c CaseResult) Key() string {
+	return fmt.Sprintf("%s#%d", filepath.Base(c.File), c.Index+1)
+}
+
+// Report holds the results of the cases of one or more files
+type Report struct {
+	Cases []CaseResult `json:"cases"`
+}
+
+// Count returns the number of cases with status s
+func (r *Report) Count(s Status) int {
+	n := 0
+	for _, c := range r.Cases {
+		if c.Status == s {
+			n++
+		}
+	}
+	return n
+}
+
+// Failed returns the failed cases
+func (r *Report) Failed() []CaseResult {
+	var failed []CaseResult
+	for _, c := range r.Cases {
+		if c.Status == Fail {
+			failed = append(failed, c)
+		}
+	}
+	return failed
+}
+
+// Runner evaluates the cases of JTS test files. The zero Runner uses
+// DefaultOps and predicates.Equals.
+type Runner struct {
+	// Ops are the operations that are evaluated; others are skipped
+	Ops OpTable
+
+	// Equal compares geometry results with the expected geometry
+	Equal func(a, b orb.Geometry) bool
+}
+
+// RunDir runs every .xml file in dir, in name order
+func (r Runner) RunDir(dir string) (*Report, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.xml"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return r.RunFiles(files...)
+}
+
+// RunFiles runs the named files. It stops at the first file that cannot be
+// read or parsed.
+func (r Runner) RunFiles(paths ...string) (*Report, error) {
+	report := &Report{}
+	for _, path := range paths {
+		run, err := ParseFile(path)
+		if err != nil {
+			return nil, err
+		}
+		cases, err := r.Run(path, run)
+		if err != nil {
+			return nil, err
+		}
+		report.Cases = append(report.Cases, cases...)
+	}
+	return report, nil
+}
+
+// Run evaluates the cases of a parsed run, recording file as their file
+func (r Runner) Run(file string, run *Run) ([]CaseResult, error) {
+	rule, err := ParseBoundaryNodeRule(run.BoundaryNodeRule)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	results := make([]CaseResult, len(run.Cases))
+	for i, c := range run.Cases {
+		results[i] = r.RunCase(c, Args{Rule: rule})
+		results[i].File = file
+		results[i].Index = i
+	}
+	return results, nil
+}
+
+// RunCase evaluates the operations of a case. Only the Rule of args is used;
+// the geometries are parsed from the case.
+func (r Runner) RunCase(c Case, args Args) CaseResult {
+	result := CaseResult{
+		Desc: strings.TrimSpace(c.Desc),
+		A:    strings.TrimSpace(c.A),
+		B:    strings.TrimSpace(c.B),
+	}
+
+	geoms := make(map[string]orb.Geometry, 2)
+	var parseErrs []string
+	for name, text := range map[string]string{"a": result.A, "b": result.B} {
+		if text == "" {
+			continue
+		}
+		g, err := predicates.ParseWKT(text)
+		if err != nil {
+			parseErrs = append(parseErrs, fmt.Sprintf("geometry %s: %v", strings.ToUpper(name), err))
+			continue
+		}
+		geoms[name] = g
+	}
+	sort.Strings(parseErrs)
+
+	for _, test := range c.Tests {
+		op := test.Op
+		res := OpResult{
+			Name:     op.Name,
+			Arg1:     op.Arg1,
+			Arg2:     op.Arg2,
+			Arg3:     op.Arg3,
+			Expected: strings.TrimSpace(op.Expected),
+		}
+		fn, ok := r.ops()[strings.ToLower(op.Name)]
+		switch {
+		case !ok:
+			res.Status, res.Reason = Skip, "operation not implemented"
+		case len(parseErrs) > 0:
+			res.Status, res.Reason = Skip, strings.Join(parseErrs, "; ")
+		default:
+			r.evaluate(&res, fn, geoms, args)
+		}
+		result.Ops = append(result.Ops, res)
+	}
+
+	result.Status = Skip
+	for _, res := range result.Ops {
+		if res.Status == Fail {
+			result.Status = Fail
+			break
+		}
+		if res.Status == Pass {
+			result.Status = Pass
+		}
+	}
+	return result
+}
+
+// evaluate runs fn on the geometries named by the operation and compares its
+// result with the expected one
+func (r Runner) evaluate(res *OpResult, fn OpFunc, geoms map[string]orb.Geometry, args Args) {
+	a, okA := geoms[strings.ToLower(res.Arg1)]
+	b, okB := geoms[strings.ToLower(res.Arg2)]
+	if !okA || (res.Arg2 != "" && !okB) {
+		res.Status, res.Reason = Skip, "missing geometry argument"
+		return
+	}
+	args.A, args.B, args.Arg3 = a, b, res.Arg3
+
+	actual, err := fn(args)
+	if errors.Is(err, ErrSkip) {
+		res.Status, res.Reason = Skip, err.Error()
+		return
+	}
+	if err != nil {
+		res.Status, res.Reason = Fail, err.Error()
+		return
+	}
+
+	pass, err := r.compare(actual, res.Expected)
+	res.Actual = formatResult(actual)
+	switch {
+	case err != nil:
+		res.Status, res.Reason = Fail, err.Error()
+	case pass:
+		res.Status = Pass
+	default:
+		res.Status = Fail
+	}
+}
+
+// compare checks an operation's result against the expected text
+func (r Runner) compare(actual interface{}, expected string) (bool, error) {
+	switch actual := actual.(type) {
+	case bool:
+		switch strings.ToLower(expected) {
+		case "true":
+			return actual, nil
+		case "false":
+			return !actual, nil
+		}
+		return false, fmt.Errorf("expected result %q is not a boolean", expected)
+	case orb.Geometry:
+		g, err := predicates.ParseWKT(expected)
+		if err != nil {
+			return false, fmt.Errorf("expected result: %v", err)
+		}
+		equal := r.Equal
+		if equal == nil {
+			equal = predicates.Equals
+		}
+		return equal(actual, g), nil
+	case string:
+		return strings.EqualFold(strings.TrimSpace(actual), expected), nil
+	}
+	return false, fmt.Errorf("unsupported result type %T", actual)
+}
+
+// ops returns the Runner's operations, or the defaults
+func (r Runner) ops() OpTable {
+	if r.Ops == nil {
+		return defaultOps
+	}
+	return r.Ops
+}
+
+// defaultOps is the table of the zero Runner
+var defaultOps = DefaultOps()
+
+// formatResult writes an operation's result for a report
+func formatResult(v interface{}) string {
+	switch v := v.(type) {
+	case orb.Geometry:
+		if s, err := predicates.FormatWKT(v); err == nil {
+			return s
+		}
+	}
+	return fmt.Sprint(v)
+}
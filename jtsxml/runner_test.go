@@ -0,0 +1,142 @@
+package jtsxml
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/paulmach/orb"
+
+	predicates "github.com/tingold/orb-predicates"
+)
+
+const testRun = `<run>
+  <desc>runner test</desc>
+  <case>
+    <desc>point in square</desc>
+    <a>POINT (1 1)</a>
+    <b>POLYGON ((0 0, 2 0, 2 2, 0 2, 0 0))</b>
+    <test><op name="within" arg1="A" arg2="B">true</op></test>
+    <test><op name="relate" arg1="A" arg2="B" arg3="0FFFFF212">true</op></test>
+    <test><op name="contains" arg1="B" arg2="A">true</op></test>
+  </case>
+  <case>
+    <desc>wrong expectation</desc>
+    <a>POINT (5 5)</a>
+    <b>POLYGON ((0 0, 2 0, 2 2, 0 2, 0 0))</b>
+    <test><op name="intersects" arg1="A" arg2="B">true</op></test>
+    <test><op name="disjoint" arg1="A" arg2="B">true</op></test>
+  </case>
+  <case>
+    <desc>unknown operation</desc>
+    <a>POINT (1 1)</a>
+    <test><op name="isValid" arg1="A">true</op></test>
+  </case>
+  <case>
+    <desc>bad geometry</desc>
+    <a>POINT (1</a>
+    <b>POINT (1 1)</b>
+    <test><op name="within" arg1="A" arg2="B">true</op></test>
+  </case>
+  <case>
+    <desc>intersection</desc>
+    <a>LINESTRING (0 0, 4 0)</a>
+    <b>LINESTRING (2 0, 6 0)</b>
+    <test><op name="intersection" arg1="A" arg2="B">LINESTRING (4 0, 2 0)</op></test>
+  </case>
+</run>`
+
+func TestRunner(t *testing.T) {
+	run, err := Parse([]byte(testRun))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Overlay operations are plugged in; this one only clips collinear
+	// horizontal segments
+	ops := DefaultOps()
+	ops["intersection"] = Overlay(func(a, b orb.Geometry) orb.Geometry {
+		la, lb := a.(orb.LineString), b.(orb.LineString)
+		return orb.LineString{{max(la[0][0], lb[0][0]), 0}, {min(la[1][0], lb[1][0]), 0}}
+	})
+	ops["isvalid"] = func(args Args) (interface{}, error) {
+		return nil, errors.New("not a binary operation")
+	}
+
+	tests := []struct {
+		ops      OpTable
+		expected []Status
+	}{
+		{nil, []Status{Pass, Fail, Skip, Skip, Skip}},
+		{ops, []Status{Pass, Fail, Fail, Skip, Pass}},
+	}
+	for _, tt := range tests {
+		cases, err := Runner{Ops: tt.ops}.Run("test.xml", run)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, c := range cases {
+			if c.Status != tt.expected[i] {
+				t.Errorf("case %s = %v, expected %v: %+v", c.Key(), c.Status, tt.expected[i], c.Ops)
+			}
+		}
+	}
+
+	cases, _ := Runner{}.Run("test.xml", run)
+	wrong := cases[1]
+	if wrong.Key() != "test.xml#2" || wrong.Ops[0].Status != Fail || wrong.Ops[0].Actual != "false" || wrong.Ops[1].Status != Pass {
+		t.Errorf("wrong expectation = %+v, expected intersects to fail with false", wrong)
+	}
+	if reason := cases[3].Ops[0].Reason; reason == "" {
+		t.Error("skipped case should have a reason")
+	}
+}
+
+func TestRunnerCustomPredicate(t *testing.T) {
+	run, err := Parse([]byte(testRun))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A wrapper that is always wrong fails the cases that use it
+	ops := DefaultOps()
+	ops["within"] = Predicate(func(a, b orb.Geometry) bool { return !predicates.Within(a, b) })
+	cases, err := Runner{Ops: ops}.Run("test.xml", run)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cases[0].Status != Fail || cases[0].Ops[0].Status != Fail || cases[0].Ops[1].Status != Pass {
+		t.Errorf("case with a broken predicate = %+v, expected within to fail", cases[0])
+	}
+}
+
+func TestRunDir(t *testing.T) {
+	report, err := Runner{}.RunDir("../testdata/jts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Cases) == 0 {
+		t.Fatal("no cases found in ../testdata/jts")
+	}
+	for _, c := range report.Failed() {
+		t.Errorf("%s (%s) failed: %+v", c.Key(), c.Desc, c.Ops)
+	}
+	if report.Count(Pass)+report.Count(Fail)+report.Count(Skip) != len(report.Cases) {
+		t.Error("every case should have one status")
+	}
+}
+
+func TestParseBoundaryNodeRule(t *testing.T) {
+	for s, expected := range map[string]predicates.BoundaryNodeRule{
+		"":            predicates.Mod2Rule,
+		" EndPoint ":  predicates.EndPointRule,
+		"multivalent": predicates.MultiValentRule,
+		"MonoValent":  predicates.MonoValentRule,
+	} {
+		if rule, err := ParseBoundaryNodeRule(s); err != nil || rule != expected {
+			t.Errorf("ParseBoundaryNodeRule(%q) = %v, %v, expected %v", s, rule, err, expected)
+		}
+	}
+	if _, err := ParseBoundaryNodeRule("Mod3"); err == nil {
+		t.Error("ParseBoundaryNodeRule(Mod3) expected an error")
+	}
+}
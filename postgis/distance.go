@@ -0,0 +1,35 @@
+package postgis
+
+import (
+	"fmt"
+	"math"
+
+	predicates "github.com/tingold/orb-predicates"
+)
+
+// ST_DWithin checks that a and b are within distance of each other, in the
+// units of their SRID. Like PostGIS it is false when either is empty and the
+// distance is inclusive, so geometries that touch are within 0.
+func ST_DWithin(a, b Geometry, distance float64) (bool, error) {
+	if err := check("ST_DWithin", a, b); err != nil {
+		return false, err
+	}
+	if distance < 0 || math.IsNaN(distance) {
+		return false, fmt.Errorf("postgis: ST_DWithin: tolerance cannot be less than zero")
+	}
+	if predicates.IsEmpty(a.Geom) || predicates.IsEmpty(b.Geom) {
+		return false, nil
+	}
+
+	// Envelopes further apart than distance settle it without the geometries
+	ea, eb := predicates.Envelope(a.Geom), predicates.Envelope(b.Geom)
+	if ea.Min[0]-eb.Max[0] > distance || eb.Min[0]-ea.Max[0] > distance ||
+		ea.Min[1]-eb.Max[1] > distance || eb.Min[1]-ea.Max[1] > distance {
+		return false, nil
+	}
+	d, err := predicates.Distance(a.Geom, b.Geom)
+	if err != nil {
+		return false, fmt.Errorf("postgis: ST_DWithin: %w", err)
+	}
+	return d <= distance, nil
+}
@@ -0,0 +1,146 @@
+// Package postgis is a thin layer over the predicates with the names and
+// semantics of the PostGIS functions, for porting SQL to Go.
+//
+// Geometries carry an SRID, as in PostGIS, and every function of two
+// geometries returns an error wrapping predicates.ErrSRIDMismatch when the
+// SRIDs differ, like PostGIS's "Operation on mixed SRID geometries".
+// Geometries the predicates cannot evaluate return an error wrapping
+// predicates.ErrUnsupportedType.
+//
+//	zone, _ := postgis.ST_GeomFromText("POLYGON((0 0, 10 0, 10 10, 0 10, 0 0))", 4326)
+//	site, _ := postgis.ST_GeomFromEWKT("SRID=4326;POINT(1 2)")
+//	ok, err := postgis.ST_Within(site, zone)
+//
+// Empty geometries and GeometryCollections follow PostGIS 3: no predicate
+// holds for an empty geometry except ST_Disjoint, two empty geometries are
+// ST_Equals, and collections are evaluated as the union of their members.
+package postgis
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/paulmach/orb"
+
+	predicates "github.com/tingold/orb-predicates"
+)
+
+// Geometry is an orb geometry tagged with an SRID. SRID 0 is unknown, as
+// in PostGIS.
+type Geometry struct {
+	Geom orb.Geometry
+	SRID int
+}
+
+// ST_SetSRID returns g with the given SRID
+func ST_SetSRID(g Geometry, srid int) Geometry {
+	g.SRID = srid
+	return g
+}
+
+// ST_SRID returns the SRID of g
+func ST_SRID(g Geometry) int {
+	return g.SRID
+}
+
+// ST_GeomFromText parses WKT into a geometry with the given SRID
+func ST_GeomFromText(wkt string, srid int) (Geometry, error) {
+	g, err := predicates.ParseWKT(wkt)
+	if err != nil {
+		return Geometry{}, fmt.Errorf("postgis: ST_GeomFromText: %w", err)
+	}
+	return Geometry{Geom: g, SRID: srid}, nil
+}
+
+// ST_GeomFromEWKT parses WKT with an optional "SRID=n;" prefix
+func ST_GeomFromEWKT(ewkt string) (Geometry, error) {
+	srid := 0
+	if prefix, wkt, ok := strings.Cut(ewkt, ";"); ok {
+		value, found := strings.CutPrefix(strings.TrimSpace(prefix), "SRID=")
+		n, err := strconv.Atoi(value)
+		if !found || err != nil {
+			return Geometry{}, fmt.Errorf("postgis: ST_GeomFromEWKT: bad SRID prefix %q", prefix)
+		}
+		srid, ewkt = n, wkt
+	}
+	g, err := ST_GeomFromText(ewkt, srid)
+	if err != nil {
+		return Geometry{}, fmt.Errorf("postgis: ST_GeomFromEWKT: %w", err)
+	}
+	return g, nil
+}
+
+// ST_GeomFromWKB decodes WKB into a geometry with the given SRID
+func ST_GeomFromWKB(wkb []byte, srid int) (Geometry, error) {
+	g, _, err := predicates.DecodeWKB(wkb)
+	if err != nil {
+		return Geometry{}, fmt.Errorf("postgis: ST_GeomFromWKB: %w", err)
+	}
+	return Geometry{Geom: g, SRID: srid}, nil
+}
+
+// ST_GeomFromEWKB decodes EWKB, as stored by PostGIS, with its SRID
+func ST_GeomFromEWKB(ewkb []byte) (Geometry, error) {
+	g, srid, err := predicates.DecodeWKB(ewkb)
+	if err != nil {
+		return Geometry{}, fmt.Errorf("postgis: ST_GeomFromEWKB: %w", err)
+	}
+	return Geometry{Geom: g, SRID: srid}, nil
+}
+
+// ST_AsText returns the WKT of g
+func ST_AsText(g Geometry) (string, error) {
+	wkt, err := predicates.FormatWKT(g.Geom)
+	if err != nil {
+		return "", fmt.Errorf("postgis: ST_AsText: %w", err)
+	}
+	return wkt, nil
+}
+
+// ST_IsEmpty checks that g has no points
+func ST_IsEmpty(g Geometry) bool {
+	return predicates.IsEmpty(g.Geom)
+}
+
+// ST_Dimension returns the topological dimension of g: 0 for points, 1 for
+// lines and 2 for areas
+func ST_Dimension(g Geometry) int {
+	return predicates.Dimension(g.Geom)
+}
+
+// ST_Envelope returns the bounding box of g, with the SRID of g
+func ST_Envelope(g Geometry) Geometry {
+	if predicates.IsEmpty(g.Geom) {
+		return g
+	}
+	return Geometry{Geom: predicates.Envelope(g.Geom), SRID: g.SRID}
+}
+
+// ST_Boundary returns the boundary of g under the OGC Mod-2 rule
+func ST_Boundary(g Geometry) Geometry {
+	return Geometry{Geom: predicates.Boundary(g.Geom), SRID: g.SRID}
+}
+
+// check returns an error for geometries of different SRIDs or of types the
+// predicates cannot evaluate
+func check(fn string, a, b Geometry) error {
+	if a.SRID != b.SRID {
+		return fmt.Errorf("postgis: %s: %w: operation on mixed SRID geometries (%d != %d)", fn, predicates.ErrSRIDMismatch, a.SRID, b.SRID)
+	}
+	if err := predicates.CheckGeometry(a.Geom); err != nil {
+		return fmt.Errorf("postgis: %s: %w", fn, err)
+	}
+	if err := predicates.CheckGeometry(b.Geom); err != nil {
+		return fmt.Errorf("postgis: %s: %w", fn, err)
+	}
+	return nil
+}
+
+// predicate evaluates pred on a and b after checking them
+func predicate(fn string, pred func(a, b orb.Geometry) bool, a, b Geometry) (bool, error) {
+	if err := check(fn, a, b); err != nil {
+		return false, err
+	}
+	return pred(a.Geom, b.Geom), nil
+}
@@ -0,0 +1,278 @@
+package postgis
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/encoding/ewkb"
+
+	predicates "github.com/tingold/orb-predicates"
+)
+
+func geom(t *testing.T, wkt string) Geometry {
+	t.Helper()
+	g, err := ST_GeomFromEWKT(wkt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+// The examples in the PostGIS reference for each function. The buffered
+// points of the examples are exact circles here.
+func TestDocumentedExamples(t *testing.T) {
+	smallc := Geometry{Geom: predicates.Circle{Center: orb.Point{1, 1}, Radius: 10}}
+	bigc := Geometry{Geom: predicates.Circle{Center: orb.Point{1, 1}, Radius: 20}}
+	bigcRing := ST_Boundary(bigc)
+
+	tests := []struct {
+		name     string
+		fn       func(a, b Geometry) (bool, error)
+		a, b     Geometry
+		expected bool
+	}{
+		// ST_Intersects and ST_Disjoint
+		{"ST_Intersects point off line", ST_Intersects, geom(t, "POINT(0 0)"), geom(t, "LINESTRING ( 2 0, 0 2 )"), false},
+		{"ST_Intersects point on line", ST_Intersects, geom(t, "POINT(0 0)"), geom(t, "LINESTRING ( 0 0, 0 2 )"), true},
+		{"ST_Disjoint point off line", ST_Disjoint, geom(t, "POINT(0 0)"), geom(t, "LINESTRING ( 2 0, 0 2 )"), true},
+		{"ST_Disjoint point on line", ST_Disjoint, geom(t, "POINT(0 0)"), geom(t, "LINESTRING ( 0 0, 0 2 )"), false},
+
+		// ST_Touches
+		{"ST_Touches interior vertex", ST_Touches, geom(t, "LINESTRING(0 0, 1 1, 0 2)"), geom(t, "POINT(1 1)"), false},
+		{"ST_Touches end point", ST_Touches, geom(t, "LINESTRING(0 0, 1 1, 0 2)"), geom(t, "POINT(0 2)"), true},
+
+		// ST_Contains
+		{"ST_Contains smallc smallc", ST_Contains, smallc, smallc, true},
+		{"ST_Contains smallc bigc", ST_Contains, smallc, bigc, false},
+		{"ST_Contains bigc smallc", ST_Contains, bigc, smallc, true},
+		{"ST_Contains bigc exterior ring", ST_Contains, bigc, bigcRing, false},
+
+		// ST_ContainsProperly
+		{"ST_ContainsProperly smallc smallc", ST_ContainsProperly, smallc, smallc, false},
+		{"ST_ContainsProperly smallc bigc", ST_ContainsProperly, smallc, bigc, false},
+		{"ST_ContainsProperly bigc smallc", ST_ContainsProperly, bigc, smallc, true},
+		{"ST_ContainsProperly bigc exterior ring", ST_ContainsProperly, bigc, bigcRing, false},
+
+		// ST_Within
+		{"ST_Within smallc smallc", ST_Within, smallc, smallc, true},
+		{"ST_Within smallc bigc", ST_Within, smallc, bigc, true},
+		{"ST_Within bigc smallc", ST_Within, bigc, smallc, false},
+
+		// ST_Covers and ST_CoveredBy
+		{"ST_Covers smallc smallc", ST_Covers, smallc, smallc, true},
+		{"ST_Covers smallc bigc", ST_Covers, smallc, bigc, false},
+		{"ST_Covers bigc exterior ring", ST_Covers, bigc, bigcRing, true},
+		{"ST_CoveredBy smallc smallc", ST_CoveredBy, smallc, smallc, true},
+		{"ST_CoveredBy smallc bigc", ST_CoveredBy, smallc, bigc, true},
+		{"ST_CoveredBy bigc smallc", ST_CoveredBy, bigc, smallc, false},
+		{"ST_CoveredBy exterior ring bigc", ST_CoveredBy, bigcRing, bigc, true},
+		{"ST_Within exterior ring bigc", ST_Within, bigcRing, bigc, false},
+
+		// ST_Overlaps, with the point on the line
+		{"ST_Overlaps point on line", ST_Overlaps, geom(t, "POINT(1 0.5)"), geom(t, "LINESTRING(1 0, 1 1, 3 5)"), false},
+		{"ST_Crosses point on line", ST_Crosses, geom(t, "POINT(1 0.5)"), geom(t, "LINESTRING(1 0, 1 1, 3 5)"), false},
+		{"ST_Intersects point on line", ST_Intersects, geom(t, "POINT(1 0.5)"), geom(t, "LINESTRING(1 0, 1 1, 3 5)"), true},
+		{"ST_Contains line point", ST_Contains, geom(t, "LINESTRING(1 0, 1 1, 3 5)"), geom(t, "POINT(1 0.5)"), true},
+
+		// ST_Crosses and ST_Overlaps of lines and polygons
+		{"ST_Crosses line through polygon", ST_Crosses, geom(t, "LINESTRING(-1 1, 3 1)"), geom(t, "POLYGON((0 0, 2 0, 2 2, 0 2, 0 0))"), true},
+		{"ST_Overlaps polygons", ST_Overlaps, geom(t, "POLYGON((0 0, 2 0, 2 2, 0 2, 0 0))"), geom(t, "POLYGON((1 1, 3 1, 3 3, 1 3, 1 1))"), true},
+
+		// ST_Equals ignores the vertices and direction of lines
+		{"ST_Equals extra vertex", ST_Equals, geom(t, "LINESTRING(0 0, 10 10)"), geom(t, "LINESTRING(0 0, 5 5, 10 10)"), true},
+		{"ST_Equals reversed", ST_Equals, geom(t, "LINESTRING(0 0, 10 10)"), geom(t, "LINESTRING(10 10, 5 5, 0 0)"), true},
+
+		// Empty geometries
+		{"ST_Intersects empty", ST_Intersects, geom(t, "POINT EMPTY"), geom(t, "POINT(0 0)"), false},
+		{"ST_Disjoint empty", ST_Disjoint, geom(t, "POINT EMPTY"), geom(t, "POINT(0 0)"), true},
+		{"ST_Within empty", ST_Within, geom(t, "POLYGON EMPTY"), bigc, false},
+		{"ST_Contains empty", ST_Contains, bigc, geom(t, "LINESTRING EMPTY"), false},
+		{"ST_Equals empties", ST_Equals, geom(t, "POINT EMPTY"), geom(t, "GEOMETRYCOLLECTION EMPTY"), true},
+		{"ST_Equals empty and point", ST_Equals, geom(t, "POINT EMPTY"), geom(t, "POINT(0 0)"), false},
+
+		// GeometryCollections are the union of their members
+		{"ST_Intersects collection", ST_Intersects, geom(t, "GEOMETRYCOLLECTION(POINT(0 0), LINESTRING(1 1, 2 2))"), geom(t, "POINT(2 2)"), true},
+		{"ST_Contains collection", ST_Contains, geom(t, "GEOMETRYCOLLECTION(POLYGON((0 0, 2 0, 2 2, 0 2, 0 0)), POLYGON((2 0, 4 0, 4 2, 2 2, 2 0)))"), geom(t, "LINESTRING(1 1, 3 1)"), true},
+		{"ST_Within collection", ST_Within, geom(t, "GEOMETRYCOLLECTION(POINT(1 1), LINESTRING(0 0, 0 1))"), geom(t, "POLYGON((-1 -1, 3 -1, 3 3, -1 3, -1 -1))"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.fn(tt.a, tt.b)
+			if err != nil || got != tt.expected {
+				t.Errorf("%v, %v, expected %v", got, err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRelate(t *testing.T) {
+	circle := Geometry{Geom: predicates.Circle{Center: orb.Point{1, 2}, Radius: 2}}
+	tests := []struct {
+		a, b     Geometry
+		rule     []int
+		expected string
+	}{
+		{geom(t, "POINT(1 2)"), circle, nil, "0FFFFF212"},
+		{geom(t, "LINESTRING(1 2, 3 4)"), geom(t, "LINESTRING(5 6, 7 8)"), nil, "FF1FF0102"},
+		{geom(t, "POINT EMPTY"), geom(t, "POINT EMPTY"), nil, "FFFFFFFF2"},
+
+		// The start of a closed line is on its boundary only under the Endpoint rule
+		{geom(t, "POINT(0 0)"), geom(t, "LINESTRING(0 0, 1 0, 1 1, 0 0)"), []int{1}, "0FFFFF1F2"},
+		{geom(t, "POINT(0 0)"), geom(t, "LINESTRING(0 0, 1 0, 1 1, 0 0)"), []int{2}, "F0FFFF1F2"},
+	}
+	for _, tt := range tests {
+		if got, err := ST_Relate(tt.a, tt.b, tt.rule...); err != nil || got != tt.expected {
+			t.Errorf("ST_Relate(%v, %v, %v) = %q, %v, expected %q", tt.a.Geom, tt.b.Geom, tt.rule, got, err, tt.expected)
+		}
+	}
+
+	if ok, err := ST_RelatePattern(geom(t, "POINT(1 2)"), circle, "*FF*FF212"); err != nil || !ok {
+		t.Errorf("ST_Relate with a pattern = %v, %v, expected true", ok, err)
+	}
+	if !ST_RelateMatch("101202FFF", "TTTTTTFFF") {
+		t.Error("ST_RelateMatch('101202FFF', 'TTTTTTFFF') = false, expected true")
+	}
+	if _, err := ST_RelatePattern(circle, circle, "TTTT"); err == nil {
+		t.Error("ST_Relate with a short pattern expected an error")
+	}
+	if _, err := ST_Relate(circle, circle, 5); err == nil {
+		t.Error("ST_Relate with boundary node rule 5 expected an error")
+	}
+}
+
+func TestDWithin(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		distance float64
+		expected bool
+	}{
+		{"POINT(0 0)", "POINT(3 4)", 5, true},
+		{"POINT(0 0)", "POINT(3 4)", 4.99, false},
+		{"POINT(0 0)", "LINESTRING(-5 1, 5 1)", 1, true},
+		{"POINT(0 0)", "LINESTRING(-5 1, 5 1)", 0.9, false},
+		{"POLYGON((0 0, 2 0, 2 2, 0 2, 0 0))", "POLYGON((5 0, 7 0, 7 2, 5 2, 5 0))", 3, true},
+		{"POLYGON((0 0, 2 0, 2 2, 0 2, 0 0))", "POLYGON((5 0, 7 0, 7 2, 5 2, 5 0))", 2.5, false},
+		{"POLYGON((0 0, 10 0, 10 10, 0 10, 0 0))", "POINT(5 5)", 0, true},
+		{"LINESTRING(0 0, 10 0)", "LINESTRING(5 0, 5 3)", 0, true},
+		{"MULTIPOINT((0 10), (20 20))", "GEOMETRYCOLLECTION(POINT(0 0), LINESTRING(10 10, 10 20))", 10, true},
+		{"POINT EMPTY", "POINT(0 0)", 100, false},
+
+		// Arcs are measured to the point where they bulge
+		{"CIRCULARSTRING(0 0, 1 1, 2 0)", "POINT(1 3)", 5, true},
+		{"CIRCULARSTRING(0 0, 1 1, 2 0)", "POINT(1 3)", 2, true},
+		{"CIRCULARSTRING(0 0, 1 1, 2 0)", "POINT(1 3)", 1.9, false},
+		{"CIRCULARSTRING(0 0, 1 1, 2 0)", "LINESTRING(-5 3, 5 3)", 2, true},
+		{"CIRCULARSTRING(0 0, 1 1, 2 0)", "LINESTRING(-5 3, 5 3)", 1.9, false},
+		{"CIRCULARSTRING(0 0, 1 1, 2 0)", "CIRCULARSTRING(0 4, 1 3, 2 4)", 2, true},
+		{"CIRCULARSTRING(0 0, 1 1, 2 0)", "CIRCULARSTRING(0 4, 1 3, 2 4)", 1.9, false},
+		{"CIRCULARSTRING(0 0, 1 -1, 2 0)", "POINT(1 3)", 3.1, false},
+		{"CIRCULARSTRING(0 0, 2 0, 0 0)", "POINT(1 3)", 2, true},
+	}
+	for _, tt := range tests {
+		if got, err := ST_DWithin(geom(t, tt.a), geom(t, tt.b), tt.distance); err != nil || got != tt.expected {
+			t.Errorf("ST_DWithin(%s, %s, %v) = %v, %v, expected %v", tt.a, tt.b, tt.distance, got, err, tt.expected)
+		}
+	}
+
+	circle := Geometry{Geom: predicates.Circle{Center: orb.Point{0, 0}, Radius: 1}}
+	if ok, err := ST_DWithin(circle, geom(t, "POINT(3 0)"), 2); err != nil || !ok {
+		t.Errorf("ST_DWithin(circle, POINT(3 0), 2) = %v, %v, expected true", ok, err)
+	}
+	if ok, err := ST_DWithin(circle, geom(t, "POINT(3 0)"), 1.9); err != nil || ok {
+		t.Errorf("ST_DWithin(circle, POINT(3 0), 1.9) = %v, %v, expected false", ok, err)
+	}
+	inCollection := Geometry{Geom: orb.Collection{predicates.Circle{Center: orb.Point{0, 0}, Radius: 1}, orb.Point{10, 10}}}
+	if ok, err := ST_DWithin(inCollection, geom(t, "POINT(3 0)"), 2); err != nil || !ok {
+		t.Errorf("ST_DWithin(collection with circle, POINT(3 0), 2) = %v, %v, expected true", ok, err)
+	}
+	if ok, err := ST_DWithin(inCollection, geom(t, "POINT(3 0)"), 1.9); err != nil || ok {
+		t.Errorf("ST_DWithin(collection with circle, POINT(3 0), 1.9) = %v, %v, expected false", ok, err)
+	}
+
+	// Custom areas are measured to their boundary, and registered types as
+	// the geometry they stand for
+	predicates.RegisterType(func(p testParcel) orb.Geometry { return p.Geometry })
+	for _, g := range []orb.Geometry{
+		testSquare{orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{2, 2}}.ToPolygon()},
+		testParcel{Geometry: orb.LineString{{0, 2}, {2, 2}}},
+	} {
+		if ok, err := ST_DWithin(Geometry{Geom: g}, geom(t, "POINT(1 5)"), 3); err != nil || !ok {
+			t.Errorf("ST_DWithin(%T, POINT(1 5), 3) = %v, %v, expected true", g, ok, err)
+		}
+		if ok, err := ST_DWithin(Geometry{Geom: g}, geom(t, "POINT(1 5)"), 2.9); err != nil || ok {
+			t.Errorf("ST_DWithin(%T, POINT(1 5), 2.9) = %v, %v, expected false", g, ok, err)
+		}
+	}
+
+	if _, err := ST_DWithin(circle, circle, -1); err == nil {
+		t.Error("ST_DWithin with a negative distance expected an error")
+	}
+}
+
+func TestSRID(t *testing.T) {
+	a := geom(t, "SRID=4326;POINT(1 1)")
+	b := geom(t, "SRID=3857;POLYGON((0 0, 2 0, 2 2, 0 2, 0 0))")
+	if ST_SRID(a) != 4326 || ST_SRID(b) != 3857 {
+		t.Fatalf("SRIDs = %d, %d, expected 4326 and 3857", ST_SRID(a), ST_SRID(b))
+	}
+
+	if _, err := ST_Within(a, b); !errors.Is(err, predicates.ErrSRIDMismatch) {
+		t.Errorf("ST_Within on mixed SRIDs error = %v, expected ErrSRIDMismatch", err)
+	}
+	if _, err := ST_Relate(a, ST_SetSRID(b, 0)); !errors.Is(err, predicates.ErrSRIDMismatch) {
+		t.Errorf("ST_Relate with an unknown SRID error = %v, expected ErrSRIDMismatch", err)
+	}
+	if ok, err := ST_Within(a, ST_SetSRID(b, 4326)); err != nil || !ok {
+		t.Errorf("ST_Within after ST_SetSRID = %v, %v, expected true", ok, err)
+	}
+
+	// EWKB carries the SRID
+	g, err := ST_GeomFromEWKB(ewkb.MustMarshal(orb.Point{1, 1}, 4326))
+	if err != nil || g.SRID != 4326 {
+		t.Fatalf("ST_GeomFromEWKB() = %v, %v, expected SRID 4326", g, err)
+	}
+	if ok, err := ST_Equals(g, a); err != nil || !ok {
+		t.Errorf("ST_Equals(EWKB point, EWKT point) = %v, %v, expected true", ok, err)
+	}
+	if text, err := ST_AsText(g); err != nil || text != "POINT (1 1)" {
+		t.Errorf("ST_AsText() = %q, %v", text, err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, ewkt := range []string{"SRID=abc;POINT(1 1)", "SRID=4326;POINT(1", "4326;POINT(1 1)"} {
+		if _, err := ST_GeomFromEWKT(ewkt); err == nil {
+			t.Errorf("ST_GeomFromEWKT(%q) expected an error", ewkt)
+		}
+	}
+	if _, err := ST_GeomFromWKB([]byte{1, 2, 3}, 0); !errors.Is(err, predicates.ErrInvalidWKB) {
+		t.Errorf("ST_GeomFromWKB of bad data error = %v, expected ErrInvalidWKB", err)
+	}
+
+	type unknown struct{ orb.Geometry }
+	bad := Geometry{Geom: unknown{orb.Point{1, 1}}}
+	if _, err := ST_Intersects(bad, geom(t, "POINT(1 1)")); !errors.Is(err, predicates.ErrUnsupportedType) {
+		t.Errorf("ST_Intersects on an unsupported type error = %v, expected ErrUnsupportedType", err)
+	}
+}
+
+// testSquare is a custom area
+type testSquare struct{ orb.Polygon }
+
+func (s testSquare) Boundary() orb.Geometry { return orb.LineString(s.Polygon[0]) }
+
+func (s testSquare) Locate(p orb.Point) predicates.Location {
+	b := s.Bound()
+	switch {
+	case !b.Contains(p):
+		return predicates.Exterior
+	case p[0] == b.Min[0] || p[0] == b.Max[0] || p[1] == b.Min[1] || p[1] == b.Max[1]:
+		return predicates.OnBoundary
+	}
+	return predicates.Interior
+}
+
+// testParcel is a registered type
+type testParcel struct{ orb.Geometry }
@@ -0,0 +1,101 @@
+package postgis
+
+import (
+	"fmt"
+
+	predicates "github.com/tingold/orb-predicates"
+)
+
+// ST_Intersects checks that a and b share a point
+func ST_Intersects(a, b Geometry) (bool, error) {
+	return predicate("ST_Intersects", predicates.Intersects, a, b)
+}
+
+// ST_Disjoint checks that a and b share no point
+func ST_Disjoint(a, b Geometry) (bool, error) {
+	return predicate("ST_Disjoint", predicates.Disjoint, a, b)
+}
+
+// ST_Contains checks that no point of b is outside a and their interiors meet
+func ST_Contains(a, b Geometry) (bool, error) {
+	return predicate("ST_Contains", predicates.Contains, a, b)
+}
+
+// ST_Within checks that no point of a is outside b and their interiors meet
+func ST_Within(a, b Geometry) (bool, error) {
+	return predicate("ST_Within", predicates.Within, a, b)
+}
+
+// ST_Covers checks that no point of b is outside a
+func ST_Covers(a, b Geometry) (bool, error) {
+	return predicate("ST_Covers", predicates.Covers, a, b)
+}
+
+// ST_CoveredBy checks that no point of a is outside b
+func ST_CoveredBy(a, b Geometry) (bool, error) {
+	return predicate("ST_CoveredBy", predicates.CoveredBy, a, b)
+}
+
+// ST_ContainsProperly checks that b lies in the interior of a
+func ST_ContainsProperly(a, b Geometry) (bool, error) {
+	return predicate("ST_ContainsProperly", predicates.ContainsProperly, a, b)
+}
+
+// ST_Crosses checks that a and b share some interior points, of a lower
+// dimension than the larger of theirs
+func ST_Crosses(a, b Geometry) (bool, error) {
+	return predicate("ST_Crosses", predicates.Crosses, a, b)
+}
+
+// ST_Overlaps checks that a and b have the same dimension and share some but
+// not all of their points
+func ST_Overlaps(a, b Geometry) (bool, error) {
+	return predicate("ST_Overlaps", predicates.Overlaps, a, b)
+}
+
+// ST_Touches checks that a and b meet only at their boundaries
+func ST_Touches(a, b Geometry) (bool, error) {
+	return predicate("ST_Touches", predicates.Touches, a, b)
+}
+
+// ST_Equals checks that a and b have the same points, whatever their
+// vertices. Two empty geometries are equal.
+func ST_Equals(a, b Geometry) (bool, error) {
+	return predicate("ST_Equals", predicates.Equals, a, b)
+}
+
+// ST_Relate returns the DE-9IM matrix of a and b. An optional
+// boundaryNodeRule takes the PostGIS values: 1 for the OGC Mod-2 rule, the
+// default, 2 for Endpoint, 3 for MultivalentEndpoint and 4 for
+// MonovalentEndpoint.
+func ST_Relate(a, b Geometry, boundaryNodeRule ...int) (string, error) {
+	if err := check("ST_Relate", a, b); err != nil {
+		return "", err
+	}
+	rule := predicates.Mod2Rule
+	if len(boundaryNodeRule) > 0 {
+		n := boundaryNodeRule[0]
+		if n < 1 || n > 4 {
+			return "", fmt.Errorf("postgis: ST_Relate: invalid boundary node rule %d", n)
+		}
+		rule = predicates.BoundaryNodeRule(n - 1)
+	}
+	return predicates.WithBoundaryNodeRule(rule).Relate(a.Geom, b.Geom), nil
+}
+
+// ST_RelatePattern is the three-argument ST_Relate: it checks that the
+// DE-9IM matrix of a and b matches pattern, such as "T*F**F***"
+func ST_RelatePattern(a, b Geometry, pattern string) (bool, error) {
+	if err := check("ST_Relate", a, b); err != nil {
+		return false, err
+	}
+	if !predicates.ValidPattern(pattern) {
+		return false, fmt.Errorf("postgis: ST_Relate: invalid pattern %q", pattern)
+	}
+	return predicates.RelateMatch(predicates.Relate(a.Geom, b.Geom), pattern), nil
+}
+
+// ST_RelateMatch checks that a DE-9IM matrix matches a pattern
+func ST_RelateMatch(matrix, pattern string) bool {
+	return predicates.RelateMatch(matrix, pattern)
+}
@@ -3,6 +3,7 @@
 // This package implements the standard OGC/DE-9IM spatial predicates:
 //   - Within: geometry A is completely inside geometry B
 //   - Contains: geometry A completely contains geometry B
+//   - ContainsProperly: geometry B lies in the interior of A without touching its boundary
 //   - Covers: no point in B is outside of A
 //   - CoveredBy: no point in A is outside of B
 //   - Crosses: geometries have some but not all interior points in common
@@ -10,6 +11,7 @@
 //   - Intersects: geometries have at least one point in common
 //   - Overlaps: geometries share some but not all points, same dimension
 //   - Touches: geometries touch at boundaries only
+//   - Equals: geometries have the same points
 //
 // Supported geometry types:
 //   - Point
@@ -21,8 +23,33 @@
 //   - MultiPolygon
 //   - Collection
 //   - Bound
+//   - Circle and CircularString, defined in this package
 //
-// All predicates handle all valid combinations of geometry types.
+// All predicates handle all valid combinations of geometry types. Circles
+// and circular arcs are evaluated exactly, without approximating them by
+// polygons.
+//
+// Empty geometries follow OGC semantics: an empty geometry has no points, so
+// every predicate returns false when either argument is empty, except
+// Disjoint, which returns true. A Point with NaN coordinates is empty, and
+// empty components of a Multi* or Collection are ignored, so
+// MULTIPOINT(EMPTY, (1 1)) behaves like POINT(1 1).
+//
+// Areas that have collapsed are evaluated with their true dimension: a Bound
+// of zero width or height is a line, a point-sized Bound is a point, and a
+// Ring or Polygon whose shell encloses no area is the lines it traces.
+//
+// The end points of lines are on the boundary under the OGC Mod-2 rule. Use
+// WithBoundaryNodeRule to evaluate the predicates with the EndPoint,
+// MultiValent or MonoValent rule instead.
+//
+// Other geometry types take part through RegisterType, which maps a domain
+// type to the orb geometry it wraps, or by implementing Relater, which
+// supplies the boundary and point location of a custom area. Any other type
+// is an error: Evaluator.Evaluate, Check and CheckGeometry report it as
+// ErrUnsupportedType. The boolean predicates have no way to report it and
+// read it as empty, so use one of those for geometries whose type is not
+// known in advance.
 //
 // Example usage:
 //
@@ -48,11 +75,26 @@ package predicates
 
 // The main predicate functions are implemented in separate files:
 // - within.go: Within, Contains
+// - containsproperly.go: ContainsProperly
 // - covers.go: Covers, CoveredBy
 // - intersects.go: Intersects
 // - disjoint.go: Disjoint
 // - crosses.go: Crosses
 // - overlaps.go: Overlaps
 // - touches.go: Touches
+// - equals.go: Equals
+// - wkt.go: ParseWKT and FormatWKT
+// - boundary.go: Dimension, IsEmpty, Envelope, Boundary
+// - interiorpoint.go: InteriorPoint
+// - collapse.go: collapsed areas as lines and points
+// - curve.go: Circle, CircularString and the arc geometry of the relate engine
+// - boundaryrule.go: BoundaryNodeRule and Evaluator
+// - typed.go: allocation-free typed entry points such as PointInPolygon
+// - registry.go: Relater, RegisterType and CheckGeometry for custom types
+// - tilecover.go: TileCover for maptile tiles
+// - classify.go: Classify and the RCC8 / Egenhofer relations
+// - direction.go: DirectionMatrix, NorthOf and the other cardinal direction relations
+// - relate.go: Relate, RelateMatch and the DE-9IM engine behind every predicate
+// - graph.go: the topology graph the relate engine builds
 //
 // Helper functions are in helpers.go
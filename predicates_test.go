@@ -1,9 +1,16 @@
 package predicates
 
 import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/rand/v2"
 	"testing"
 
 	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/encoding/ewkb"
+	"github.com/paulmach/orb/encoding/wkb"
+	"github.com/paulmach/orb/maptile"
 )
 
 // Test geometries
@@ -59,6 +66,38 @@ var (
 		},
 	}
 
+	// Non-convex shapes whose vertex centroid lies outside the area
+	cShape = orb.Polygon{
+		orb.Ring{
+			orb.Point{0, 0},
+			orb.Point{10, 0},
+			orb.Point{10, 2},
+			orb.Point{2, 2},
+			orb.Point{2, 8},
+			orb.Point{10, 8},
+			orb.Point{10, 10},
+			orb.Point{0, 10},
+			orb.Point{0, 0},
+		},
+	}
+
+	donut = orb.Polygon{
+		orb.Ring{
+			orb.Point{0, 0},
+			orb.Point{10, 0},
+			orb.Point{10, 10},
+			orb.Point{0, 10},
+			orb.Point{0, 0},
+		},
+		orb.Ring{
+			orb.Point{3, 3},
+			orb.Point{3, 7},
+			orb.Point{7, 7},
+			orb.Point{7, 3},
+			orb.Point{3, 3},
+		},
+	}
+
 	// Points
 	pointInside    = orb.Point{5, 5}
 	pointOutside   = orb.Point{15, 15}
@@ -250,6 +289,57 @@ func TestContains(t *testing.T) {
 	}
 }
 
+// ==================== ContainsProperly Tests ====================
+
+func TestContainsProperly(t *testing.T) {
+	squareWithHole := orb.Polygon{
+		unitSquare[0],
+		orb.Ring{orb.Point{4, 4}, orb.Point{4, 6}, orb.Point{6, 6}, orb.Point{6, 4}, orb.Point{4, 4}},
+	}
+
+	tests := []struct {
+		name     string
+		a, b     orb.Geometry
+		expected bool
+	}{
+		{"polygon contains point inside", unitSquare, pointInside, true},
+		{"polygon not contains point on edge", unitSquare, pointOnEdge, false},
+		{"polygon contains smaller polygon", unitSquare, smallSquare, true},
+		{"polygon not contains itself", unitSquare, unitSquare, false},
+		{"polygon not contains polygon touching edge", unitSquare,
+			orb.Polygon{orb.Ring{orb.Point{0, 2}, orb.Point{4, 2}, orb.Point{4, 4}, orb.Point{0, 4}, orb.Point{0, 2}}}, false},
+		{"polygon contains line inside", unitSquare, lineInside, true},
+		{"polygon not contains line on edge", unitSquare, lineOnEdge, false},
+		{"polygon not contains line ending on edge", unitSquare, orb.LineString{orb.Point{5, 5}, orb.Point{5, 10}}, false},
+		{"holed polygon not contains polygon around hole", squareWithHole,
+			orb.Polygon{orb.Ring{orb.Point{3, 3}, orb.Point{7, 3}, orb.Point{7, 7}, orb.Point{3, 7}, orb.Point{3, 3}}}, false},
+		{"holed polygon not contains polygon touching hole", squareWithHole, smallSquare, false},
+		{"holed polygon contains point beside hole", squareWithHole, orb.Point{2, 2}, true},
+		{"ring contains smaller ring", unitSquare[0], ringInside, true},
+		{"bound contains point", testBound, pointInside, true},
+		{"bound not contains point on edge", testBound, pointOnEdge, false},
+		{"multipolygon contains point", multiPolygon, orb.Point{2, 2}, true},
+		{"multipolygon not contains line spanning parts", multiPolygon, orb.LineString{orb.Point{2, 2}, orb.Point{12, 12}}, false},
+		{"line contains interior point", lineOnEdge, orb.Point{5, 0}, true},
+		{"line not contains endpoint", lineOnEdge, orb.Point{0, 0}, false},
+		{"line contains inner segment", lineOnEdge, orb.LineString{orb.Point{2, 0}, orb.Point{8, 0}}, true},
+		{"line not contains segment at endpoint", lineOnEdge, orb.LineString{orb.Point{0, 0}, orb.Point{8, 0}}, false},
+		{"point contains equal point", pointInside, pointInside, true},
+		{"multipoint contains member", multiPointAllInside, orb.Point{5, 5}, true},
+		{"polygon contains collection inside", unitSquare, orb.Collection{pointInside, lineInside}, true},
+		{"polygon not contains collection touching", unitSquare, orb.Collection{pointInside, pointOnEdge}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ContainsProperly(tt.a, tt.b)
+			if result != tt.expected {
+				t.Errorf("ContainsProperly(%v, %v) = %v, expected %v", tt.a, tt.b, result, tt.expected)
+			}
+		})
+	}
+}
+
 // ==================== Intersects Tests ====================
 
 func TestIntersects(t *testing.T) {
@@ -651,6 +741,17 @@ func TestCollectionPredicates(t *testing.T) {
 		{"polygon contains collection", Contains, unitSquare, collection, true},
 		{"collection intersects polygon", Intersects, collection, unitSquare, true},
 		{"collection disjoint from distant polygon", Disjoint, collection, disjointSquare, true},
+
+		// A collection is the union of its parts
+		{"adjacent polygons contain line spanning both", Contains, orb.Collection{unitSquare, touchingSquare}, orb.LineString{{5, 5}, {15, 5}}, true},
+		{"line on shared edge within adjacent polygons", Within, orb.LineString{{10, 2}, {10, 8}}, orb.Collection{unitSquare, touchingSquare}, true},
+		{"point on shared edge within adjacent polygons", Within, orb.Point{10, 5}, orb.Collection{unitSquare, touchingSquare}, true},
+		{"adjacent polygons cover bound of both", Covers, orb.Collection{unitSquare, touchingSquare}, orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{20, 10}}, true},
+		{"line spanning polygon and line parts within collection", Within, orb.LineString{{5, 5}, {15, 5}}, orb.Collection{unitSquare, orb.LineString{{10, 5}, {20, 5}}}, true},
+		{"line leaving the collection crosses it", Crosses, orb.LineString{{5, 5}, {25, 5}}, orb.Collection{unitSquare, touchingSquare}, true},
+		{"collection of points and lines touches polygon", Touches, orb.Collection{lineTouching, pointOutside}, unitSquare, true},
+		{"collection overlaps polygon", Overlaps, orb.Collection{smallSquare, overlappingSquare}, unitSquare, true},
+		{"collection properly contains point", ContainsProperly, orb.Collection{unitSquare, touchingSquare}, orb.Point{10, 5}, true},
 	}
 
 	for _, tt := range tests {
@@ -761,3 +862,1751 @@ func TestMultiLineStringPredicates(t *testing.T) {
 		})
 	}
 }
+
+// ==================== Boundary Tests ====================
+
+func TestDimension(t *testing.T) {
+	tests := []struct {
+		name     string
+		g        orb.Geometry
+		expected int
+	}{
+		{"point", pointInside, 0},
+		{"multipoint", multiPointAllInside, 0},
+		{"linestring", lineInside, 1},
+		{"multilinestring", multiLineString, 1},
+		{"ring", ringInside, 2},
+		{"polygon", unitSquare, 2},
+		{"multipolygon", multiPolygon, 2},
+		{"bound", testBound, 2},
+		{"mixed collection", testCollection, 1},
+		{"empty collection", orb.Collection{}, -1},
+		{"empty point", orb.Point{math.NaN(), math.NaN()}, 0},
+		{"collection with empty polygon", orb.Collection{orb.Polygon{}, lineInside}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := Dimension(tt.g); result != tt.expected {
+				t.Errorf("Dimension(%v) = %d, expected %d", tt.g, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsEmpty(t *testing.T) {
+	tests := []struct {
+		name     string
+		g        orb.Geometry
+		expected bool
+	}{
+		{"point", pointInside, false},
+		{"empty multipoint", orb.MultiPoint{}, true},
+		{"empty linestring", orb.LineString{}, true},
+		{"empty polygon", orb.Polygon{}, true},
+		{"polygon with empty shell", orb.Polygon{orb.Ring{}}, true},
+		{"empty collection", orb.Collection{}, true},
+		{"collection", testCollection, false},
+		{"nan point", orb.Point{math.NaN(), math.NaN()}, true},
+		{"multipoint of empty points", orb.MultiPoint{{math.NaN(), math.NaN()}}, true},
+		{"multilinestring of empty lines", orb.MultiLineString{{}, {}}, true},
+		{"multipolygon of empty polygons", orb.MultiPolygon{{}, {orb.Ring{}}}, true},
+		{"nested empty collection", orb.Collection{orb.Collection{}, orb.Point{math.NaN(), math.NaN()}}, true},
+		{"multipoint with one empty point", orb.MultiPoint{{math.NaN(), math.NaN()}, {1, 1}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := IsEmpty(tt.g); result != tt.expected {
+				t.Errorf("IsEmpty(%v) = %v, expected %v", tt.g, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEnvelope(t *testing.T) {
+	c := orb.Collection{orb.LineString{}, orb.Point{5, 5}, orb.Point{7, 8}}
+	expected := orb.Bound{Min: orb.Point{5, 5}, Max: orb.Point{7, 8}}
+	if result := Envelope(c); result != expected {
+		t.Errorf("Envelope(%v) = %v, expected %v", c, result, expected)
+	}
+	if result := Envelope(unitSquare); result != unitSquare.Bound() {
+		t.Errorf("Envelope(%v) = %v, expected %v", unitSquare, result, unitSquare.Bound())
+	}
+
+	// Nested empty components do not stretch the envelope to the origin
+	nested := orb.Collection{orb.MultiPoint{{math.NaN(), math.NaN()}, {5, 5}}, orb.Collection{orb.Polygon{}}, orb.Point{7, 8}}
+	if result := Envelope(nested); result != expected {
+		t.Errorf("Envelope(%v) = %v, expected %v", nested, result, expected)
+	}
+}
+
+func TestEmptyPredicates(t *testing.T) {
+	emptyPoint := orb.Point{math.NaN(), math.NaN()}
+	empties := []struct {
+		name string
+		g    orb.Geometry
+	}{
+		{"empty point", emptyPoint},
+		{"empty multipoint", orb.MultiPoint{}},
+		{"empty linestring", orb.LineString{}},
+		{"empty polygon", orb.Polygon{}},
+		{"empty multipolygon", orb.MultiPolygon{{}}},
+		{"empty collection", orb.Collection{}},
+		{"nested empty collection", orb.Collection{orb.Collection{emptyPoint}, orb.LineString{}}},
+	}
+	others := []orb.Geometry{pointInside, lineInside, unitSquare, testBound, testCollection, emptyPoint}
+
+	for _, e := range empties {
+		for _, o := range others {
+			for _, pair := range [][2]orb.Geometry{{e.g, o}, {o, e.g}} {
+				a, b := pair[0], pair[1]
+				for name, pred := range map[string]func(a, b orb.Geometry) bool{
+					"Intersects": Intersects, "Within": Within, "Contains": Contains,
+					"ContainsProperly": ContainsProperly, "Covers": Covers, "CoveredBy": CoveredBy,
+					"Touches": Touches, "Crosses": Crosses, "Overlaps": Overlaps,
+				} {
+					if pred(a, b) {
+						t.Errorf("%s: %s(%v, %v) = true, expected false", e.name, name, a, b)
+					}
+				}
+				if !Disjoint(a, b) {
+					t.Errorf("%s: Disjoint(%v, %v) = false, expected true", e.name, a, b)
+				}
+			}
+		}
+	}
+
+	// Empty components are ignored, so the rest of the geometry decides
+	withEmpty := []struct {
+		name     string
+		a, b     orb.Geometry
+		pred     func(a, b orb.Geometry) bool
+		expected bool
+	}{
+		{"multipoint with empty point within", orb.MultiPoint{emptyPoint, {5, 5}}, unitSquare, Within, true},
+		{"multipolygon with empty polygon within", orb.MultiPolygon{{}, smallSquare}, unitSquare, Within, true},
+		{"collection with empty point overlaps", orb.Collection{emptyPoint, overlappingSquare}, unitSquare, Overlaps, true},
+		{"collection with empty line touches", orb.Collection{orb.LineString{}, touchingSquare}, unitSquare, Touches, true},
+		{"multilinestring with empty line crosses", orb.MultiLineString{{}, lineCrossing}, unitSquare, Crosses, true},
+		{"polygon contains multipoint with empty point", unitSquare, orb.MultiPoint{{5, 5}, emptyPoint}, Contains, true},
+	}
+	for _, tt := range withEmpty {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.pred(tt.a, tt.b); result != tt.expected {
+				t.Errorf("got %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCollapsedAreas(t *testing.T) {
+	zeroWidth := orb.Bound{Min: orb.Point{5, -5}, Max: orb.Point{5, 15}}
+	zeroWidthInside := orb.Bound{Min: orb.Point{5, 0}, Max: orb.Point{5, 10}}
+	zeroHeight := orb.Bound{Min: orb.Point{-5, 5}, Max: orb.Point{15, 5}}
+	zeroHeightOnEdge := orb.Bound{Min: orb.Point{0, 10}, Max: orb.Point{10, 10}}
+	pointBound := orb.Bound{Min: orb.Point{5, 5}, Max: orb.Point{5, 5}}
+	cornerBound := orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{0, 0}}
+	// Shells that only run back over themselves
+	flatPolygon := orb.Polygon{{{-5, 5}, {15, 5}, {5, 5}, {-5, 5}}}
+	spikePolygon := orb.Polygon{{{0, 5}, {10, 5}, {10, 8}, {10, 5}, {0, 5}}}
+	pointPolygon := orb.Polygon{{{5, 5}, {5, 5}, {5, 5}, {5, 5}}}
+
+	dimensions := []struct {
+		name     string
+		g        orb.Geometry
+		expected int
+	}{
+		{"zero-width bound", zeroWidth, 1},
+		{"zero-height bound", zeroHeight, 1},
+		{"point bound", pointBound, 0},
+		{"flat polygon", flatPolygon, 1},
+		{"flat ring", flatPolygon[0], 1},
+		{"polygon with a spike", spikePolygon, 1},
+		{"point polygon", pointPolygon, 0},
+		{"multipolygon of flat polygons", orb.MultiPolygon{flatPolygon, pointPolygon}, 1},
+		{"multipolygon with one flat polygon", orb.MultiPolygon{flatPolygon, smallSquare}, 2},
+		{"collection with a point bound", orb.Collection{pointBound}, 0},
+	}
+	for _, tt := range dimensions {
+		t.Run("dimension "+tt.name, func(t *testing.T) {
+			if result := Dimension(tt.g); result != tt.expected {
+				t.Errorf("Dimension(%v) = %d, expected %d", tt.g, result, tt.expected)
+			}
+		})
+	}
+
+	tests := []struct {
+		name     string
+		a, b     orb.Geometry
+		pred     func(a, b orb.Geometry) bool
+		expected bool
+	}{
+		// Zero-width bounds are vertical lines
+		{"zero-width bound crosses polygon", zeroWidth, unitSquare, Crosses, true},
+		{"zero-width bound does not overlap polygon", zeroWidth, unitSquare, Overlaps, false},
+		{"zero-width bound does not touch polygon", zeroWidth, unitSquare, Touches, false},
+		{"zero-width bound within polygon", zeroWidthInside, unitSquare, Within, true},
+		{"zero-width bound inside does not cross polygon", zeroWidthInside, unitSquare, Crosses, false},
+		{"polygon contains zero-width bound properly", unitSquare, zeroWidthInside, ContainsProperly, false},
+		{"zero-width bound crosses zero-height bound", zeroWidth, zeroHeight, Crosses, true},
+		{"zero-width bound does not touch zero-height bound", zeroWidth, zeroHeight, Touches, false},
+		{"zero-width bounds overlap", zeroWidth, zeroWidthInside, Overlaps, false},
+		{"zero-width bound contains zero-width bound", zeroWidth, zeroWidthInside, Contains, true},
+		{"zero-width bound overlaps line", zeroWidthInside, orb.LineString{{5, 5}, {5, 20}}, Overlaps, true},
+		{"zero-width bound touches bound", zeroWidthInside, orb.Bound{Min: orb.Point{5, 10}, Max: orb.Point{8, 12}}, Touches, true},
+
+		// Zero-height bounds are horizontal lines
+		{"zero-height bound crosses polygon", zeroHeight, unitSquare, Crosses, true},
+		{"zero-height bound on edge touches polygon", zeroHeightOnEdge, unitSquare, Touches, true},
+		{"zero-height bound on edge is not within polygon", zeroHeightOnEdge, unitSquare, Within, false},
+		{"polygon covers zero-height bound on edge", unitSquare, zeroHeightOnEdge, Covers, true},
+		{"bound touches zero-height bound on edge", testBound, orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{10, 0}}, Touches, true},
+
+		// Point-sized bounds are points
+		{"point bound within polygon", pointBound, unitSquare, Within, true},
+		{"point bound does not touch polygon", pointBound, unitSquare, Touches, false},
+		{"corner bound touches polygon", cornerBound, unitSquare, Touches, true},
+		{"corner bound not within polygon", cornerBound, unitSquare, Within, false},
+		{"point bound equals point", pointBound, pointInside, Within, true},
+		{"point bounds do not overlap", pointBound, pointBound, Overlaps, false},
+		{"point bounds do not touch", pointBound, cornerBound, Touches, false},
+		{"line touches corner bound", lineOnEdge, cornerBound, Touches, true},
+
+		// Zero-area polygons are the lines they trace
+		{"flat polygon crosses polygon", flatPolygon, unitSquare, Crosses, true},
+		{"flat polygon does not overlap polygon", flatPolygon, unitSquare, Overlaps, false},
+		{"flat polygon overlaps line", flatPolygon, orb.LineString{{10, 5}, {20, 5}}, Overlaps, true},
+		{"flat polygon crosses line", flatPolygon, orb.LineString{{2, 0}, {2, 10}}, Crosses, true},
+		{"spike polygon touches line at its end", spikePolygon, orb.LineString{{10, 8}, {10, 12}}, Touches, true},
+		{"spike polygon within polygon", spikePolygon, unitSquare, Within, true},
+		{"point polygon within polygon", pointPolygon, unitSquare, Within, true},
+		{"collapsed polygon in collection crosses polygon", orb.Collection{flatPolygon}, unitSquare, Crosses, true},
+		{"multipolygon with flat polygon crosses polygon", orb.MultiPolygon{flatPolygon}, unitSquare, Crosses, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.pred(tt.a, tt.b); result != tt.expected {
+				t.Errorf("got %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+
+	boundaries := []struct {
+		name     string
+		g        orb.Geometry
+		expected orb.Geometry
+	}{
+		{"zero-width bound", zeroWidthInside, orb.MultiPoint{{5, 0}, {5, 10}}},
+		{"point bound", pointBound, orb.Collection{}},
+		{"flat polygon", flatPolygon, orb.MultiPoint{{-5, 5}, {15, 5}}},
+		{"polygon with a spike", spikePolygon, orb.MultiPoint{{0, 5}, {10, 8}}},
+	}
+	for _, tt := range boundaries {
+		t.Run("boundary "+tt.name, func(t *testing.T) {
+			if result := Boundary(tt.g); !orb.Equal(result, tt.expected) {
+				t.Errorf("Boundary(%v) = %v, expected %v", tt.g, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBoundary(t *testing.T) {
+	closedLine := orb.LineString{orb.Point{0, 0}, orb.Point{10, 0}, orb.Point{10, 10}, orb.Point{0, 0}}
+	joinedLines := orb.MultiLineString{
+		orb.LineString{orb.Point{0, 0}, orb.Point{5, 0}},
+		orb.LineString{orb.Point{5, 0}, orb.Point{10, 0}},
+		orb.LineString{orb.Point{5, 0}, orb.Point{5, 5}},
+	}
+
+	tests := []struct {
+		name     string
+		g        orb.Geometry
+		expected orb.Geometry
+	}{
+		{"point", pointInside, orb.Collection{}},
+		{"multipoint", multiPointAllInside, orb.Collection{}},
+		{"linestring", lineInside, orb.MultiPoint{orb.Point{2, 2}, orb.Point{8, 8}}},
+		{"closed linestring", closedLine, orb.MultiPoint{}},
+		{"multilinestring mod-2", joinedLines, orb.MultiPoint{orb.Point{0, 0}, orb.Point{5, 0}, orb.Point{10, 0}, orb.Point{5, 5}}},
+		{"multilinestring shared end point", joinedLines[:2], orb.MultiPoint{orb.Point{0, 0}, orb.Point{10, 0}}},
+		{"ring", ringInside, orb.MultiLineString{orb.LineString(ringInside)}},
+		{"polygon", unitSquare, orb.MultiLineString{orb.LineString(unitSquare[0])}},
+		{"multipolygon", multiPolygon, orb.MultiLineString{
+			orb.LineString(multiPolygon[0][0]), orb.LineString(multiPolygon[1][0]),
+		}},
+		{"bound", testBound, orb.MultiLineString{orb.LineString(boundToPolygon(testBound)[0])}},
+		{"collection", orb.Collection{pointInside, lineInside, smallSquare}, orb.Collection{
+			orb.MultiPoint{orb.Point{2, 2}, orb.Point{8, 8}},
+			orb.MultiLineString{orb.LineString(smallSquare[0])},
+		}},
+		{"collection line inside area", orb.Collection{
+			orb.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}},
+			orb.LineString{{2, 2}, {8, 8}},
+		}, orb.MultiLineString{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}},
+		{"collection areas sharing an edge", orb.Collection{
+			orb.Polygon{{{0, 0}, {5, 0}, {5, 10}, {0, 10}, {0, 0}}},
+			orb.Polygon{{{5, 0}, {10, 0}, {10, 10}, {5, 10}, {5, 0}}},
+		}, orb.MultiLineString{{{5, 10}, {0, 10}, {0, 0}, {5, 0}}, {{5, 0}, {10, 0}, {10, 10}, {5, 10}}}},
+		{"collection line across area", orb.Collection{
+			orb.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}},
+			orb.LineString{{5, 5}, {15, 5}},
+		}, orb.Collection{
+			orb.MultiPoint{{15, 5}},
+			orb.MultiLineString{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Boundary(tt.g)
+			if !orb.Equal(result, tt.expected) {
+				t.Errorf("Boundary(%v) = %v, expected %v", tt.g, result, tt.expected)
+			}
+		})
+	}
+
+	// The boundary agrees with the locations the predicates use
+	gc := orb.Collection{
+		orb.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}},
+		orb.LineString{{2, 2}, {8, 8}},
+	}
+	if Within(orb.Point{2, 2}, Boundary(gc)) {
+		t.Errorf("point in the interior of %v is within its boundary", gc)
+	}
+	if !Within(orb.Point{0, 5}, Boundary(gc)) {
+		t.Errorf("point on the boundary of %v is not within it", gc)
+	}
+}
+
+func TestBoundaryNodeRule(t *testing.T) {
+	closedLine := orb.LineString{{0, 0}, {10, 0}, {10, 10}, {0, 0}}
+	joinedLines := orb.MultiLineString{{{0, 0}, {5, 0}}, {{5, 0}, {10, 0}}}
+
+	boundaries := []struct {
+		rule     BoundaryNodeRule
+		g        orb.Geometry
+		expected orb.Geometry
+	}{
+		{Mod2Rule, closedLine, orb.MultiPoint{}},
+		{EndPointRule, closedLine, orb.MultiPoint{{0, 0}}},
+		{MultiValentRule, closedLine, orb.MultiPoint{{0, 0}}},
+		{MonoValentRule, closedLine, orb.MultiPoint{}},
+		{Mod2Rule, joinedLines, orb.MultiPoint{{0, 0}, {10, 0}}},
+		{EndPointRule, joinedLines, orb.MultiPoint{{0, 0}, {5, 0}, {10, 0}}},
+		{MultiValentRule, joinedLines, orb.MultiPoint{{5, 0}}},
+		{MonoValentRule, joinedLines, orb.MultiPoint{{0, 0}, {10, 0}}},
+		{MultiValentRule, lineInside, orb.MultiPoint{}},
+		{EndPointRule, unitSquare, orb.MultiLineString{orb.LineString(unitSquare[0])}},
+	}
+	for _, tt := range boundaries {
+		t.Run("boundary "+tt.rule.String(), func(t *testing.T) {
+			if result := WithBoundaryNodeRule(tt.rule).Boundary(tt.g); !orb.Equal(result, tt.expected) {
+				t.Errorf("Boundary(%v) = %v, expected %v", tt.g, result, tt.expected)
+			}
+		})
+	}
+
+	end := orb.Point{0, 0}
+	tests := []struct {
+		name     string
+		rule     BoundaryNodeRule
+		pred     func(e Evaluator) bool
+		expected bool
+	}{
+		{"mod-2 end point not within line", Mod2Rule, func(e Evaluator) bool { return e.Within(end, joinedLines) }, false},
+		{"multivalent end point within line", MultiValentRule, func(e Evaluator) bool { return e.Within(end, joinedLines) }, true},
+		{"mod-2 joined lines touch", Mod2Rule, func(e Evaluator) bool { return e.Touches(joinedLines[0], joinedLines[1]) }, true},
+		{"multivalent joined lines cross", MultiValentRule, func(e Evaluator) bool { return e.Crosses(joinedLines[0], joinedLines[1]) }, true},
+		{"endpoint join touches", EndPointRule, func(e Evaluator) bool { return e.Touches(joinedLines, orb.Point{5, 0}) }, true},
+		{"mod-2 join does not touch", Mod2Rule, func(e Evaluator) bool { return e.Touches(joinedLines, orb.Point{5, 0}) }, false},
+		{"endpoint closed line contains properly", EndPointRule, func(e Evaluator) bool {
+			return e.ContainsProperly(closedLine, orb.Point{10, 0})
+		}, true},
+		{"mod-2 multipoint at end point does not cross", Mod2Rule, func(e Evaluator) bool {
+			return e.Crosses(orb.MultiPoint{{0, 0}, {5, 5}}, orb.LineString{{0, 0}, {10, 0}})
+		}, false},
+		{"multivalent multipoint at end point crosses", MultiValentRule, func(e Evaluator) bool {
+			return e.Crosses(orb.MultiPoint{{0, 0}, {5, 5}}, orb.LineString{{0, 0}, {10, 0}})
+		}, true},
+		{"areas ignore the rule", EndPointRule, func(e Evaluator) bool { return e.Touches(unitSquare, touchingSquare) }, true},
+		{"empty geometries under a rule", EndPointRule, func(e Evaluator) bool { return e.Overlaps(orb.LineString{}, lineInside) }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.pred(WithBoundaryNodeRule(tt.rule)); result != tt.expected {
+				t.Errorf("got %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPredicatesAgainstBoundary(t *testing.T) {
+	tests := []struct {
+		name      string
+		predicate func(a, b orb.Geometry) bool
+		a, b      orb.Geometry
+		expected  bool
+	}{
+		{"edge point within polygon boundary", Within, pointOnEdge, Boundary(unitSquare), true},
+		{"corner point within polygon boundary", Within, pointOnCorner, Boundary(unitSquare), true},
+		{"interior point not within polygon boundary", Within, pointInside, Boundary(unitSquare), false},
+		{"edge within polygon boundary", Within, lineOnEdge, Boundary(unitSquare), true},
+		{"end point within line boundary", Within, orb.Point{2, 2}, Boundary(lineInside), true},
+		{"polygon boundary touches polygon", Intersects, Boundary(unitSquare), unitSquare, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.predicate(tt.a, tt.b)
+			if result != tt.expected {
+				t.Errorf("%s = %v, expected %v", tt.name, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRelate(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     orb.Geometry
+		expected string
+	}{
+		{"point in polygon", pointInside, unitSquare, "0FFFFF212"},
+		{"point on boundary", orb.Point{0, 5}, unitSquare, "F0FFFF212"},
+		{"polygon contains polygon", unitSquare, smallSquare, "212FF1FF2"},
+		{"line crosses line", orb.LineString{{0, 0}, {2, 2}}, orb.LineString{{0, 2}, {2, 0}}, "0F1FF0102"},
+		{"collapsed bound", orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{0, 1}}, orb.Point{0, 0.5}, "0F1FF0FF2"},
+		{"empty", orb.Polygon{}, pointInside, "FFFFFF0F2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Relate(tt.a, tt.b); got != tt.expected {
+				t.Errorf("Relate(%v, %v) = %s, expected %s", tt.a, tt.b, got, tt.expected)
+			}
+		})
+	}
+
+	for _, tt := range []struct {
+		matrix, pattern string
+		expected        bool
+	}{
+		{"0FFFFF212", "T*F**F***", true},
+		{"0FFFFF212", "T*****FF*", false},
+		{"212101212", "2*2******", true},
+		{"212101212", "212101212", true},
+		{"212101212", "T*T***T**", true},
+		{"FF0FFF212", "F*0******", true},
+		{"212101212", "2121", false},
+		{"21210121X", "*********", false},
+		{"212101212", "*********X", false},
+	} {
+		if got := RelateMatch(tt.matrix, tt.pattern); got != tt.expected {
+			t.Errorf("RelateMatch(%s, %s) = %v, expected %v", tt.matrix, tt.pattern, got, tt.expected)
+		}
+	}
+	for pattern, expected := range map[string]bool{"T*F**F***": true, "tf*012***": true, "T*F**F**": false, "T*F**F**X": false} {
+		if got := ValidPattern(pattern); got != expected {
+			t.Errorf("ValidPattern(%s) = %v, expected %v", pattern, got, expected)
+		}
+	}
+
+	// The end points of a closed line are interior under Mod-2 but boundary
+	// under the end point rule
+	closed := orb.LineString{{0, 0}, {1, 0}, {1, 1}, {0, 0}}
+	if got := WithBoundaryNodeRule(EndPointRule).Relate(orb.Point{0, 0}, closed); got != "F0FFFF1F2" {
+		t.Errorf("Relate under EndPointRule = %s, expected F0FFFF1F2", got)
+	}
+}
+
+func TestEquals(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     orb.Geometry
+		expected bool
+	}{
+		{"same polygon", unitSquare, unitSquare, true},
+		{"extra vertex", unitSquare, orb.Polygon{{{0, 0}, {5, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}, true},
+		{"bound and polygon", orb.Bound{Max: orb.Point{10, 10}}, unitSquare, true},
+		{"reversed line", orb.LineString{{0, 0}, {5, 5}}, orb.LineString{{5, 5}, {0, 0}}, true},
+		{"polygon within", smallSquare, unitSquare, false},
+		{"line on boundary", lineOnEdge, unitSquare, false},
+		{"both empty", orb.Polygon{}, orb.LineString{}, true},
+		{"one empty", orb.Polygon{}, unitSquare, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Equals(tt.a, tt.b); got != tt.expected {
+				t.Errorf("Equals(%v, %v) = %v, expected %v", tt.a, tt.b, got, tt.expected)
+			}
+			if got := Equals(tt.b, tt.a); got != tt.expected {
+				t.Errorf("Equals(%v, %v) = %v, expected %v", tt.b, tt.a, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestInteriorPoint(t *testing.T) {
+	tests := []struct {
+		name string
+		g    orb.Geometry
+	}{
+		{"polygon", unitSquare},
+		{"c-shaped polygon", cShape},
+		{"polygon with hole", donut},
+		{"ring", ringInside},
+		{"bound", testBound},
+		{"multipolygon", multiPolygon},
+		{"linestring", lineInside},
+		{"closed linestring", orb.LineString{{0, 0}, {10, 0}, {10, 10}, {0, 0}}},
+		{"multilinestring", multiLineString},
+		{"point", pointInside},
+		{"multipoint", multiPointAllInside},
+		{"collection", orb.Collection{lineInside, cShape, pointOutside}},
+		{"zero-area polygon", orb.Polygon{{{0, 0}, {10, 0}, {5, 0}, {0, 0}}}},
+		{"zero-width bound", orb.Bound{Min: orb.Point{5, 0}, Max: orb.Point{5, 10}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, ok := InteriorPoint(tt.g)
+			if !ok {
+				t.Fatalf("InteriorPoint(%v) found no point", tt.g)
+			}
+			if !Intersects(p, tt.g) {
+				t.Errorf("InteriorPoint(%v) = %v, which does not intersect the geometry", tt.g, p)
+			}
+			if Intersects(p, Boundary(tt.g)) {
+				t.Errorf("InteriorPoint(%v) = %v, which lies on the boundary", tt.g, p)
+			}
+		})
+	}
+
+	for _, g := range []orb.Geometry{orb.MultiPoint{}, orb.LineString{}, orb.Polygon{}, orb.Collection{}} {
+		if p, ok := InteriorPoint(g); ok {
+			t.Errorf("InteriorPoint(%v) = %v, expected no point", g, p)
+		}
+	}
+}
+
+func TestNonConvexPredicates(t *testing.T) {
+	// A square with a hole filling the notch of cShape
+	notchHole := orb.Polygon{
+		orb.Ring{{-1, -1}, {11, -1}, {11, 11}, {-1, 11}, {-1, -1}},
+		orb.Ring{{3, 3}, {3, 7}, {9, 7}, {9, 3}, {3, 3}},
+	}
+	// A square sitting in the hole of donut, sharing its edges
+	holeSquare := orb.Polygon{orb.Ring{{3, 3}, {7, 3}, {7, 7}, {3, 7}, {3, 3}}}
+
+	tests := []struct {
+		name      string
+		predicate func(a, b orb.Geometry) bool
+		a, b      orb.Geometry
+		expected  bool
+	}{
+		{"c-shape within square with hole in notch", Within, cShape, notchHole, true},
+		{"c-shape within bound", Within, cShape, testBound, true},
+		{"c-shape ring within square with hole in notch", Within, cShape[0], notchHole, true},
+		{"donut within square with hole in notch", Within, donut, notchHole, false},
+		{"square in hole touches donut", Touches, holeSquare, donut, true},
+		{"square in hole does not overlap donut", Overlaps, holeSquare, donut, false},
+		{"c-shape overlaps square with hole in notch", Overlaps, cShape, notchHole, false},
+		{"c-shape interior intersects bound", Intersects, cShape, testBound, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.predicate(tt.a, tt.b)
+			if result != tt.expected {
+				t.Errorf("%s = %v, expected %v", tt.name, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLineAreaExact(t *testing.T) {
+	// A U-shaped ring whose notch reaches down to y=0.5 between x=4 and x=6
+	uRing := orb.Ring{{0, 0}, {10, 0}, {10, 10}, {6, 10}, {6, 0.5}, {4, 0.5}, {4, 10}, {0, 10}, {0, 0}}
+	// Two squares separated by a gap that falls between evenly spaced samples
+	gapped := orb.MultiPolygon{
+		unitSquare,
+		{{{10.1, 0}, {20, 0}, {20, 10}, {10.1, 10}, {10.1, 0}}},
+	}
+	adjacent := orb.MultiPolygon{unitSquare, touchingSquare}
+	// Vertices and segment midpoint all lie outside unitSquare
+	longLine := orb.LineString{{-100, 5}, {11, 5}}
+	// Vertices inside uRing, midpoint on the notch edge
+	notchLine := orb.LineString{{1, 1}, {7, 1}}
+
+	tests := []struct {
+		name      string
+		predicate func(a, b orb.Geometry) bool
+		a, b      orb.Geometry
+		expected  bool
+	}{
+		{"line across gap not within multipolygon", Within, orb.LineString{{1, 5}, {19, 5}}, gapped, false},
+		{"line across gap crosses multipolygon", Crosses, orb.LineString{{1, 5}, {19, 5}}, gapped, true},
+		{"line through notch not within ring", Within, notchLine, uRing, false},
+		{"line through notch not within polygon", Within, notchLine, orb.Polygon{uRing}, false},
+		{"polygon with notch not covers line", Covers, orb.Polygon{uRing}, notchLine, false},
+		{"line through notch crosses polygon", Crosses, notchLine, orb.Polygon{uRing}, true},
+		{"long line crosses polygon", Crosses, longLine, unitSquare, true},
+		{"polygon crossed by long line", Crosses, unitSquare, longLine, true},
+		{"long line crosses bound", Crosses, longLine, testBound, true},
+		{"long line does not touch polygon", Touches, longLine, unitSquare, false},
+		{"long line does not touch bound", Touches, longLine, testBound, false},
+		{"line over shared edge within adjacent squares", Within, orb.LineString{{5, 5}, {15, 5}}, adjacent, true},
+		{"line over shared edge does not cross adjacent squares", Crosses, orb.LineString{{5, 5}, {15, 5}}, adjacent, false},
+		{"multilinestring within polygon with one part on boundary", Within, orb.MultiLineString{lineOnEdge, lineInside}, unitSquare, true},
+		{"multilinestring with parts inside and outside crosses polygon", Crosses, orb.MultiLineString{lineInside, lineOutside}, unitSquare, true},
+		{"line along edge touches polygon", Touches, lineOnEdge, unitSquare, true},
+		{"line grazing corner touches polygon", Touches, orb.LineString{{-5, 5}, {0, 10}, {5, 15}}, unitSquare, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.predicate(tt.a, tt.b)
+			if result != tt.expected {
+				t.Errorf("%s = %v, expected %v", tt.name, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTypedPredicates(t *testing.T) {
+	emptyPoint := orb.Point{math.NaN(), math.NaN()}
+	flat := orb.Polygon{{{-5, 5}, {15, 5}, {5, 5}, {-5, 5}}}
+	points := []orb.Point{pointInside, pointOutside, pointOnEdge, pointOnCorner, pointInSmall, pointInOverlap, {10, 5}, emptyPoint}
+	polygons := []orb.Polygon{unitSquare, smallSquare, overlappingSquare, disjointSquare, touchingSquare, cShape, donut, flat, {}}
+	lines := []orb.LineString{lineInside, lineCrossing, lineOutside, lineTouching, lineOnEdge, {{5, 5}}, {}}
+	bounds := []orb.Bound{
+		testBound,
+		{Min: orb.Point{5, 5}, Max: orb.Point{20, 20}},
+		{Min: orb.Point{20, 20}, Max: orb.Point{30, 30}},
+		{Min: orb.Point{10, 0}, Max: orb.Point{20, 10}},
+		{Min: orb.Point{5, -5}, Max: orb.Point{5, 15}},
+		{Min: orb.Point{5, 5}, Max: orb.Point{5, 5}},
+		{Min: orb.Point{1, 1}, Max: orb.Point{0, 0}},
+	}
+
+	check := func(name string, got, want bool, a, b interface{}) {
+		t.Helper()
+		if got != want {
+			t.Errorf("%s(%v, %v) = %v, expected %v", name, a, b, got, want)
+		}
+	}
+
+	for _, p := range points {
+		for _, poly := range polygons {
+			check("PointInPolygon", PointInPolygon(p, poly), Within(p, poly), p, poly)
+			check("PolygonCoversPoint", PolygonCoversPoint(poly, p), Covers(poly, p), poly, p)
+			if len(poly) > 0 {
+				check("PointInRing", PointInRing(p, poly[0]), Within(p, poly[0]), p, poly[0])
+			}
+		}
+		mp := orb.MultiPolygon{smallSquare, touchingSquare}
+		check("PointInMultiPolygon", PointInMultiPolygon(p, mp), Within(p, mp), p, mp)
+		check("PointInMultiPolygon", PointInMultiPolygon(p, orb.MultiPolygon{flat}), Within(p, orb.MultiPolygon{flat}), p, flat)
+		for _, b := range bounds {
+			check("PointInBound", PointInBound(p, b), Within(p, b), p, b)
+			check("BoundCoversPoint", BoundCoversPoint(b, p), Covers(b, p), b, p)
+		}
+	}
+
+	for _, ls := range lines {
+		for _, ls2 := range lines {
+			check("LineStringIntersectsLineString", LineStringIntersectsLineString(ls, ls2), Intersects(ls, ls2), ls, ls2)
+		}
+		for _, poly := range polygons {
+			check("LineStringIntersectsPolygon", LineStringIntersectsPolygon(ls, poly), Intersects(ls, poly), ls, poly)
+		}
+		for _, b := range bounds {
+			check("BoundIntersectsLineString", BoundIntersectsLineString(b, ls), Intersects(b, ls), b, ls)
+		}
+	}
+
+	for _, poly := range polygons {
+		for _, poly2 := range polygons {
+			check("PolygonIntersectsPolygon", PolygonIntersectsPolygon(poly, poly2), Intersects(poly, poly2), poly, poly2)
+		}
+		for _, b := range bounds {
+			check("BoundIntersectsPolygon", BoundIntersectsPolygon(b, poly), Intersects(b, poly), b, poly)
+		}
+	}
+
+	for _, b := range bounds {
+		for _, b2 := range bounds {
+			check("BoundIntersectsBound", BoundIntersectsBound(b, b2), Intersects(b, b2), b, b2)
+		}
+	}
+}
+
+func TestTypedPredicatesDoNotAllocate(t *testing.T) {
+	bound := orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{100, 100}}
+	tests := map[string]func(){
+		"PointInPolygon":                 func() { PointInPolygon(pointInside, unitSquare) },
+		"PointInRing":                    func() { PointInRing(pointInside, unitSquare[0]) },
+		"PointInMultiPolygon":            func() { PointInMultiPolygon(pointInside, multiPolygon) },
+		"PointInBound":                   func() { PointInBound(pointInside, bound) },
+		"PolygonCoversPoint":             func() { PolygonCoversPoint(unitSquare, pointOnEdge) },
+		"BoundCoversPoint":               func() { BoundCoversPoint(bound, pointInside) },
+		"LineStringIntersectsLineString": func() { LineStringIntersectsLineString(lineInside, lineCrossing) },
+		"LineStringIntersectsPolygon":    func() { LineStringIntersectsPolygon(lineOutside, unitSquare) },
+		"PolygonIntersectsPolygon":       func() { PolygonIntersectsPolygon(unitSquare, disjointSquare) },
+		"BoundIntersectsBound":           func() { BoundIntersectsBound(bound, testBound) },
+		"BoundIntersectsLineString":      func() { BoundIntersectsLineString(bound, lineOutside) },
+		"BoundIntersectsPolygon":         func() { BoundIntersectsPolygon(bound, disjointSquare) },
+	}
+	for name, f := range tests {
+		if allocs := testing.AllocsPerRun(100, f); allocs != 0 {
+			t.Errorf("%s allocates %v times per call", name, allocs)
+		}
+	}
+}
+
+// testDiamond is a custom area: the points within r of c in the L1 norm.
+// It embeds orb.Geometry because the interface has an unexported method.
+type testDiamond struct {
+	orb.Geometry
+	c orb.Point
+	r float64
+}
+
+func (d testDiamond) GeoJSONType() string { return "Polygon" }
+func (d testDiamond) Dimensions() int     { return 2 }
+func (d testDiamond) Bound() orb.Bound {
+	return orb.Bound{Min: orb.Point{d.c[0] - d.r, d.c[1] - d.r}, Max: orb.Point{d.c[0] + d.r, d.c[1] + d.r}}
+}
+func (d testDiamond) Boundary() orb.Geometry { return orb.LineString(d.polygon()[0]) }
+func (d testDiamond) Locate(p orb.Point) Location {
+	dist := math.Abs(p[0]-d.c[0]) + math.Abs(p[1]-d.c[1]) - d.r
+	switch {
+	case math.Abs(dist) <= epsilon:
+		return OnBoundary
+	case dist < 0:
+		return Interior
+	}
+	return Exterior
+}
+
+// polygon returns the same area as an orb.Polygon
+func (d testDiamond) polygon() orb.Polygon {
+	x, y, r := d.c[0], d.c[1], d.r
+	return orb.Polygon{{{x - r, y}, {x, y - r}, {x + r, y}, {x, y + r}, {x - r, y}}}
+}
+
+// testParcel wraps an orb geometry and is registered with RegisterType
+type testParcel struct {
+	orb.Geometry
+	id int
+}
+
+// testUnknown is a geometry type the predicates know nothing about
+type testUnknown struct {
+	orb.Point
+}
+
+// testLineRelater claims to be a custom line, which Relater does not support
+type testLineRelater struct{ testDiamond }
+
+func (testLineRelater) Dimensions() int { return 1 }
+
+// supportedPredicates maps the lower case names of the predicates to them
+var supportedPredicates = map[string]func(a, b orb.Geometry) bool{
+	"intersects":       Intersects,
+	"contains":         Contains,
+	"within":           Within,
+	"covers":           Covers,
+	"coveredby":        CoveredBy,
+	"crosses":          Crosses,
+	"overlaps":         Overlaps,
+	"touches":          Touches,
+	"disjoint":         Disjoint,
+	"containsproperly": ContainsProperly,
+	"equals":           Equals,
+}
+
+func TestCustomTypes(t *testing.T) {
+	RegisterType(func(p testParcel) orb.Geometry { return p.Geometry })
+
+	diamond := testDiamond{c: orb.Point{5, 5}, r: 5}
+	others := []orb.Geometry{
+		pointInside, pointOutside, pointOnEdge, orb.Point{5, 0}, orb.Point{2.5, 2.5},
+		orb.MultiPoint{{5, 5}, {20, 20}},
+		lineInside, lineCrossing, lineOutside, orb.LineString{{0, 5}, {10, 5}},
+		orb.LineString{{0, 5}, {5, 0}}, orb.LineString{{-5, 5}, {0, 5}},
+		unitSquare, smallSquare, disjointSquare, cShape, donut,
+		orb.Polygon{{{0, 5}, {5, 0}, {5, 5}, {0, 5}}},
+		orb.Collection{orb.Point{5, 5}, orb.LineString{{20, 0}, {20, 10}}},
+		testDiamond{c: orb.Point{10, 5}, r: 5},
+		testDiamond{c: orb.Point{5, 5}, r: 2},
+	}
+
+	// Custom areas are evaluated by the relate engine, so the same area as a
+	// polygon inside a Collection is the reference
+	for name, pred := range supportedPredicates {
+		for _, other := range others {
+			want := pred(orb.Collection{diamond.polygon()}, other)
+			if d, ok := other.(testDiamond); ok {
+				want = pred(orb.Collection{diamond.polygon()}, d.polygon())
+			}
+			if got := pred(diamond, other); got != want {
+				t.Errorf("%s(diamond, %v) = %v, expected %v", name, other, got, want)
+			}
+			// A registered type behaves exactly like the geometry it wraps
+			if got, want := pred(testParcel{Geometry: unitSquare}, other), pred(unitSquare, other); got != want {
+				t.Errorf("%s(parcel, %v) = %v, expected %v", name, other, got, want)
+			}
+			if got := pred(orb.Collection{diamond}, other); got != want {
+				t.Errorf("%s(collection of diamond, %v) = %v, expected %v", name, other, got, want)
+			}
+		}
+	}
+
+	if d := Dimension(diamond); d != 2 {
+		t.Errorf("Dimension(diamond) = %d, expected 2", d)
+	}
+	if d := Dimension(testParcel{Geometry: lineInside}); d != 1 {
+		t.Errorf("Dimension(line parcel) = %d, expected 1", d)
+	}
+	if IsEmpty(testParcel{Geometry: orb.LineString{}}) != true {
+		t.Error("IsEmpty(empty parcel) = false, expected true")
+	}
+	if b := Envelope(orb.Collection{testParcel{Geometry: pointInside}, diamond}); b != diamond.Bound() {
+		t.Errorf("Envelope = %v, expected %v", b, diamond.Bound())
+	}
+	if b, ok := Boundary(diamond).(orb.MultiLineString); !ok || len(b) != 1 {
+		t.Errorf("Boundary(diamond) = %v, expected one line", Boundary(diamond))
+	}
+	resolved := Resolve(orb.Collection{testParcel{Geometry: lineInside}, diamond})
+	if c, ok := resolved.(orb.Collection); !ok || len(c) != 2 || !orb.Equal(c[0], lineInside) || c[1] != orb.Geometry(diamond) {
+		t.Errorf("Resolve = %v, expected the line and the diamond", resolved)
+	}
+	if p, ok := InteriorPoint(diamond); !ok || diamond.Locate(p) != Interior {
+		t.Errorf("InteriorPoint(diamond) = %v, %v, expected an interior point", p, ok)
+	}
+}
+
+func TestUnsupportedTypes(t *testing.T) {
+	RegisterType(func(p testParcel) orb.Geometry { return p.Geometry })
+
+	tests := []struct {
+		name      string
+		geom      orb.Geometry
+		supported bool
+	}{
+		{"orb type", unitSquare, true},
+		{"relater", testDiamond{c: orb.Point{5, 5}, r: 5}, true},
+		{"registered", testParcel{Geometry: unitSquare}, true},
+		{"registered as unknown", testParcel{Geometry: testUnknown{}}, false},
+		{"unknown", testUnknown{}, false},
+		{"unknown in collection", orb.Collection{pointInside, testUnknown{}}, false},
+		{"line relater", testLineRelater{}, false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckGeometry(tt.geom)
+			if tt.supported && err != nil {
+				t.Errorf("CheckGeometry() = %v, expected nil", err)
+			}
+			if !tt.supported && !errors.Is(err, ErrUnsupportedType) {
+				t.Errorf("CheckGeometry() = %v, expected ErrUnsupportedType", err)
+			}
+
+			_, err = Check(Intersects, unitSquare, tt.geom)
+			if tt.supported != (err == nil) {
+				t.Errorf("Check() error = %v, expected supported %v", err, tt.supported)
+			}
+			r, err := WithBoundaryNodeRule(Mod2Rule).Evaluate(tt.geom, unitSquare, PredDisjoint)
+			if tt.supported != (err == nil) || (!tt.supported && !errors.Is(err, ErrUnsupportedType)) {
+				t.Errorf("Evaluate() error = %v, expected supported %v", err, tt.supported)
+			}
+			if !tt.supported && r != 0 {
+				t.Errorf("Evaluate() = %v with an error, expected none", r)
+			}
+		})
+	}
+
+	if ok, err := Check(Contains, unitSquare, pointInside); !ok || err != nil {
+		t.Errorf("Check(Contains) = %v, %v, expected true, nil", ok, err)
+	}
+}
+
+func TestRegisterTypePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("RegisterType(orb.Point) did not panic")
+		}
+	}()
+	RegisterType(func(p orb.Point) orb.Geometry { return p })
+}
+
+func TestCircle(t *testing.T) {
+	c := Circle{Center: orb.Point{5, 5}, Radius: 5}
+	box := func(x0, y0, x1, y1 float64) orb.Polygon {
+		return orb.Polygon{{{x0, y0}, {x1, y0}, {x1, y1}, {x0, y1}, {x0, y0}}}
+	}
+	// A point just inside the circle that a 64-gon would leave outside
+	angle := 2 * math.Pi / 128
+	nearEdge := orb.Point{5 + 4.9999*math.Cos(angle), 5 + 4.9999*math.Sin(angle)}
+
+	tests := []struct {
+		name      string
+		predicate string
+		a, b      orb.Geometry
+		expected  bool
+	}{
+		{"centre within", "within", orb.Point{5, 5}, c, true},
+		{"point near the circumference within", "within", nearEdge, c, true},
+		{"point on the circumference not within", "within", orb.Point{10, 5}, c, false},
+		{"point on the circumference covered", "covers", c, orb.Point{10, 5}, true},
+		{"point on the circumference touches", "touches", orb.Point{10, 5}, c, true},
+		{"point outside the circle but inside its bound", "intersects", orb.Point{9, 9}, c, false},
+		{"multipoint partly inside intersects", "intersects", orb.MultiPoint{{5, 5}, {20, 20}}, c, true},
+		{"multipoint partly inside not within", "within", orb.MultiPoint{{5, 5}, {20, 20}}, c, false},
+
+		{"circle within circumscribed square", "within", c, box(0, 0, 10, 10), true},
+		{"circumscribed square does not contain it properly", "containsproperly", box(0, 0, 10, 10), c, false},
+		{"inscribed square within circle", "within", box(5-3.5, 5-3.5, 5+3.5, 5+3.5), c, true},
+		{"small square contained properly", "containsproperly", c, box(4, 4, 6, 6), true},
+		{"square over the circumference overlaps", "overlaps", c, box(8, 8, 12, 12), true},
+		{"square beyond the circumference disjoint", "disjoint", c, box(9, 9, 12, 12), true},
+		{"square against the circumference touches", "touches", c, box(10, 0, 20, 10), true},
+
+		{"diameter within", "within", orb.LineString{{0, 5}, {10, 5}}, c, true},
+		{"line through crosses", "crosses", orb.LineString{{-5, 5}, {15, 5}}, c, true},
+		{"tangent line touches", "touches", orb.LineString{{0, 10}, {10, 10}}, c, true},
+		{"line above disjoint", "disjoint", orb.LineString{{0, 11}, {10, 11}}, c, true},
+
+		{"circles touching from outside", "touches", c, Circle{Center: orb.Point{15, 5}, Radius: 5}, true},
+		{"circles overlapping", "overlaps", c, Circle{Center: orb.Point{12, 5}, Radius: 5}, true},
+		{"circle inside circle", "containsproperly", c, Circle{Center: orb.Point{6, 5}, Radius: 2}, true},
+		{"circle touching from within is within", "within", Circle{Center: orb.Point{8, 5}, Radius: 2}, c, true},
+		{"circle touching from within is not contained properly", "containsproperly", c, Circle{Center: orb.Point{8, 5}, Radius: 2}, false},
+		{"equal circles", "within", c, c, true},
+		{"circumference touches its circle", "touches", c.boundaryString(), c, true},
+		{"circumference covered by its circle", "coveredby", c.boundaryString(), c, true},
+
+		{"circle in collection", "within", orb.Point{5, 5}, orb.Collection{c, box(20, 20, 30, 30)}, true},
+		{"zero radius circle is a point", "within", Circle{Center: orb.Point{5, 5}}, box(0, 0, 10, 10), true},
+		{"empty circle", "intersects", Circle{Center: orb.Point{5, 5}, Radius: -1}, c, false},
+		{"empty circle disjoint", "disjoint", Circle{Center: orb.Point{5, 5}, Radius: -1}, c, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := supportedPredicates[tt.predicate](tt.a, tt.b); got != tt.expected {
+				t.Errorf("%s() = %v, expected %v", tt.predicate, got, tt.expected)
+			}
+		})
+	}
+
+	if d := Dimension(c); d != 2 {
+		t.Errorf("Dimension(circle) = %d, expected 2", d)
+	}
+	if d := Dimension(Circle{Center: orb.Point{1, 1}}); d != 0 {
+		t.Errorf("Dimension(zero radius circle) = %d, expected 0", d)
+	}
+	if p, ok := InteriorPoint(c); !ok || p != c.Center {
+		t.Errorf("InteriorPoint(circle) = %v, %v, expected the centre", p, ok)
+	}
+	if !PointInCircle(nearEdge, c) || PointInCircle(orb.Point{10, 5}, c) || !CircleCoversPoint(c, orb.Point{10, 5}) {
+		t.Error("PointInCircle and CircleCoversPoint disagree with Within and Covers")
+	}
+	if err := CheckGeometry(c); err != nil {
+		t.Errorf("CheckGeometry(circle) = %v", err)
+	}
+}
+
+func TestCircularString(t *testing.T) {
+	arc := CircularString{Points: []orb.Point{{0, 0}, {5, 5}, {10, 0}}}
+	closed := CircularString{Points: []orb.Point{{0, 0}, {10, 0}, {0, 0}}}
+
+	if b, want := arc.Bound(), (orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{10, 5}}); !b.Equal(want) {
+		t.Errorf("Bound() = %v, expected %v", b, want)
+	}
+	if b, want := closed.Bound(), (orb.Bound{Min: orb.Point{0, -5}, Max: orb.Point{10, 5}}); !b.Equal(want) {
+		t.Errorf("Bound(closed) = %v, expected %v", b, want)
+	}
+	if b := Boundary(arc); !orb.Equal(b, orb.MultiPoint{{0, 0}, {10, 0}}) {
+		t.Errorf("Boundary() = %v, expected the end points", b)
+	}
+	if b := Boundary(closed); !IsEmpty(b) {
+		t.Errorf("Boundary(closed) = %v, expected empty", b)
+	}
+	if d := Dimension(arc); d != 1 {
+		t.Errorf("Dimension() = %d, expected 1", d)
+	}
+	if p, ok := InteriorPoint(arc); !ok || !Intersects(p, arc) || Touches(p, arc) {
+		t.Errorf("InteriorPoint() = %v, %v, expected a point in the interior", p, ok)
+	}
+
+	// The arc lies within the circle it is part of, on its circumference
+	c := Circle{Center: orb.Point{5, 0}, Radius: 5}
+	if !Covers(c, arc) || Within(arc, c) || !Touches(arc, c) {
+		t.Error("arc on a circumference should be covered by and touch the circle")
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected float64
+	}{
+		{"POINT (0 0)", "POINT (3 4)", 5},
+		{"POINT (0 0)", "LINESTRING (-5 1, 5 1)", 1},
+		{"POLYGON ((0 0, 2 0, 2 2, 0 2, 0 0))", "POLYGON ((5 0, 7 0, 7 2, 5 2, 5 0))", 3},
+		{"POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0))", "POINT (5 5)", 0},
+		{"MULTIPOINT ((0 10), (20 20))", "GEOMETRYCOLLECTION (POINT (0 0), LINESTRING (10 10, 10 20))", 10},
+
+		// Arcs are measured to the point where they bulge, and an arc
+		// through three points on a line is its chord
+		{"CIRCULARSTRING (0 0, 1 1, 2 0)", "POINT (1 3)", 2},
+		{"CIRCULARSTRING (0 0, 1 -1, 2 0)", "POINT (1 3)", math.Sqrt(10)},
+		{"CIRCULARSTRING (0 0, 1 1, 2 0)", "LINESTRING (-5 3, 5 3)", 2},
+		{"CIRCULARSTRING (0 0, 1 1, 2 0)", "CIRCULARSTRING (0 4, 1 3, 2 4)", 2},
+		{"CIRCULARSTRING (0 0, 2 0, 0 0)", "POINT (1 3)", 2},
+		{"CIRCULARSTRING (0 0, 1 0.00000000001, 2 0)", "POINT (1 1)", 1},
+	}
+	for _, tt := range tests {
+		a, err := ParseWKT(tt.a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ParseWKT(tt.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, pair := range [][2]orb.Geometry{{a, b}, {b, a}} {
+			if got, err := Distance(pair[0], pair[1]); err != nil || math.Abs(got-tt.expected) > 1e-9 {
+				t.Errorf("Distance(%s, %s) = %v, %v, expected %v", tt.a, tt.b, got, err, tt.expected)
+			}
+		}
+	}
+
+	circle := Circle{Center: orb.Point{0, 0}, Radius: 1}
+	if got, err := Distance(circle, orb.Point{3, 0}); err != nil || math.Abs(got-2) > 1e-9 {
+		t.Errorf("Distance(circle, POINT (3 0)) = %v, %v, expected 2", got, err)
+	}
+	if got, err := Distance(testDiamond{c: orb.Point{0, 0}, r: 1}, orb.Point{3, 0}); err != nil || math.Abs(got-2) > 1e-9 {
+		t.Errorf("Distance(diamond, POINT (3 0)) = %v, %v, expected 2", got, err)
+	}
+	if got, err := Distance(orb.LineString{}, orb.Point{0, 0}); err != nil || !math.IsInf(got, 1) {
+		t.Errorf("Distance(LINESTRING EMPTY, POINT (0 0)) = %v, %v, expected +Inf", got, err)
+	}
+	if _, err := Distance(testUnknown{}, orb.Point{0, 0}); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("Distance(unknown, POINT (0 0)) error = %v, expected ErrUnsupportedType", err)
+	}
+}
+
+func TestTileCover(t *testing.T) {
+	tile := maptile.New(5, 9, 4)
+
+	t.Run("tile bound", func(t *testing.T) {
+		covering, interior := TileCover(tile.Bound(), 5)
+		// The four children are covered, and the twelve tiles around them touch the bound
+		if len(interior) != 4 || len(covering) != 16 {
+			t.Errorf("TileCover() = %d covering, %d interior, expected 16 and 4", len(covering), len(interior))
+		}
+		for _, child := range tile.Children() {
+			if !containsTile(interior, child) {
+				t.Errorf("interior is missing %v", child)
+			}
+		}
+	})
+
+	t.Run("point", func(t *testing.T) {
+		covering, interior := TileCover(tile.Center(), 4)
+		if len(covering) != 1 || covering[0] != tile || len(interior) != 0 {
+			t.Errorf("TileCover() = %v, %v, expected only %v covering", covering, interior, tile)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		covering, interior := TileCover(orb.Polygon{}, 4)
+		if covering != nil || interior != nil {
+			t.Errorf("TileCover() = %v, %v, expected nothing", covering, interior)
+		}
+	})
+
+	// The quadtree descent must find the same tiles as testing every tile
+	shapes := map[string]orb.Geometry{
+		"polygon": orb.Polygon{{{-20, -10}, {35, -15}, {50, 30}, {10, 45}, {-30, 20}, {-20, -10}}},
+		"line":    orb.LineString{{-100, 40}, {-80, 30}, {-60, 45}},
+		"circle":  Circle{Center: orb.Point{100, -20}, Radius: 25},
+	}
+	const z = 5
+	for name, g := range shapes {
+		t.Run(name, func(t *testing.T) {
+			covering, interior := TileCover(g, z)
+			var wantCovering, wantInterior []maptile.Tile
+			for x := uint32(0); x < 1<<z; x++ {
+				for y := uint32(0); y < 1<<z; y++ {
+					tile := maptile.New(x, y, z)
+					if Intersects(g, tile.Bound()) {
+						wantCovering = append(wantCovering, tile)
+					}
+					if Covers(g, tile.Bound()) {
+						wantInterior = append(wantInterior, tile)
+					}
+				}
+			}
+			if !sameTiles(covering, wantCovering) {
+				t.Errorf("covering = %v, expected %v", covering, wantCovering)
+			}
+			if !sameTiles(interior, wantInterior) {
+				t.Errorf("interior = %v, expected %v", interior, wantInterior)
+			}
+		})
+	}
+}
+
+func containsTile(tiles []maptile.Tile, tile maptile.Tile) bool {
+	for _, t := range tiles {
+		if t == tile {
+			return true
+		}
+	}
+	return false
+}
+
+func sameTiles(a, b []maptile.Tile) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, t := range a {
+		if !containsTile(b, t) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestWKBPredicates(t *testing.T) {
+	wkbPredicates := map[string]func(a, b []byte) (bool, error){
+		"intersects":       IntersectsWKB,
+		"contains":         ContainsWKB,
+		"within":           WithinWKB,
+		"covers":           CoversWKB,
+		"coveredby":        CoveredByWKB,
+		"crosses":          CrossesWKB,
+		"overlaps":         OverlapsWKB,
+		"touches":          TouchesWKB,
+		"disjoint":         DisjointWKB,
+		"containsproperly": ContainsProperlyWKB,
+	}
+	geoms := []orb.Geometry{
+		unitSquare, smallSquare, disjointSquare, touchingSquare, donut,
+		pointInside, pointOnEdge, pointOutside, lineCrossing, lineOnEdge,
+		orb.MultiPoint{pointInside, pointOutside},
+		orb.MultiLineString{lineInside, lineOutside},
+		multiPolygon,
+		orb.Collection{pointOutside, smallSquare},
+		orb.Point{math.NaN(), math.NaN()},
+		orb.Polygon{},
+	}
+
+	// The WKB predicates answer like the predicates on the decoded geometries
+	for name, pred := range wkbPredicates {
+		for _, a := range geoms {
+			for _, b := range geoms {
+				got, err := pred(ewkb.MustMarshal(a, 4326), ewkb.MustMarshal(b, 4326, binary.BigEndian))
+				if err != nil {
+					t.Fatalf("%sWKB(%v, %v) error: %v", name, a, b, err)
+				}
+				if expected := supportedPredicates[name](a, b); got != expected {
+					t.Errorf("%sWKB(%v, %v) = %v, expected %v", name, a, b, got, expected)
+				}
+			}
+		}
+	}
+}
+
+// wkbBytes writes little endian WKB from a type code and float64 or uint32 values
+func wkbBytes(typ uint32, values ...interface{}) []byte {
+	data := binary.LittleEndian.AppendUint32([]byte{1}, typ)
+	for _, v := range values {
+		switch v := v.(type) {
+		case uint32:
+			data = binary.LittleEndian.AppendUint32(data, v)
+		case float64:
+			data = binary.LittleEndian.AppendUint64(data, math.Float64bits(v))
+		case []byte:
+			data = append(data, v...)
+		}
+	}
+	return data
+}
+
+func TestWKBDecode(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected orb.Geometry
+		srid     int
+	}{
+		{"WKB", wkb.MustMarshal(donut), donut, 0},
+		{"EWKB", ewkb.MustMarshal(multiPolygon, 3857, binary.BigEndian), multiPolygon, 3857},
+		{"EWKB point Z", wkbBytes(0xA0000001, uint32(4326), 1.0, 2.0, 3.0), orb.Point{1, 2}, 4326},
+		{"EWKB line ZM", wkbBytes(0xC0000002, uint32(2), 1.0, 2.0, 3.0, 4.0, 5.0, 6.0, 7.0, 8.0), orb.LineString{{1, 2}, {5, 6}}, 0},
+		{"ISO line M", wkbBytes(2002, uint32(2), 1.0, 2.0, 3.0, 5.0, 6.0, 7.0), orb.LineString{{1, 2}, {5, 6}}, 0},
+		{"ISO multipoint ZM", wkbBytes(3004, uint32(1), wkbBytes(3001, 1.0, 2.0, 3.0, 4.0)), orb.MultiPoint{{1, 2}}, 0},
+		{"collection", wkbBytes(7, uint32(2), wkbBytes(1, 1.0, 2.0), wkbBytes(1002, uint32(0))), orb.Collection{orb.Point{1, 2}, orb.LineString{}}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, srid, err := DecodeWKB(tt.data)
+			if err != nil || srid != tt.srid || !orb.Equal(g, tt.expected) {
+				t.Errorf("DecodeWKB() = %v, %d, %v, expected %v, %d", g, srid, err, tt.expected, tt.srid)
+			}
+			env, srid, err := WKBEnvelope(tt.data)
+			if err != nil || srid != tt.srid || env != Envelope(tt.expected) {
+				t.Errorf("WKBEnvelope() = %v, %d, %v, expected %v", env, srid, err, Envelope(tt.expected))
+			}
+		})
+	}
+
+	// POINT EMPTY is written with NaN coordinates
+	if g, _, err := DecodeWKB(wkbBytes(1, math.NaN(), math.NaN())); err != nil || !IsEmpty(g) {
+		t.Errorf("DecodeWKB(POINT EMPTY) = %v, %v, expected an empty point", g, err)
+	}
+}
+
+func TestWKBErrors(t *testing.T) {
+	square := wkb.MustMarshal(unitSquare)
+	tests := []struct {
+		name     string
+		a, b     []byte
+		expected error
+	}{
+		{"SRID mismatch", ewkb.MustMarshal(unitSquare, 4326), ewkb.MustMarshal(smallSquare, 3857), ErrSRIDMismatch},
+		{"SRID and none", ewkb.MustMarshal(unitSquare, 4326), square, ErrSRIDMismatch},
+		{"empty data", nil, square, ErrInvalidWKB},
+		{"bad byte order", append([]byte{2}, square[1:]...), square, ErrInvalidWKB},
+		{"truncated", square, square[:len(square)-3], ErrInvalidWKB},
+		{"trailing bytes", square, append(square[:len(square):len(square)], 0), ErrInvalidWKB},
+		{"huge count", wkbBytes(2, uint32(1<<30)), square, ErrInvalidWKB},
+		{"wrong part type", wkbBytes(4, uint32(1), wkbBytes(2, uint32(0))), square, ErrInvalidWKB},
+		{"curve", wkbBytes(8, uint32(0)), square, ErrUnsupportedType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := IntersectsWKB(tt.a, tt.b); !errors.Is(err, tt.expected) {
+				t.Errorf("IntersectsWKB() error = %v, expected %v", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWKBEnvelopeDoesNotAllocate(t *testing.T) {
+	far := wkb.MustMarshal(generateCircularPolygon(100, 100, 5, 64))
+	near := wkb.MustMarshal(donut)
+	tests := map[string]func(){
+		"WKBEnvelope":   func() { WKBEnvelope(near) },
+		"IntersectsWKB": func() { IntersectsWKB(near, far) },
+		"WithinWKB":     func() { WithinWKB(far, near) },
+	}
+	for name, f := range tests {
+		if allocs := testing.AllocsPerRun(100, f); allocs != 0 {
+			t.Errorf("%s allocates %v times per call", name, allocs)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	geoms := []orb.Geometry{
+		unitSquare, smallSquare, overlappingSquare, disjointSquare, touchingSquare, cShape, donut,
+		pointInside, pointOutside, pointOnEdge, pointOnCorner,
+		lineInside, lineCrossing, lineOutside, lineTouching, lineOnEdge,
+		multiPointAllInside, multiPointSomeInside, ringInside, ringOverlapping,
+		multiLineString, multiPolygon, testBound, testCollection,
+		Circle{Center: orb.Point{5, 5}, Radius: 3},
+		orb.Bound{Min: orb.Point{0, 5}, Max: orb.Point{10, 5}},
+		orb.Point{math.NaN(), math.NaN()},
+	}
+
+	// Every set, including the single predicates, answers like the predicates
+	sets := []PredicateSet{AllPredicates, PredIntersects | PredTouches | PredWithin | PredOverlaps}
+	for i := range predicateNames {
+		sets = append(sets, 1<<i)
+	}
+	for _, a := range geoms {
+		for _, b := range geoms {
+			for _, set := range sets {
+				r := Evaluate(a, b, set)
+				for i, name := range predicateNames {
+					p := PredicateSet(1 << i)
+					expected := set&p != 0 && supportedPredicates[name](a, b)
+					if r.Has(p) != expected {
+						t.Errorf("Evaluate(%v, %v, %v) = %v, expected %s %v", a, b, set, r, name, expected)
+					}
+				}
+			}
+		}
+	}
+
+	if s := (PredWithin | PredTouches).String(); s != "within|touches" {
+		t.Errorf("String() = %q, expected within|touches", s)
+	}
+	if r := Evaluate(pointInside, unitSquare, AllPredicates); r.String() != "intersects|within|coveredby" {
+		t.Errorf("Evaluate(point in square) = %v, expected intersects|within|coveredby", r)
+	}
+}
+
+// propertyGeometries returns the geometries the property tests relate with
+// each other
+func propertyGeometries() []orb.Geometry {
+	geoms := []orb.Geometry{
+		unitSquare, smallSquare, overlappingSquare, disjointSquare, touchingSquare, cShape, donut,
+		pointInside, pointOnEdge, pointOnCorner,
+		lineInside, lineCrossing, lineTouching, lineOnEdge,
+		multiPointSomeInside, ringOverlapping, multiLineString, multiPolygon, testBound, testCollection,
+		Circle{Center: orb.Point{5, 5}, Radius: 3},
+	}
+
+	// Shapes on a small grid, which share edges and vertices with each other
+	// and the shapes above much of the time
+	r := rand.New(rand.NewPCG(1, 2))
+	coord := func() float64 { return float64(r.IntN(6) * 2) }
+	for range 25 {
+		x, y, w, h := coord(), coord(), coord()+2, coord()+2
+		geoms = append(geoms,
+			orb.Polygon{{{x, y}, {x + w, y}, {x + w, y + h}, {x, y + h}, {x, y}}},
+			orb.Polygon{{{x, y}, {x + w, y}, {x, y + h}, {x, y}}},
+			orb.LineString{{x, y}, {coord(), coord()}, {coord(), coord()}},
+			orb.MultiPoint{{x, y}, {coord(), coord()}},
+		)
+	}
+	for range 10 {
+		x, y, w, h := coord(), coord(), coord()+2, coord()+2
+		geoms = append(geoms,
+			orb.Point{x, y},
+			orb.Point{coord() + 1, coord() + 1},
+			orb.Bound{Min: orb.Point{x, y}, Max: orb.Point{x + w, y + h}},
+			orb.Ring{{x, y}, {x + w, y}, {x, y + h}, {x, y}},
+			orb.MultiPolygon{
+				{{{x, y}, {x + w, y}, {x + w, y + h}, {x, y + h}, {x, y}}, {{x + 1, y + 1}, {x + w - 1, y + 1}, {x + 1, y + h - 1}, {x + 1, y + 1}}},
+				{{{x + w, y + h}, {x + w + 2, y + h}, {x + w, y + h + 2}, {x + w, y + h}}},
+			},
+			Circle{Center: orb.Point{x, y}, Radius: w},
+		)
+	}
+	return geoms
+}
+
+func TestPredicateProperties(t *testing.T) {
+	geoms := propertyGeometries()
+	for _, a := range geoms {
+		for _, b := range geoms {
+			m, mt := Relate(a, b), Relate(b, a)
+			for i := range 3 {
+				for j := range 3 {
+					if m[i*3+j] != mt[j*3+i] {
+						t.Fatalf("Relate(%v, %v) = %s is not the transpose of %s", a, b, m, mt)
+					}
+				}
+			}
+
+			intersects := Intersects(a, b)
+			for _, c := range []struct {
+				name string
+				ok   bool
+			}{
+				{"Within(a, b) == Contains(b, a)", Within(a, b) == Contains(b, a)},
+				{"CoveredBy(a, b) == Covers(b, a)", CoveredBy(a, b) == Covers(b, a)},
+				{"Disjoint(a, b) == !Intersects(a, b)", Disjoint(a, b) == !intersects},
+				{"Intersects is symmetric", intersects == Intersects(b, a)},
+				{"Touches is symmetric", Touches(a, b) == Touches(b, a)},
+				{"Touches implies Intersects", !Touches(a, b) || intersects},
+				{"Covers implies Intersects", !Covers(a, b) || intersects},
+				{"Within implies CoveredBy", !Within(a, b) || CoveredBy(a, b)},
+				{"ContainsProperly implies Contains", !ContainsProperly(a, b) || Contains(a, b)},
+			} {
+				if !c.ok {
+					t.Errorf("%s fails for a = %v, b = %v (%s)", c.name, a, b, m)
+				}
+			}
+
+			// The typed predicates agree with the generic ones
+			for _, c := range typedPredicates(a, b) {
+				if c.got != c.want {
+					t.Errorf("%s(%v, %v) = %v, the generic predicate gives %v", c.name, a, b, c.got, c.want)
+				}
+			}
+		}
+	}
+}
+
+func TestQuickPaths(t *testing.T) {
+	// Polygons that share an edge, with points on it, on their corner and
+	// inside, boxes within epsilon of touching, and lines that cross at and
+	// away from their vertices
+	adjacent := orb.MultiPolygon{
+		{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}},
+		{{{2, 0}, {4, 0}, {4, 2}, {2, 2}, {2, 0}}},
+	}
+	geoms := append(propertyGeometries(),
+		adjacent,
+		orb.Point{2, 1}, orb.Point{2, 2}, orb.MultiPoint{{2, 1}, {1, 1}},
+		orb.Bound{Min: orb.Point{4 + epsilon/2, 0}, Max: orb.Point{6, 2}},
+		orb.Collection{orb.Point{5, 5}, orb.LineString{{4, 4}, {6, 6}}, smallSquare},
+		orb.LineString{{0, 0}, {1, 3}, {2, 0}, {3, 3}, {4, 0}},
+		orb.LineString{{0, 2}, {4, 2}},
+		orb.LineString{{0, 3}, {1, 3 + epsilon/2}, {4, 3}},
+		orb.LineString{{1, 1}, {1, 3}, {3, 3}, {3, 1}, {1, 1}},
+	)
+
+	// The answers without the relate engine's graph are those it gives
+	sets := []PredicateSet{PredIntersects, PredDisjoint, PredCovers, PredCoveredBy, PredIntersects | PredCovers, AllPredicates}
+	for _, a := range geoms {
+		for _, b := range geoms {
+			if IsEmpty(a) || IsEmpty(b) {
+				continue
+			}
+			na, nb := normalize(a), normalize(b)
+			ea, eb := Envelope(na), Envelope(nb)
+			dimA, dimB := normalizedDimension(na), normalizedDimension(nb)
+			im := relateGraph(na, nb, ea, eb, Mod2Rule, 0, dimA, dimB)
+			if got := Relate(a, b); got != im.String() {
+				t.Errorf("Relate(%v, %v) = %s, the graph gives %s", a, b, got, im)
+			}
+			for _, set := range sets {
+				expected := im.result(set, dimA, dimB)
+				if got := Evaluate(a, b, set); got != expected {
+					t.Errorf("Evaluate(%v, %v, %v) = %v, the graph gives %v", a, b, set, got, expected)
+				}
+			}
+		}
+	}
+}
+
+// typedCase is a typed predicate's result and the generic predicate's
+type typedCase struct {
+	name      string
+	got, want bool
+}
+
+// typedPredicates evaluates the typed predicates that take a and b
+func typedPredicates(a, b orb.Geometry) []typedCase {
+	switch a := a.(type) {
+	case orb.Point:
+		switch b := b.(type) {
+		case orb.Polygon:
+			return []typedCase{
+				{"PointInPolygon", PointInPolygon(a, b), Within(a, b)},
+				{"PolygonCoversPoint", PolygonCoversPoint(b, a), Covers(b, a)},
+			}
+		case orb.Ring:
+			return []typedCase{{"PointInRing", PointInRing(a, b), Within(a, b)}}
+		case orb.MultiPolygon:
+			return []typedCase{{"PointInMultiPolygon", PointInMultiPolygon(a, b), Within(a, b)}}
+		case orb.Bound:
+			return []typedCase{
+				{"PointInBound", PointInBound(a, b), Within(a, b)},
+				{"BoundCoversPoint", BoundCoversPoint(b, a), Covers(b, a)},
+			}
+		case Circle:
+			return []typedCase{
+				{"PointInCircle", PointInCircle(a, b), Within(a, b)},
+				{"CircleCoversPoint", CircleCoversPoint(b, a), Covers(b, a)},
+			}
+		}
+	case orb.LineString:
+		switch b := b.(type) {
+		case orb.LineString:
+			return []typedCase{{"LineStringIntersectsLineString", LineStringIntersectsLineString(a, b), Intersects(a, b)}}
+		case orb.Polygon:
+			return []typedCase{{"LineStringIntersectsPolygon", LineStringIntersectsPolygon(a, b), Intersects(a, b)}}
+		}
+	case orb.Polygon:
+		if b, ok := b.(orb.Polygon); ok {
+			return []typedCase{{"PolygonIntersectsPolygon", PolygonIntersectsPolygon(a, b), Intersects(a, b)}}
+		}
+	case orb.Bound:
+		switch b := b.(type) {
+		case orb.Bound:
+			return []typedCase{{"BoundIntersectsBound", BoundIntersectsBound(a, b), Intersects(a, b)}}
+		case orb.LineString:
+			return []typedCase{{"BoundIntersectsLineString", BoundIntersectsLineString(a, b), Intersects(a, b)}}
+		case orb.Polygon:
+			return []typedCase{{"BoundIntersectsPolygon", BoundIntersectsPolygon(a, b), Intersects(a, b)}}
+		}
+	}
+	return nil
+}
+
+func TestClassify(t *testing.T) {
+	cornerSquare := orb.Polygon{{{0, 0}, {5, 0}, {5, 5}, {0, 5}, {0, 0}}}
+	tests := []struct {
+		name     string
+		a, b     orb.Geometry
+		expected Relation
+	}{
+		{"disjoint squares", unitSquare, disjointSquare, RelDisjoint},
+		{"squares sharing an edge", unitSquare, touchingSquare, RelMeet},
+		{"overlapping squares", unitSquare, overlappingSquare, RelOverlap},
+		{"equal squares", unitSquare, orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{10, 10}}, RelEqual},
+		{"square inside square", smallSquare, unitSquare, RelInside},
+		{"square contains square", unitSquare, smallSquare, RelContains},
+		{"square in a corner of square", cornerSquare, unitSquare, RelCoveredBy},
+		{"square covers square in its corner", unitSquare, cornerSquare, RelCovers},
+		{"point inside square", pointInside, unitSquare, RelInside},
+		{"point on edge of square", pointOnEdge, unitSquare, RelMeet},
+		{"line inside square", lineInside, unitSquare, RelInside},
+		{"line from edge into square", orb.LineString{{0, 5}, {5, 5}}, unitSquare, RelCoveredBy},
+		{"line along edge of square", lineOnEdge, unitSquare, RelMeet},
+		{"line crossing square", lineCrossing, unitSquare, RelCross},
+		{"lines crossing", orb.LineString{{0, 0}, {2, 2}}, orb.LineString{{0, 2}, {2, 0}}, RelCross},
+		{"lines overlapping", orb.LineString{{0, 0}, {2, 0}}, orb.LineString{{1, 0}, {3, 0}}, RelOverlap},
+		{"point inside line", orb.Point{1, 0}, orb.LineString{{0, 0}, {2, 0}}, RelInside},
+		{"point at end of line", orb.Point{0, 0}, orb.LineString{{0, 0}, {2, 0}}, RelMeet},
+		{"point on closed line", orb.Point{0, 0}, orb.LineString{{0, 0}, {2, 0}, {2, 2}, {0, 0}}, RelInside},
+		{"line inside line", orb.LineString{{1, 0}, {2, 0}}, orb.LineString{{0, 0}, {3, 0}}, RelInside},
+		{"line along line to its end", orb.LineString{{1, 0}, {3, 0}}, orb.LineString{{0, 0}, {3, 0}}, RelCoveredBy},
+		// Which boundaries meet is not told apart
+		{"lines sharing an end", orb.LineString{{0, 0}, {2, 0}}, orb.LineString{{2, 0}, {2, 2}}, RelMeet},
+		{"line ending on line", orb.LineString{{1, 0}, {1, 2}}, orb.LineString{{0, 0}, {2, 0}}, RelMeet},
+		{"points meeting one of several", orb.MultiPoint{{0, 0}, {5, 5}}, orb.LineString{{0, 0}, {2, 0}}, RelMeet},
+		{"points crossing line", orb.MultiPoint{{1, 0}, {5, 5}}, orb.LineString{{0, 0}, {2, 0}}, RelCross},
+		{"equal points", pointInside, pointInside, RelEqual},
+		{"empty", orb.Polygon{}, unitSquare, RelDisjoint},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.a, tt.b); got != tt.expected {
+				t.Errorf("Classify = %v, expected %v", got, tt.expected)
+			}
+			if got := Classify(tt.b, tt.a); got != tt.expected.Converse() {
+				t.Errorf("Classify reversed = %v, expected %v", got, tt.expected.Converse())
+			}
+		})
+	}
+
+	// Every pair is classified as the predicates say
+	geoms := []orb.Geometry{
+		unitSquare, smallSquare, overlappingSquare, disjointSquare, touchingSquare, cShape, donut,
+		pointInside, pointOutside, pointOnEdge, pointOnCorner,
+		lineInside, lineCrossing, lineOutside, lineTouching, lineOnEdge,
+		multiPointAllInside, multiPointSomeInside, ringInside, ringOverlapping,
+		multiLineString, multiPolygon, testBound, testCollection,
+		Circle{Center: orb.Point{5, 5}, Radius: 3},
+	}
+	for _, a := range geoms {
+		for _, b := range geoms {
+			r := Classify(a, b)
+			within, contains := Within(a, b), Contains(a, b)
+			for _, c := range []struct {
+				rel Relation
+				ok  bool
+			}{
+				{RelDisjoint, Disjoint(a, b)},
+				{RelMeet, Touches(a, b)},
+				{RelEqual, within && contains},
+				{RelInside, ContainsProperly(b, a) && !contains},
+				{RelCoveredBy, within && !contains && !ContainsProperly(b, a)},
+				{RelContains, ContainsProperly(a, b) && !within},
+				{RelCovers, contains && !within && !ContainsProperly(a, b)},
+				{RelCross, Crosses(a, b)},
+			} {
+				if (r == c.rel) != c.ok {
+					t.Errorf("Classify(%v, %v) = %v, but the predicates give %v: %v", a, b, r, c.rel, c.ok)
+				}
+			}
+			if Overlaps(a, b) && r != RelOverlap {
+				t.Errorf("Classify(%v, %v) = %v, expected overlap", a, b, r)
+			}
+		}
+	}
+}
+
+func TestDirection(t *testing.T) {
+	north := orb.Polygon{{{2, 12}, {8, 12}, {8, 14}, {2, 14}, {2, 12}}}
+	northTouching := orb.Polygon{{{2, 10}, {8, 10}, {8, 14}, {2, 14}, {2, 10}}}
+	tests := []struct {
+		name     string
+		a        orb.Geometry
+		expected Direction
+	}{
+		{"inside", smallSquare, DirSame},
+		{"north", north, DirN},
+		{"north touching the envelope", northTouching, DirN},
+		{"point on the northern edge", orb.Point{5, 10}, DirN | DirSame},
+		{"point on the north east corner", orb.Point{10, 10}, DirN | DirNE | DirSame | DirE},
+		{"line along the northern edge", orb.LineString{{2, 10}, {8, 10}}, DirN | DirSame},
+		{"line crossing", lineCrossing, DirW | DirSame | DirE},
+		{"overlapping", overlappingSquare, DirSame | DirE | DirN | DirNE},
+		{"point outside", pointOutside, DirNE},
+		{"collection", orb.Collection{orb.Point{-5, -5}, north}, DirSW | DirN},
+		{"circle", Circle{Center: orb.Point{5, -5}, Radius: 2}, DirS},
+		{"empty", orb.Polygon{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DirectionMatrix(tt.a, unitSquare); got != tt.expected {
+				t.Errorf("DirectionMatrix = %v, expected %v", got, tt.expected)
+			}
+			if got := NorthOf(tt.a, unitSquare); got != (tt.expected != 0 && tt.expected&^DirNorthward == 0) {
+				t.Errorf("NorthOf = %v with matrix %v", got, tt.expected)
+			}
+		})
+	}
+
+	// References of no width or height have line and point tiles
+	origin := orb.Point{0, 0}
+	horizontal := orb.LineString{{0, 0}, {10, 0}}
+	vertical := orb.LineString{{0, 0}, {0, 10}}
+	for _, tt := range []struct {
+		name     string
+		a, b     orb.Geometry
+		expected Direction
+	}{
+		{"point north of point", orb.Point{0, 5}, origin, DirN},
+		{"point east of point", orb.Point{3, 0}, origin, DirE},
+		{"equal points", origin, origin, DirSame},
+		{"line crossing north of point", orb.LineString{{-1, 5}, {1, 5}}, origin, DirNW | DirN | DirNE},
+		{"line ending north of point", orb.LineString{{-1, 5}, {0, 5}}, origin, DirNW},
+		{"line through point", orb.LineString{{0, -1}, {0, 1}}, origin, DirN | DirSame | DirS},
+		{"square around point", orb.Polygon{{{-1, -1}, {1, -1}, {1, 1}, {-1, 1}, {-1, -1}}}, origin, DirWestward | DirN | DirSame | DirS | DirEastward},
+		{"square with an edge north of point", orb.Polygon{{{0, 1}, {2, 1}, {2, 2}, {0, 2}, {0, 1}}}, origin, DirNE},
+		{"point on line", orb.Point{5, 0}, horizontal, DirSame},
+		{"point beyond end of line", orb.Point{12, 0}, horizontal, DirE},
+		{"point north of line", orb.Point{5, 3}, horizontal, DirN},
+		{"point at end of line", origin, horizontal, DirW | DirSame},
+		{"line crossing north of line", orb.LineString{{-1, 12}, {1, 12}}, vertical, DirNW | DirN | DirNE},
+		{"line along line", orb.LineString{{-5, 0}, {5, 0}}, horizontal, DirW | DirSame},
+	} {
+		if got := DirectionMatrix(tt.a, tt.b); got != tt.expected {
+			t.Errorf("DirectionMatrix %s = %v, expected %v", tt.name, got, tt.expected)
+		}
+	}
+
+	if !SouthOf(lineTouching, orb.Point{-5, 5}) || !EastOf(disjointSquare, unitSquare) || !WestOf(unitSquare, disjointSquare) {
+		t.Error("SouthOf, EastOf or WestOf failed")
+	}
+	if EastOf(overlappingSquare, unitSquare) || SouthOf(pointOnEdge, unitSquare) {
+		t.Error("EastOf or SouthOf held for a geometry not strictly on that side")
+	}
+	if m := (DirN | DirSE).Matrix(); m != [3][3]bool{{false, true, false}, {false, false, false}, {false, false, true}} {
+		t.Errorf("Matrix() = %v", m)
+	}
+
+	for _, tt := range []struct {
+		a        orb.Geometry
+		expected Direction
+	}{
+		{orb.Point{5, 5}, DirSame},
+		{orb.Point{5, 20}, DirN},
+		{orb.Point{20, 20}, DirNE},
+		{orb.Point{20, 6}, DirE},
+		{orb.Point{-5, 5}, DirW},
+		{orb.Point{0, -10}, DirS},
+		{north, DirN},
+		{orb.Point{5 + 10*math.Cos(math.Pi/8), 5 + 10*math.Sin(math.Pi/8)}, DirE},
+	} {
+		if got := ConeDirection(tt.a, unitSquare); got != tt.expected {
+			t.Errorf("ConeDirection(%v) = %v, expected %v", tt.a, got, tt.expected)
+		}
+	}
+}
+
+func TestParseWKT(t *testing.T) {
+	tests := []struct {
+		wkt  string
+		want orb.Geometry
+	}{
+		{"POINT (1 2)", orb.Point{1, 2}},
+		{"POINT Z (1 2 3)", orb.Point{1, 2}},
+		{"LINEARRING (0 0, 1 0, 1 1, 0 0)", orb.LineString{{0, 0}, {1, 0}, {1, 1}, {0, 0}}},
+		{"LINESTRING EMPTY", orb.LineString{}},
+		{"POLYGON EMPTY", orb.Polygon{}},
+		{"MULTIPOINT (1 2, 3 4)", orb.MultiPoint{{1, 2}, {3, 4}}},
+		{"MULTIPOINT ((1 2), (3 4))", orb.MultiPoint{{1, 2}, {3, 4}}},
+		{"MULTILINESTRING (EMPTY, (0 0, 1 1))", orb.MultiLineString{{}, {{0, 0}, {1, 1}}}},
+		{"MULTIPOLYGON (EMPTY, ((0 0, 1 0, 1 1, 0 0)))", orb.MultiPolygon{{}, {{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}}},
+		{"GEOMETRYCOLLECTION EMPTY", orb.Collection{}},
+		{"GEOMETRYCOLLECTION (POINT (1 1),\n  LINESTRING (0 0, 1 1))", orb.Collection{orb.Point{1, 1}, orb.LineString{{0, 0}, {1, 1}}}},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseWKT(tt.wkt)
+		if err != nil {
+			t.Errorf("ParseWKT(%q) error: %v", tt.wkt, err)
+			continue
+		}
+		if !orb.Equal(got, tt.want) {
+			t.Errorf("ParseWKT(%q) = %#v, want %#v", tt.wkt, got, tt.want)
+		}
+	}
+
+	// Empty points have no ordinates
+	for _, s := range []string{"POINT EMPTY", "MULTIPOINT (EMPTY, (1 1))", "GEOMETRYCOLLECTION (POINT EMPTY)"} {
+		g, err := ParseWKT(s)
+		if err != nil {
+			t.Errorf("ParseWKT(%q) error: %v", s, err)
+			continue
+		}
+		var points orb.MultiPoint
+		switch g := g.(type) {
+		case orb.Point:
+			points = orb.MultiPoint{g}
+		case orb.MultiPoint:
+			points = g[:1]
+		case orb.Collection:
+			points = orb.MultiPoint{g[0].(orb.Point)}
+		}
+		if len(points) != 1 || !math.IsNaN(points[0][0]) {
+			t.Errorf("ParseWKT(%q) = %#v, want an empty point first", s, g)
+		}
+	}
+
+	// orb.Equal does not know the curved types
+	g, err := ParseWKT("CIRCULARSTRING (0 0, 1 1, 2 0)")
+	if cs, ok := g.(CircularString); err != nil || !ok || !orb.Equal(orb.LineString(cs.Points), orb.LineString{{0, 0}, {1, 1}, {2, 0}}) {
+		t.Errorf("ParseWKT(CIRCULARSTRING) = %#v, %v", g, err)
+	}
+	if g, err := ParseWKT("CIRCULARSTRING EMPTY"); err != nil || !IsEmpty(g) {
+		t.Errorf("ParseWKT(CIRCULARSTRING EMPTY) = %#v, %v", g, err)
+	}
+
+	for _, s := range []string{"", "POINT (1)", "CIRCLE (1 1)", "POINT (1 1) x", "CIRCULARSTRING (0 0, 1 1)",
+		"POLYGON ((0 0, 1 0, 1 1))", "POLYGON ((0 0, 1 0, 0 0))", "LINEARRING (0 0, 1 0, 1 1, 0 1)"} {
+		if _, err := ParseWKT(s); err == nil {
+			t.Errorf("ParseWKT(%q) expected an error", s)
+		}
+	}
+}
+
+func TestFormatWKT(t *testing.T) {
+	tests := []struct {
+		g        orb.Geometry
+		expected string
+	}{
+		{orb.Point{1, 2}, "POINT (1 2)"},
+		{orb.Point{math.NaN(), math.NaN()}, "POINT EMPTY"},
+		{orb.Point{0.30000000000000004, 1e-300}, "POINT (0.30000000000000004 1e-300)"},
+		{orb.MultiPoint{{1, 2}, {math.NaN(), math.NaN()}}, "MULTIPOINT ((1 2), EMPTY)"},
+		{orb.LineString{}, "LINESTRING EMPTY"},
+		{orb.MultiLineString{{{0, 0}, {1, 1}}, {}}, "MULTILINESTRING ((0 0, 1 1), EMPTY)"},
+		{orb.Ring{{0, 0}, {1, 0}, {1, 1}, {0, 0}}, "POLYGON ((0 0, 1 0, 1 1, 0 0))"},
+		{orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 2}}, "POLYGON ((0 0, 1 0, 1 2, 0 2, 0 0))"},
+		{orb.MultiPolygon{}, "MULTIPOLYGON EMPTY"},
+		{CircularString{Points: []orb.Point{{0, 0}, {1, 1}, {2, 0}}}, "CIRCULARSTRING (0 0, 1 1, 2 0)"},
+		{orb.Collection{orb.Point{1, 1}, orb.Polygon{}}, "GEOMETRYCOLLECTION (POINT (1 1), POLYGON EMPTY)"},
+	}
+
+	for _, tt := range tests {
+		got, err := FormatWKT(tt.g)
+		if err != nil || got != tt.expected {
+			t.Errorf("FormatWKT(%#v) = %q, %v, expected %q", tt.g, got, err, tt.expected)
+			continue
+		}
+		if _, err := ParseWKT(got); err != nil {
+			t.Errorf("ParseWKT(%q) error: %v", got, err)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package predicates
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sync"
+
+	"github.com/paulmach/orb"
+)
+
+// ErrUnsupportedType is returned by CheckGeometry, Check and
+// Evaluator.Evaluate for geometry types the predicates do not know how to
+// evaluate. The boolean predicates, which cannot return it, treat such
+// geometries as empty, so Disjoint holds for them.
+var ErrUnsupportedType = errors.New("predicates: unsupported geometry type")
+
+// Location is where a point lies relative to a geometry
+type Location int
+
+const (
+	// Exterior is outside the geometry
+	Exterior Location = iota
+	// OnBoundary is on the boundary of the geometry
+	OnBoundary
+	// Interior is inside the geometry
+	Interior
+)
+
+// String returns the name of the location
+func (l Location) String() string {
+	switch l {
+	case Exterior:
+		return "Exterior"
+	case OnBoundary:
+		return "Boundary"
+	case Interior:
+		return "Interior"
+	}
+	return "Location(?)"
+}
+
+// Relater is implemented by custom areal geometry types, such as circles or
+// corridors, so that every predicate can evaluate them. The relate engine
+// splits the other geometry where it meets the Boundary and looks up each
+// piece with Locate, so Locate must agree with Boundary exactly: a point on
+// the boundary lines is OnBoundary, and the points on one side of them are
+// Interior and on the other side Exterior.
+//
+// Bound and Dimensions come from orb.Geometry; Dimensions must return 2.
+// orb.Geometry has an unexported method, so a custom type embeds an orb
+// geometry, or the orb.Geometry interface itself, and overrides Bound and
+// Dimensions. Custom lines and points, and domain types that wrap orb
+// geometries, are registered with RegisterType instead.
+type Relater interface {
+	orb.Geometry
+
+	// Boundary returns the outline of the area as closed LineStrings, a
+	// MultiLineString, or the rings of a Polygon
+	Boundary() orb.Geometry
+
+	// Locate returns the location of p in the area
+	Locate(p orb.Point) Location
+}
+
+var registry = struct {
+	sync.RWMutex
+	adapters map[reflect.Type]func(orb.Geometry) orb.Geometry
+}{adapters: make(map[reflect.Type]func(orb.Geometry) orb.Geometry)}
+
+// RegisterType makes values of type T usable with every predicate. The
+// adapter returns the orb geometry a value stands for, or a Relater for
+// shapes orb cannot represent. A later registration for the same type
+// replaces the earlier one. RegisterType panics if T is an interface, one of
+// the orb geometry types, Circle or CircularString.
+//
+//	predicates.RegisterType(func(p Parcel) orb.Geometry { return p.Shape })
+//	predicates.Within(site, parcel)
+func RegisterType[T orb.Geometry](adapter func(T) orb.Geometry) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() == reflect.Interface {
+		panic(fmt.Sprintf("predicates: RegisterType of interface type %v", t))
+	}
+	var zero T
+	if isBuiltinType(zero) {
+		panic(fmt.Sprintf("predicates: RegisterType of built-in type %v", t))
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+	registry.adapters[t] = func(g orb.Geometry) orb.Geometry {
+		return adapter(g.(T))
+	}
+}
+
+// Resolve returns the geometry g stands for: a registered type, also inside
+// a Collection, is replaced with what its adapter returns, and any other
+// geometry is returned as it is
+func Resolve(g orb.Geometry) orb.Geometry {
+	return resolveCustom(g)
+}
+
+// isBuiltinType checks if g is one of the orb geometry types, or one of this
+// package's curved types, that the predicates handle directly
+func isBuiltinType(g orb.Geometry) bool {
+	switch g.(type) {
+	case orb.Point, orb.MultiPoint, orb.LineString, orb.MultiLineString,
+		orb.Ring, orb.Polygon, orb.MultiPolygon, orb.Collection, orb.Bound,
+		Circle, CircularString:
+		return true
+	}
+	return false
+}
+
+// adaptCustom returns the geometry a registered type stands for, with any
+// registered types inside it adapted too. It is false for orb types,
+// Relaters and unsupported types.
+func adaptCustom(g orb.Geometry) (orb.Geometry, bool) {
+	if g == nil || isBuiltinType(g) {
+		return nil, false
+	}
+	if _, ok := g.(Relater); ok {
+		return nil, false
+	}
+
+	registry.RLock()
+	adapter, ok := registry.adapters[reflect.TypeOf(g)]
+	registry.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	return resolveCustom(adapter(g)), true
+}
+
+// resolveCustom replaces registered types, including those inside
+// Collections, with the geometry they stand for
+func resolveCustom(g orb.Geometry) orb.Geometry {
+	switch geom := g.(type) {
+	case orb.Point, orb.MultiPoint, orb.LineString, orb.MultiLineString,
+		orb.Ring, orb.Polygon, orb.MultiPolygon, orb.Bound, Circle, CircularString:
+		return g
+	case orb.Collection:
+		if !hasCustomParts(geom) {
+			return g
+		}
+		parts := make(orb.Collection, len(geom))
+		for i, part := range geom {
+			parts[i] = resolveCustom(part)
+		}
+		return parts
+	}
+	if adapted, ok := adaptCustom(g); ok {
+		return adapted
+	}
+	return g
+}
+
+// hasCustomParts checks if a Collection has parts that are not built-in types
+func hasCustomParts(c orb.Collection) bool {
+	for _, part := range c {
+		if sub, ok := part.(orb.Collection); ok {
+			if hasCustomParts(sub) {
+				return true
+			}
+		} else if !isBuiltinType(part) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckGeometry returns an error wrapping ErrUnsupportedType if g, or any
+// part of a Collection, is neither an orb geometry, a Circle or
+// CircularString, a Relater nor a registered type. A Relater that is not an area is also reported.
+func CheckGeometry(g orb.Geometry) error {
+	switch geom := resolveCustom(g).(type) {
+	case nil:
+		return fmt.Errorf("%w: nil", ErrUnsupportedType)
+	case orb.Collection:
+		for _, part := range geom {
+			if err := CheckGeometry(part); err != nil {
+				return err
+			}
+		}
+		return nil
+	case Relater:
+		if d := geom.Dimensions(); d != 2 {
+			return fmt.Errorf("%w: %T has dimension %d, custom geometries must be areas",
+				ErrUnsupportedType, g, d)
+		}
+		return nil
+	default:
+		if !isBuiltinType(geom) {
+			return fmt.Errorf("%w: %T", ErrUnsupportedType, g)
+		}
+	}
+	return nil
+}
+
+// Check evaluates pred on a and b after checking that both geometries are
+// supported, so that an unknown type is reported rather than read as false
+//
+//	ok, err := predicates.Check(predicates.Within, a, b)
+func Check(pred func(a, b orb.Geometry) bool, a, b orb.Geometry) (bool, error) {
+	if err := CheckGeometry(a); err != nil {
+		return false, err
+	}
+	if err := CheckGeometry(b); err != nil {
+		return false, err
+	}
+	return pred(a, b), nil
+}
+
+// relaterLinework returns the boundary lines of a custom area
+func relaterLinework(r Relater) orb.MultiLineString {
+	var lines orb.MultiLineString
+	var collect func(g orb.Geometry)
+	collect = func(g orb.Geometry) {
+		switch geom := g.(type) {
+		case orb.LineString:
+			lines = append(lines, geom)
+		case orb.MultiLineString:
+			lines = append(lines, geom...)
+		case orb.Ring:
+			lines = append(lines, orb.LineString(geom))
+		case orb.Polygon:
+			lines = append(lines, ringsBoundary(geom)...)
+		case orb.MultiPolygon:
+			for _, poly := range geom {
+				lines = append(lines, ringsBoundary(poly)...)
+			}
+		case orb.Collection:
+			for _, part := range geom {
+				collect(part)
+			}
+		}
+	}
+	collect(r.Boundary())
+	return lines
+}
+
+// relaterPolygon returns the boundary lines of a custom area as the rings of
+// a polygon, for the scan line of InteriorPoint
+func relaterPolygon(r Relater) orb.Polygon {
+	var poly orb.Polygon
+	for _, ls := range relaterLinework(r) {
+		poly = append(poly, orb.Ring(ls))
+	}
+	return poly
+}
+
+// locateSides returns the location on the left and right of an edge piece
+// with midpoint mid and direction dir, which lies on the boundary of a custom
+// area. The area is probed a small step either side of the midpoint.
+func locateSides(r Relater, mid, dir orb.Point) (left, right int) {
+	length := math.Hypot(dir[0], dir[1])
+	step := math.Max(length*1e-6, 100*epsilon) / length
+	dx, dy := dir[0]*step, dir[1]*step
+
+	left, right = locExterior, locExterior
+	if r.Locate(orb.Point{mid[0] - dy, mid[1] + dx}) == Interior {
+		left = locInterior
+	}
+	if r.Locate(orb.Point{mid[0] + dy, mid[1] - dx}) == Interior {
+		right = locInterior
+	}
+	return left, right
+}
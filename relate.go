@@ -0,0 +1,1075 @@
+package predicates
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/paulmach/orb"
+
+	"github.com/tingold/orb-predicates/internal/bandindex"
+)
+
+// intersectionMatrix is a DE-9IM matrix indexed by the location in a and the
+// location in b (locInterior, locBoundary or locExterior). Each entry holds
+// the dimension of the intersection, or -1 when it is empty.
+type intersectionMatrix [3][3]int
+
+// dimFalse marks an empty intersection in an intersectionMatrix
+const dimFalse = -1
+
+// imOrder is the row and column order of the DE-9IM string form
+var imOrder = [3]int{locInterior, locBoundary, locExterior}
+
+// set raises the entry for (locA, locB) to at least dim
+func (im *intersectionMatrix) set(locA, locB, dim int) {
+	if im[locA][locB] < dim {
+		im[locA][locB] = dim
+	}
+}
+
+// String returns the matrix in the usual nine character form, e.g. "212101212"
+func (im intersectionMatrix) String() string {
+	var sb strings.Builder
+	for _, a := range imOrder {
+		for _, b := range imOrder {
+			switch d := im[a][b]; d {
+			case dimFalse:
+				sb.WriteByte('F')
+			default:
+				sb.WriteByte(byte('0' + d))
+			}
+		}
+	}
+	return sb.String()
+}
+
+// matches checks the matrix against a nine character DE-9IM pattern made of
+// 'T', 'F', '*', '0', '1' and '2'
+func (im intersectionMatrix) matches(pattern string) bool {
+	if len(pattern) != 9 {
+		return false
+	}
+	for i, c := range pattern {
+		d := im[imOrder[i/3]][imOrder[i%3]]
+		switch c {
+		case '*':
+		case 'T', 't':
+			if d == dimFalse {
+				return false
+			}
+		case 'F', 'f':
+			if d != dimFalse {
+				return false
+			}
+		case '0', '1', '2':
+			if d != int(c-'0') {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// intersects checks that the geometries share at least one point
+func (im intersectionMatrix) intersects() bool {
+	return im[locInterior][locInterior] != dimFalse ||
+		im[locInterior][locBoundary] != dimFalse ||
+		im[locBoundary][locInterior] != dimFalse ||
+		im[locBoundary][locBoundary] != dimFalse
+}
+
+// within checks the pattern T*F**F***
+func (im intersectionMatrix) within() bool {
+	return im[locInterior][locInterior] != dimFalse &&
+		im[locInterior][locExterior] == dimFalse &&
+		im[locBoundary][locExterior] == dimFalse
+}
+
+// contains checks the pattern T*****FF*
+func (im intersectionMatrix) contains() bool {
+	return im[locInterior][locInterior] != dimFalse &&
+		im[locExterior][locInterior] == dimFalse &&
+		im[locExterior][locBoundary] == dimFalse
+}
+
+// containsProperly checks the pattern T**FF*FF*
+func (im intersectionMatrix) containsProperly() bool {
+	return im.contains() &&
+		im[locBoundary][locInterior] == dimFalse &&
+		im[locBoundary][locBoundary] == dimFalse
+}
+
+// covers checks that a and b intersect and no point of b is outside a
+func (im intersectionMatrix) covers() bool {
+	return im.intersects() &&
+		im[locExterior][locInterior] == dimFalse &&
+		im[locExterior][locBoundary] == dimFalse
+}
+
+// coveredBy checks that a and b intersect and no point of a is outside b
+func (im intersectionMatrix) coveredBy() bool {
+	return im.intersects() &&
+		im[locInterior][locExterior] == dimFalse &&
+		im[locBoundary][locExterior] == dimFalse
+}
+
+// crosses checks the crosses pattern for geometries of dimension dimA and dimB
+func (im intersectionMatrix) crosses(dimA, dimB int) bool {
+	ii := im[locInterior][locInterior]
+	switch {
+	case dimA < dimB:
+		return ii != dimFalse && im[locInterior][locExterior] != dimFalse
+	case dimA > dimB:
+		return ii != dimFalse && im[locExterior][locInterior] != dimFalse
+	case dimA == 1:
+		return ii == 0
+	}
+	return false
+}
+
+// overlaps checks the overlaps pattern for geometries of dimension dimA and dimB
+func (im intersectionMatrix) overlaps(dimA, dimB int) bool {
+	if dimA != dimB {
+		return false
+	}
+	ii := im[locInterior][locInterior]
+	if dimA == 1 && ii != 1 {
+		return false
+	}
+	return ii != dimFalse &&
+		im[locInterior][locExterior] != dimFalse &&
+		im[locExterior][locInterior] != dimFalse
+}
+
+// touches checks that only the boundaries of the geometries meet
+func (im intersectionMatrix) touches(dimA, dimB int) bool {
+	if dimA == 0 && dimB == 0 {
+		return false
+	}
+	return im[locInterior][locInterior] == dimFalse &&
+		(im[locInterior][locBoundary] != dimFalse ||
+			im[locBoundary][locInterior] != dimFalse ||
+			im[locBoundary][locBoundary] != dimFalse)
+}
+
+// Relate returns the DE-9IM intersection matrix of a and b in its nine
+// character form, e.g. "212101212". The rows are the interior, boundary and
+// exterior of a and the columns those of b; each entry is the dimension of
+// their intersection, or F when it is empty.
+func Relate(a, b orb.Geometry) string {
+	return relate(normalize(a), normalize(b)).String()
+}
+
+// RelateMatch checks a DE-9IM matrix, as returned by Relate, against a nine
+// character pattern of 'T', 'F', '*', '0', '1' and '2', as JTS and PostGIS's
+// ST_RelateMatch do. It is false if either string is malformed.
+//
+//	predicates.RelateMatch(predicates.Relate(a, b), "T*F**F***") // Within
+func RelateMatch(matrix, pattern string) bool {
+	im, ok := parseMatrix(matrix)
+	return ok && im.matches(pattern)
+}
+
+// ValidPattern checks that pattern is a DE-9IM pattern RelateMatch accepts:
+// nine of 'T', 'F', '*', '0', '1' and '2', in either case
+func ValidPattern(pattern string) bool {
+	if len(pattern) != 9 {
+		return false
+	}
+	for i := 0; i < len(pattern); i++ {
+		if !strings.ContainsRune("TF*012tf", rune(pattern[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseMatrix parses the nine character form of a DE-9IM matrix
+func parseMatrix(s string) (intersectionMatrix, bool) {
+	var im intersectionMatrix
+	if len(s) != 9 {
+		return im, false
+	}
+	for i := 0; i < len(s); i++ {
+		d := dimFalse
+		switch c := s[i]; c {
+		case 'F', 'f':
+		case '0', '1', '2':
+			d = int(c - '0')
+		default:
+			return im, false
+		}
+		im[imOrder[i/3]][imOrder[i%3]] = d
+	}
+	return im, true
+}
+
+// holds reads predicate p, a single bit of a PredicateSet, from the matrix
+// of geometries of dimension dimA and dimB
+func (im intersectionMatrix) holds(p PredicateSet, dimA, dimB int) bool {
+	switch p {
+	case PredIntersects:
+		return im.intersects()
+	case PredDisjoint:
+		return !im.intersects()
+	case PredContains:
+		return im.contains()
+	case PredWithin:
+		return im.within()
+	case PredCovers:
+		return im.covers()
+	case PredCoveredBy:
+		return im.coveredBy()
+	case PredContainsProperly:
+		return im.containsProperly()
+	case PredCrosses:
+		return im.crosses(dimA, dimB)
+	case PredOverlaps:
+		return im.overlaps(dimA, dimB)
+	case PredTouches:
+		return im.touches(dimA, dimB)
+	case PredEquals:
+		return im.within() && im.contains()
+	}
+	return false
+}
+
+// decided checks that every predicate in set has the same value on im as on
+// any matrix im can still grow into. The relate engine only ever raises
+// entries, so an entry that is set stays set: a predicate that needs it
+// empty is false, and one that needs only some entries set is true once
+// they are.
+func (im intersectionMatrix) decided(set PredicateSet, dimA, dimB int) bool {
+	for set != 0 {
+		p := set & -set
+		set &^= p
+		if !im.settles(p, dimA, dimB) {
+			return false
+		}
+	}
+	return true
+}
+
+// settles checks that predicate p can no longer change, see decided
+func (im intersectionMatrix) settles(p PredicateSet, dimA, dimB int) bool {
+	ii := im[locInterior][locInterior]
+	ie, ei := im[locInterior][locExterior], im[locExterior][locInterior]
+	switch p {
+	case PredIntersects, PredDisjoint:
+		return im.intersects()
+	case PredWithin, PredCoveredBy:
+		return ie != dimFalse || im[locBoundary][locExterior] != dimFalse
+	case PredContains, PredCovers:
+		return ei != dimFalse || im[locExterior][locBoundary] != dimFalse
+	case PredContainsProperly:
+		return ei != dimFalse || im[locExterior][locBoundary] != dimFalse ||
+			im[locBoundary][locInterior] != dimFalse || im[locBoundary][locBoundary] != dimFalse
+	case PredCrosses:
+		switch {
+		case dimA < dimB:
+			return ii != dimFalse && ie != dimFalse
+		case dimA > dimB:
+			return ii != dimFalse && ei != dimFalse
+		case dimA == 1:
+			return ii == 1
+		}
+	case PredOverlaps:
+		if dimA == dimB {
+			return ii != dimFalse && ie != dimFalse && ei != dimFalse && (dimA != 1 || ii == 1)
+		}
+	case PredTouches:
+		if dimA != 0 || dimB != 0 {
+			return ii != dimFalse
+		}
+	case PredEquals:
+		return im.settles(PredWithin, dimA, dimB) || im.settles(PredContains, dimA, dimB)
+	}
+	return true
+}
+
+// relate computes the DE-9IM matrix of a and b.
+//
+// Both geometries are broken into points, lines and polygons, and a
+// Collection is the union of its parts: a line or point inside an area part
+// is part of that area, and polygons that share an edge or a vertex form one
+// area. The matrix is read from the topology graph of the two geometries,
+// see relateRun. The end points of lines are on the boundary under the
+// Mod-2 rule.
+func relate(a, b orb.Geometry) intersectionMatrix {
+	return relateWithRule(a, b, Mod2Rule)
+}
+
+// relateWithRule computes the DE-9IM matrix of a and b, using rule for the
+// end points of lines
+func relateWithRule(a, b orb.Geometry, rule BoundaryNodeRule) intersectionMatrix {
+	return relateUntil(a, b, Envelope(a), Envelope(b), rule, 0, 0, 0)
+}
+
+// relateUntil computes the DE-9IM matrix of normalized geometries a and b
+// with envelopes ea and eb, stopping as soon as the predicates in set are
+// decided for geometries of dimension dimA and dimB. Only those predicates
+// can be read from the result; an empty set computes the whole matrix.
+func relateUntil(a, b orb.Geometry, ea, eb orb.Bound, rule BoundaryNodeRule, set PredicateSet, dimA, dimB int) intersectionMatrix {
+	if im, ok := directMatrix(a, b, ea, eb); ok {
+		return im
+	}
+	return relateGraph(a, b, ea, eb, rule, set, dimA, dimB)
+}
+
+// relateGraph is relateUntil through the relate engine's own geometries,
+// for the pairs directMatrix does not know
+func relateGraph(a, b orb.Geometry, ea, eb orb.Bound, rule BoundaryNodeRule, set PredicateSet, dimA, dimB int) intersectionMatrix {
+	ga, gb := newRelateGeometry(a, ea, rule), newRelateGeometry(b, eb, rule)
+
+	// Exteriors of finite geometries always share the rest of the plane
+	r := relateRun{ga: ga, gb: gb, im: emptyMatrix(), set: set, dimA: dimA, dimB: dimB}
+	switch {
+	case ga.kind == pointsKind:
+		r.points(0)
+	case gb.kind == pointsKind:
+		r.points(1)
+	default:
+		r.run()
+	}
+	return r.im
+}
+
+// points relates geometry owner, made only of points, to the other without
+// building a graph: each point is located in the other, and the points
+// cannot cover any part of it of higher dimension
+func (r *relateRun) points(owner int) {
+	g, other := r.geometry(owner), r.geometry(1-owner)
+	add := func(loc, otherLoc, dim int) bool {
+		if owner == 0 {
+			return r.add(loc, otherLoc, dim)
+		}
+		return r.add(otherLoc, loc, dim)
+	}
+
+	switch {
+	case other.hasArea():
+		if add(locExterior, locInterior, 2) || add(locExterior, locBoundary, 1) {
+			return
+		}
+	case len(other.lines) > 0 || len(other.curves) > 0:
+		if add(locExterior, locInterior, 1) {
+			return
+		}
+		for _, p := range other.boundary {
+			if !g.pointSet.contains(p) && add(locExterior, locBoundary, 0) {
+				return
+			}
+		}
+	default:
+		for _, p := range other.points {
+			if !g.pointSet.contains(p) && add(locExterior, locInterior, 0) {
+				return
+			}
+		}
+	}
+	for _, p := range g.points {
+		if add(locInterior, other.locate(p), 0) {
+			return
+		}
+	}
+}
+
+// directMatrix computes the matrix without building the relate engine's
+// geometries when a point or circle is related to a circle, or points to a
+// single area. It is false for other inputs.
+func directMatrix(a, b orb.Geometry, ea, eb orb.Bound) (intersectionMatrix, bool) {
+	if im, ok := circleMatrix(a, b); ok {
+		return im, true
+	}
+	if im, ok := pointsAreaMatrix(a, b, eb); ok {
+		return im, true
+	}
+	if im, ok := pointsAreaMatrix(b, a, ea); ok {
+		return transpose(im), true
+	}
+	return intersectionMatrix{}, false
+}
+
+// pointsAreaMatrix relates a point or multipoint to an area with envelope
+// env by locating each point, as relateRun.points does. It is false when
+// area is not a single area, a point is on the boundaries of several
+// polygons, which may surround it, or there are enough points against a
+// large area to make the segment index worth building.
+func pointsAreaMatrix(points, area orb.Geometry, env orb.Bound) (intersectionMatrix, bool) {
+	var one [1]orb.Point
+	var ps []orb.Point
+	switch g := points.(type) {
+	case orb.Point:
+		one[0] = g
+		ps = one[:]
+	case orb.MultiPoint:
+		ps = g
+	default:
+		return intersectionMatrix{}, false
+	}
+	segments := 0
+	switch g := area.(type) {
+	case orb.Polygon:
+		if !polygonIsArea(g) {
+			return intersectionMatrix{}, false
+		}
+		for _, r := range g {
+			segments += len(r)
+		}
+	case orb.MultiPolygon:
+		for _, poly := range g {
+			if !polygonIsArea(poly) {
+				return intersectionMatrix{}, false
+			}
+			for _, r := range poly {
+				segments += len(r)
+			}
+		}
+	case orb.Ring:
+		if !shellHasArea(g) {
+			return intersectionMatrix{}, false
+		}
+		segments = len(g)
+	case orb.Bound, Circle:
+	default:
+		return intersectionMatrix{}, false
+	}
+	// Many points against a large area are faster through the segment index
+	if len(ps) > indexAfterLocates && segments > indexMinSegments {
+		return intersectionMatrix{}, false
+	}
+
+	im := emptyMatrix()
+	im.set(locExterior, locInterior, 2)
+	im.set(locExterior, locBoundary, 1)
+	for _, p := range ps {
+		if pointEmpty(p) {
+			continue
+		}
+		loc, ok := locateInArea(p, area, env)
+		if !ok {
+			return intersectionMatrix{}, false
+		}
+		im.set(locInterior, loc, 0)
+	}
+	return im, true
+}
+
+// locateInArea returns the location of p in a polygon, multipolygon, ring,
+// bound or circle with envelope env. It is false when p is on the
+// boundaries of several polygons.
+func locateInArea(p orb.Point, area orb.Geometry, env orb.Bound) (int, bool) {
+	if !boundContainsPoint(env, p) {
+		return locExterior, true
+	}
+	switch g := area.(type) {
+	case orb.Polygon:
+		return locateInPolygon(p, g), true
+	case orb.MultiPolygon:
+		return locateInMultiPolygon(p, g)
+	case orb.Ring:
+		return locateInPolygon(p, orb.Polygon{g}), true
+	case orb.Bound:
+		return locateInBound(p, g), true
+	case Circle:
+		return g.locate(p), true
+	}
+	return 0, false
+}
+
+// locateInMultiPolygon returns the location of p in a multipolygon. It is
+// false when p is on the boundaries of several polygons.
+func locateInMultiPolygon(p orb.Point, mp orb.MultiPolygon) (int, bool) {
+	onBoundary := 0
+	for _, poly := range mp {
+		switch locateInPolygon(p, poly) {
+		case locInterior:
+			return locInterior, true
+		case locBoundary:
+			onBoundary++
+		}
+	}
+	switch {
+	case onBoundary > 1:
+		return 0, false
+	case onBoundary > 0:
+		return locBoundary, true
+	}
+	return locExterior, true
+}
+
+// segmentPoint returns the point at fraction t along segment pq
+func segmentPoint(p, q orb.Point, t float64) orb.Point {
+	if t == 0 {
+		return p
+	}
+	if t == 1 {
+		return q
+	}
+	return orb.Point{p[0] + t*(q[0]-p[0]), p[1] + t*(q[1]-p[1])}
+}
+
+// relateKind is what a geometry is made of, for the locations the relate
+// engine knows without a search
+type relateKind int
+
+const (
+	mixedKind   relateKind = iota
+	pointsKind             // only points
+	linesKind              // only straight lines
+	polygonKind            // a single polygon
+)
+
+// relateGeometry is a geometry broken into the parts the relate engine works
+// with. Areas take precedence over lines, and lines over points, when a
+// location is looked up.
+type relateGeometry struct {
+	points   orb.MultiPoint
+	lines    orb.MultiLineString
+	polygons orb.MultiPolygon
+	boundary orb.MultiPoint // end points of the lines under the boundary node rule
+
+	custom      []Relater           // custom areas, located with Locate
+	customLines orb.MultiLineString // boundary lines of the custom areas
+
+	circles []Circle
+	curves  [][]relateEdge // arcs of the CircularStrings
+
+	env         orb.Bound
+	kind        relateKind
+	rings       []relateRing
+	pointSet    pointSet // points, for lookups
+	boundarySet pointSet // boundary, for lookups
+
+	// The straight segments of the lines and rings are searched to locate
+	// points, and indexed by y once enough points have been located for the
+	// index to pay for itself
+	segmentCount int
+	located      int
+	index        *bandindex.Index
+	oriented     bool
+
+	// Per polygon state of the point being located
+	probes  []polygonProbe
+	touched []int32
+	stamp   uint32
+}
+
+// relateRing is a ring of one of the polygons
+type relateRing struct {
+	ring         orb.Ring
+	poly         int32
+	shell        bool
+	interiorLeft bool // the polygon interior is on the left going along the ring, see orientRings
+}
+
+// newRelateGeometry breaks a normalized geometry g with envelope env into its
+// non-empty points, lines and polygons
+func newRelateGeometry(g orb.Geometry, env orb.Bound, rule BoundaryNodeRule) *relateGeometry {
+	rg := &relateGeometry{env: env}
+
+	collectPoints(g, &rg.points)
+	collectLineStrings(g, &rg.lines)
+
+	// CircularStrings join the lines at their end points
+	var strings []CircularString
+	collectCurves(g, &rg.circles, &strings)
+	ends := rg.lines
+	for _, cs := range strings {
+		rg.curves = append(rg.curves, cs.edges())
+		ends = append(ends[:len(ends):len(ends)], cs.ends())
+	}
+	if len(ends) > 0 {
+		rg.boundary = lineBoundary(ends, rule)
+	}
+
+	var polys []orb.Polygon
+	collectPolygons(g, &polys)
+	rg.polygons = polys
+	for pi, poly := range polys {
+		for i, ring := range poly {
+			rg.rings = append(rg.rings, relateRing{ring: ring, poly: int32(pi), shell: i == 0})
+			rg.segmentCount += max(len(ring)-1, 0)
+		}
+	}
+	for _, ls := range rg.lines {
+		rg.segmentCount += max(len(ls)-1, 1)
+	}
+
+	collectRelaters(g, &rg.custom)
+	for _, r := range rg.custom {
+		rg.customLines = append(rg.customLines, relaterLinework(r)...)
+	}
+
+	rg.pointSet = newPointSet(rg.points)
+	rg.boundarySet = newPointSet(rg.boundary)
+
+	onlyLines := len(rg.curves) == 0 && len(rg.circles) == 0 && len(rg.custom) == 0
+	switch {
+	case len(rg.points) > 0 && len(rg.lines) == 0 && len(polys) == 0 && onlyLines:
+		rg.kind = pointsKind
+	case len(rg.points) == 0 && len(rg.lines) > 0 && len(polys) == 0 && onlyLines:
+		rg.kind = linesKind
+	case len(rg.points) == 0 && len(rg.lines) == 0 && len(polys) == 1 && onlyLines:
+		rg.kind = polygonKind
+	}
+	return rg
+}
+
+// hasArea checks if the geometry has any polygons, circles or custom areas
+func (g *relateGeometry) hasArea() bool {
+	return len(g.polygons) > 0 || len(g.circles) > 0 || len(g.custom) > 0
+}
+
+// locateOnLinework returns the location of a point known to lie on the
+// lines or rings of the geometry, which is plain for the simple kinds
+func (g *relateGeometry) locateOnLinework(p orb.Point) int {
+	switch g.kind {
+	case pointsKind:
+		return locInterior
+	case linesKind:
+		if g.boundarySet.contains(p) {
+			return locBoundary
+		}
+		return locInterior
+	case polygonKind:
+		return locBoundary
+	}
+	return g.locate(p)
+}
+
+// locatePiece returns the location of a piece of edge e and of the faces on
+// either side of it, see locateEdge. The location of a piece of the
+// geometry's own lines or rings is plain for the simple kinds.
+func (g *relateGeometry) locatePiece(e *graphEdge, own bool, mid, dir orb.Point) (loc, left, right int) {
+	if own {
+		switch g.kind {
+		case linesKind:
+			return locInterior, locExterior, locExterior
+		case polygonKind:
+			if e.interiorLeft {
+				return locBoundary, locInterior, locExterior
+			}
+			return locBoundary, locExterior, locInterior
+		}
+	}
+	return g.locateEdge(mid, dir)
+}
+
+// locate returns the location of a point in the geometry
+func (g *relateGeometry) locate(p orb.Point) int {
+	if !boundContainsPoint(g.env, p) {
+		return locExterior
+	}
+	probe := g.probe(p, orb.Point{}, false)
+	if probe.interior {
+		return locInterior
+	}
+	onBoundary := probe.onBoundary
+	for _, r := range g.custom {
+		switch r.Locate(p) {
+		case Interior:
+			return locInterior
+		case OnBoundary:
+			onBoundary++
+		}
+	}
+	for _, c := range g.circles {
+		switch c.locate(p) {
+		case locInterior:
+			return locInterior
+		case locBoundary:
+			onBoundary++
+		}
+	}
+
+	switch {
+	case onBoundary > 1 && g.nodeSurrounded(p):
+		return locInterior
+	case onBoundary > 0:
+		return locBoundary
+	}
+	return g.locateLinesAndPoints(p, probe.onLine)
+}
+
+// locateLinesAndPoints returns the location of a point in the lines and
+// points, given whether it is on one of the straight lines
+func (g *relateGeometry) locateLinesAndPoints(p orb.Point, onLine bool) int {
+	if !onLine {
+	curves:
+		for _, curve := range g.curves {
+			for _, e := range curve {
+				if e.contains(p) {
+					onLine = true
+					break curves
+				}
+			}
+		}
+	}
+	if onLine {
+		if g.boundarySet.contains(p) {
+			return locBoundary
+		}
+		return locInterior
+	}
+	if g.pointSet.contains(p) {
+		return locInterior
+	}
+	return locExterior
+}
+
+// locateEdge returns the location of an open edge piece, which must not
+// cross any ring edge, from its midpoint and direction there, together with
+// the area location (interior or exterior) on its left and right sides
+func (g *relateGeometry) locateEdge(mid, dir orb.Point) (loc, left, right int) {
+	if !boundContainsPoint(g.env, mid) {
+		return locExterior, locExterior, locExterior
+	}
+	probe := g.probe(mid, dir, true)
+	if probe.interior {
+		return locInterior, locInterior, locInterior
+	}
+	leftIn, rightIn := probe.leftIn, probe.rightIn
+	for _, r := range g.custom {
+		switch r.Locate(mid) {
+		case Interior:
+			return locInterior, locInterior, locInterior
+		case OnBoundary:
+			left, right := locateSides(r, mid, dir)
+			leftIn = leftIn || left == locInterior
+			rightIn = rightIn || right == locInterior
+		}
+	}
+	for _, c := range g.circles {
+		switch c.locate(mid) {
+		case locInterior:
+			return locInterior, locInterior, locInterior
+		case locBoundary:
+			// The disk is on the side of the edge its centre is on
+			if dir[0]*(c.Center[1]-mid[1])-dir[1]*(c.Center[0]-mid[0]) > 0 {
+				leftIn = true
+			} else {
+				rightIn = true
+			}
+		}
+	}
+
+	switch {
+	case leftIn && rightIn:
+		return locInterior, locInterior, locInterior
+	case leftIn:
+		return locBoundary, locInterior, locExterior
+	case rightIn:
+		return locBoundary, locExterior, locInterior
+	}
+	return g.locateLinesAndPoints(mid, probe.onLine), locExterior, locExterior
+}
+
+// segmentProbe is what a point touches among the straight segments
+type segmentProbe struct {
+	onLine     bool // on a line
+	onBoundary int  // number of polygons with the point on their boundary
+	interior   bool // inside a polygon
+
+	// Whether a polygon is on the left or right of the direction given,
+	// when the point is on its boundary
+	leftIn, rightIn bool
+}
+
+// polygonProbe is the state of one polygon while a point is located
+type polygonProbe struct {
+	stamp    uint32
+	inside   bool
+	boundary bool
+}
+
+// Geometries with more than indexMinSegments segments are indexed once
+// more than indexAfterLocates points have been located in them; until then
+// the segments are scanned
+const (
+	indexMinSegments  = 32
+	indexAfterLocates = 8
+)
+
+// probe locates p among the straight segments of the lines and rings: it
+// counts the ring edges a ray from p to the right crosses and notes the
+// segments p is on. With sides set, it also finds which sides of direction
+// dir at p are inside a polygon whose boundary p is on.
+func (g *relateGeometry) probe(p, dir orb.Point, sides bool) segmentProbe {
+	var probe segmentProbe
+	if len(g.lines) == 0 && len(g.rings) == 0 {
+		return probe
+	}
+	if sides {
+		g.orientRings()
+	}
+	if g.probes == nil {
+		g.probes = make([]polygonProbe, len(g.polygons))
+	}
+	g.stamp++
+	g.touched = g.touched[:0]
+
+	g.located++
+	if g.index == nil && g.segmentCount > indexMinSegments && g.located > indexAfterLocates {
+		g.index = newSegmentIndex(g)
+	}
+	if g.index != nil {
+		for _, s := range g.index.Near(p[1]) {
+			g.probeSegment(&probe, p, dir, sides, s.A, s.B, s.Ring)
+		}
+	} else {
+		for _, ls := range g.lines {
+			if len(ls) == 1 {
+				g.probeSegment(&probe, p, dir, sides, ls[0], ls[0], -1)
+			}
+			for i := 0; i < len(ls)-1; i++ {
+				g.probeSegment(&probe, p, dir, sides, ls[i], ls[i+1], -1)
+			}
+		}
+		for ri, ring := range g.rings {
+			for i := 0; i < len(ring.ring)-1; i++ {
+				g.probeSegment(&probe, p, dir, sides, ring.ring[i], ring.ring[i+1], int32(ri))
+			}
+		}
+	}
+
+	for _, poly := range g.touched {
+		switch state := g.probes[poly]; {
+		case state.boundary:
+			probe.onBoundary++
+		case state.inside:
+			probe.interior = true
+		}
+	}
+	return probe
+}
+
+// probeSegment adds segment ab, of a line or of ring when ring is not -1,
+// to the probe of p
+func (g *relateGeometry) probeSegment(probe *segmentProbe, p, dir orb.Point, sides bool, a, b orb.Point, ring int32) {
+	// Only segments that reach the height of p can contain it or cross the ray
+	if (a[1] < p[1]-epsilon && b[1] < p[1]-epsilon) || (a[1] > p[1]+epsilon && b[1] > p[1]+epsilon) {
+		return
+	}
+	if ring < 0 {
+		if !probe.onLine && pointOnSegment(p, a, b) {
+			probe.onLine = true
+		}
+		return
+	}
+	if pointsEqual(a, b) {
+		return
+	}
+	r := &g.rings[ring]
+	state := &g.probes[r.poly]
+	if state.stamp != g.stamp {
+		*state = polygonProbe{stamp: g.stamp}
+		g.touched = append(g.touched, r.poly)
+	}
+	if pointOnSegment(p, a, b) {
+		state.boundary = true
+		if sides {
+			// Which side of the edge the polygon interior is on
+			sameDirection := dir[0]*(b[0]-a[0])+dir[1]*(b[1]-a[1]) > 0
+			if r.interiorLeft == sameDirection {
+				probe.leftIn = true
+			} else {
+				probe.rightIn = true
+			}
+		}
+		return
+	}
+	if crossesRay(p, a, b) {
+		state.inside = !state.inside
+	}
+}
+
+// orientRings finds which side of each ring the polygon interior is on
+func (g *relateGeometry) orientRings() {
+	if g.oriented {
+		return
+	}
+	g.oriented = true
+	for i := range g.rings {
+		r := &g.rings[i]
+		r.interiorLeft = r.shell == (r.ring.Orientation() == orb.CCW)
+	}
+}
+
+// newSegmentIndex indexes the straight segments of g by height. Segments
+// of lines have ring -1.
+func newSegmentIndex(g *relateGeometry) *bandindex.Index {
+	return bandindex.New(g.env, g.segmentCount, func(visit func(bandindex.Segment)) {
+		for _, ls := range g.lines {
+			if len(ls) == 1 {
+				visit(bandindex.Segment{A: ls[0], B: ls[0], Ring: -1})
+			}
+			for i := 0; i < len(ls)-1; i++ {
+				visit(bandindex.Segment{A: ls[i], B: ls[i+1], Ring: -1})
+			}
+		}
+		for ri, ring := range g.rings {
+			for i := 0; i < len(ring.ring)-1; i++ {
+				visit(bandindex.Segment{A: ring.ring[i], B: ring.ring[i+1], Ring: int32(ri)})
+			}
+		}
+	})
+}
+
+// pointSet is a set of points sorted by x, for finding a point within epsilon
+type pointSet []orb.Point
+
+// newPointSet returns the set of points
+func newPointSet(points []orb.Point) pointSet {
+	byX := func(p, q orb.Point) int { return cmp.Compare(p[0], q[0]) }
+	if slices.IsSortedFunc(points, byX) {
+		return points
+	}
+	set := append(pointSet(nil), points...)
+	slices.SortFunc(set, byX)
+	return set
+}
+
+// contains checks if the set has a point equal to p
+func (s pointSet) contains(p orb.Point) bool {
+	i := sort.Search(len(s), func(i int) bool { return s[i][0] >= p[0]-epsilon })
+	for ; i < len(s) && s[i][0] <= p[0]+epsilon; i++ {
+		if pointsEqual(s[i], p) {
+			return true
+		}
+	}
+	return false
+}
+
+// collectRelaters appends the non-empty custom areas of g
+func collectRelaters(g orb.Geometry, custom *[]Relater) {
+	if IsEmpty(g) {
+		return
+	}
+	switch geom := g.(type) {
+	case Relater:
+		*custom = append(*custom, geom)
+	case orb.Collection:
+		for _, part := range geom {
+			collectRelaters(part, custom)
+		}
+	}
+}
+
+// collectCurves appends the non-empty Circles and CircularStrings of g
+func collectCurves(g orb.Geometry, circles *[]Circle, strings *[]CircularString) {
+	if IsEmpty(g) {
+		return
+	}
+	switch geom := g.(type) {
+	case Circle:
+		*circles = append(*circles, geom)
+	case CircularString:
+		*strings = append(*strings, geom)
+	case orb.Collection:
+		for _, part := range geom {
+			collectCurves(part, circles, strings)
+		}
+	}
+}
+
+// sectorRay is a polygon edge leaving a node, with the side the polygon
+// interior is on
+type sectorRay struct {
+	angle        float64
+	dir          orb.Point
+	poly         int
+	interiorLeft bool
+}
+
+// nodeSurrounded checks if the polygons with p on their boundary together
+// cover every direction around p, as adjacent polygons do at a shared vertex
+func (g *relateGeometry) nodeSurrounded(p orb.Point) bool {
+	var rays []sectorRay
+	addRay := func(to orb.Point, poly int, interiorLeft bool) {
+		dir := orb.Point{to[0] - p[0], to[1] - p[1]}
+		rays = append(rays, sectorRay{math.Atan2(dir[1], dir[0]), dir, poly, interiorLeft})
+	}
+
+	for pi, poly := range g.polygons {
+		for i, ring := range poly {
+			if len(ring) < 4 || ring.Orientation() == 0 {
+				continue
+			}
+			interiorLeft := (i == 0) == (ring.Orientation() == orb.CCW)
+			for k := 0; k < len(ring)-1; k++ {
+				c, d := ring[k], ring[k+1]
+				if !pointOnSegment(p, c, d) || pointsEqual(c, d) {
+					continue
+				}
+				// The edge leaves p forwards towards d and backwards towards c
+				if !pointsEqual(p, d) {
+					addRay(d, pi, interiorLeft)
+				}
+				if !pointsEqual(p, c) {
+					addRay(c, pi, !interiorLeft)
+				}
+			}
+		}
+	}
+	if len(rays) == 0 {
+		return false
+	}
+	sort.Slice(rays, func(i, j int) bool { return rays[i].angle < rays[j].angle })
+
+	// Group rays that leave in the same direction; the sectors lie between groups
+	var groups [][]sectorRay
+	for _, r := range rays {
+		if n := len(groups); n > 0 {
+			last := groups[n-1][0]
+			if sign(last.dir[0]*r.dir[1]-last.dir[1]*r.dir[0]) == 0 &&
+				last.dir[0]*r.dir[0]+last.dir[1]*r.dir[1] > 0 {
+				groups[n-1] = append(groups[n-1], r)
+				continue
+			}
+		}
+		groups = append(groups, []sectorRay{r})
+	}
+	if len(groups) > 1 {
+		first, last := groups[0][0], groups[len(groups)-1][0]
+		if sign(last.dir[0]*first.dir[1]-last.dir[1]*first.dir[0]) == 0 &&
+			last.dir[0]*first.dir[0]+last.dir[1]*first.dir[1] > 0 {
+			groups[0] = append(groups[0], groups[len(groups)-1]...)
+			groups = groups[:len(groups)-1]
+		}
+	}
+
+	// The sector after group gi is inside a polygon when the polygon's last
+	// ray at or before it has the interior on its left
+	for gi := range groups {
+		covered := false
+		for pi := range g.polygons {
+			for back := 0; back < len(groups); back++ {
+				group := groups[(gi-back+len(groups))%len(groups)]
+				found, in := false, false
+				for _, r := range group {
+					if r.poly == pi {
+						found = true
+						in = in || r.interiorLeft
+					}
+				}
+				if found {
+					covered = in
+					break
+				}
+			}
+			if covered {
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
+}
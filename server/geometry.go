@@ -0,0 +1,109 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/paulmach/orb"
+
+	predicates "github.com/tingold/orb-predicates"
+	"github.com/tingold/orb-predicates/geojsonpred"
+)
+
+// errNotFound is returned for references to geometries that are not registered
+var errNotFound = errors.New("geometry not found")
+
+// errStoreFull is returned when MaxGeometries geometries are registered
+var errStoreFull = errors.New("too many registered geometries")
+
+// decoded is a geometry of a request with the envelope and emptiness that
+// are reported when it is registered
+type decoded struct {
+	geom  orb.Geometry
+	env   orb.Bound
+	empty bool
+}
+
+func newDecoded(g orb.Geometry) *decoded {
+	return &decoded{geom: g, env: predicates.Envelope(g), empty: predicates.IsEmpty(g)}
+}
+
+// store holds the registered geometries by ID
+type store struct {
+	mu    sync.RWMutex
+	geoms map[string]*decoded
+	max   int
+}
+
+func (s *store) get(id string) (*decoded, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	p, ok := s.geoms[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", errNotFound, id)
+	}
+	return p, nil
+}
+
+// put registers p under id, and reports whether it replaced a geometry
+func (s *store) put(id string, p *decoded) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, replaced := s.geoms[id]
+	if !replaced && s.max > 0 && len(s.geoms) >= s.max {
+		return false, errStoreFull
+	}
+	s.geoms[id] = p
+	return replaced, nil
+}
+
+func (s *store) delete(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.geoms[id]
+	delete(s.geoms, id)
+	return ok
+}
+
+// decode returns the geometry a request value stands for: a JSON string of
+// WKT, a GeoJSON geometry, Feature or FeatureCollection object, or
+// {"ref": "id"} naming a registered geometry
+func (s *store) decode(v json.RawMessage) (*decoded, error) {
+	data := bytes.TrimSpace(v)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil, errors.New("missing geometry")
+	}
+
+	if data[0] == '"' {
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return nil, err
+		}
+		g, err := predicates.ParseWKT(strings.TrimSpace(text))
+		if err != nil {
+			return nil, fmt.Errorf("bad WKT: %w", err)
+		}
+		return newDecoded(g), nil
+	}
+
+	var object struct {
+		Type string  `json:"type"`
+		Ref  *string `json:"ref"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, fmt.Errorf("geometry must be a WKT string or a GeoJSON object: %w", err)
+	}
+	if object.Ref != nil && object.Type == "" {
+		return s.get(*object.Ref)
+	}
+
+	g, err := geojsonpred.DecodeGeometry(data)
+	if err != nil {
+		return nil, fmt.Errorf("bad GeoJSON: %w", err)
+	}
+	return newDecoded(g), nil
+}
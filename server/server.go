@@ -0,0 +1,364 @@
+// Package server serves the predicates over HTTP as a JSON API, so that
+// services in other languages get the same answers as Go code.
+//
+// Geometries in requests are JSON strings of WKT, GeoJSON objects, or
+// {"ref": "id"} to use a geometry registered with PUT /geometries/{id}.
+// Registered geometries are decoded once, so repeated queries against them
+// skip decoding.
+//
+//	POST   /predicate/{name}  {"a": ..., "b": ...}                -> {"predicate": "within", "result": true}
+//	POST   /relate            {"a": ..., "b": ..., "pattern": ...} -> {"matrix": "212101212", "matches": true}
+//	POST   /batch             {"requests": [{"op": "within", "a": ..., "b": ...}, ...]} -> {"results": [...]}
+//	PUT    /geometries/{id}   {"geometry": ...}                    -> {"id": "zone", "envelope": [...]}
+//	GET    /geometries/{id}
+//	DELETE /geometries/{id}
+//
+// Errors are returned as {"error": "..."} with a 4xx or 5xx status.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	predicates "github.com/tingold/orb-predicates"
+)
+
+// Options configures a Server. Zero fields take their defaults.
+type Options struct {
+	// MaxBodyBytes limits the size of request bodies; 1 MiB by default
+	MaxBodyBytes int64
+
+	// Timeout limits the time to answer a request; 10 seconds by default.
+	// A request that runs out of time gets a 503 response and a batch stops
+	// before its next item, but an evaluation that has started runs to its
+	// end: Timeout is a deadline for the response, not a limit on CPU time,
+	// which MaxBodyBytes and MaxBatch bound instead.
+	Timeout time.Duration
+
+	// MaxBatch limits the number of requests in a batch; 1000 by default
+	MaxBatch int
+
+	// MaxGeometries limits the number of registered geometries; 10000 by default
+	MaxGeometries int
+}
+
+func (o Options) withDefaults() Options {
+	if o.MaxBodyBytes <= 0 {
+		o.MaxBodyBytes = 1 << 20
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = 10 * time.Second
+	}
+	if o.MaxBatch <= 0 {
+		o.MaxBatch = 1000
+	}
+	if o.MaxGeometries <= 0 {
+		o.MaxGeometries = 10000
+	}
+	return o
+}
+
+// Server is an http.Handler for the predicate API. It is safe for
+// concurrent use.
+type Server struct {
+	opts    Options
+	store   *store
+	handler http.Handler
+}
+
+// New returns a Server with the given options
+func New(opts Options) *Server {
+	s := &Server{
+		opts:  opts.withDefaults(),
+		store: &store{geoms: make(map[string]*decoded)},
+	}
+	s.store.max = s.opts.MaxGeometries
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /predicate/{name}", s.handlePredicate)
+	mux.HandleFunc("POST /relate", s.handleRelate)
+	mux.HandleFunc("POST /batch", s.handleBatch)
+	mux.HandleFunc("PUT /geometries/{id}", s.handlePutGeometry)
+	mux.HandleFunc("GET /geometries/{id}", s.handleGetGeometry)
+	mux.HandleFunc("DELETE /geometries/{id}", s.handleDeleteGeometry)
+	s.handler = http.TimeoutHandler(mux, s.opts.Timeout, `{"error":"request timed out"}`)
+	return s
+}
+
+// ServeHTTP answers an API request
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Set here so that the timeout response is labelled JSON too
+	w.Header().Set("Content-Type", "application/json")
+	s.handler.ServeHTTP(w, r)
+}
+
+// evaluate runs a predicate on decoded geometries
+func evaluate(p predicates.PredicateSet, a, b *decoded) bool {
+	return predicates.Evaluate(a.geom, b.geom, p) != 0
+}
+
+// pairRequest is the body of /predicate/{name} and /relate
+type pairRequest struct {
+	A       json.RawMessage `json:"a"`
+	B       json.RawMessage `json:"b"`
+	Pattern string          `json:"pattern,omitempty"`
+}
+
+type predicateResponse struct {
+	Predicate string `json:"predicate"`
+	Result    bool   `json:"result"`
+}
+
+type relateResponse struct {
+	Matrix  string `json:"matrix"`
+	Matches *bool  `json:"matches,omitempty"`
+}
+
+func (s *Server) handlePredicate(w http.ResponseWriter, r *http.Request) {
+	p, ok := predicates.ParsePredicate(r.PathValue("name"))
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown predicate %q", r.PathValue("name")))
+		return
+	}
+	var req pairRequest
+	if !s.readJSON(w, r, &req) {
+		return
+	}
+	a, b, err := s.decodePair(req.A, req.B)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, predicateResponse{Predicate: p.String(), Result: evaluate(p, a, b)})
+}
+
+func (s *Server) handleRelate(w http.ResponseWriter, r *http.Request) {
+	var req pairRequest
+	if !s.readJSON(w, r, &req) {
+		return
+	}
+	resp, err := s.relate(req)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// relate computes the DE-9IM matrix of a pair, and matches it against the
+// pattern if there is one
+func (s *Server) relate(req pairRequest) (relateResponse, error) {
+	if req.Pattern != "" && !predicates.ValidPattern(req.Pattern) {
+		return relateResponse{}, badRequest(fmt.Errorf("bad DE-9IM pattern %q", req.Pattern))
+	}
+	a, b, err := s.decodePair(req.A, req.B)
+	if err != nil {
+		return relateResponse{}, err
+	}
+	resp := relateResponse{Matrix: predicates.Relate(a.geom, b.geom)}
+	if req.Pattern != "" {
+		matches := predicates.RelateMatch(resp.Matrix, req.Pattern)
+		resp.Matches = &matches
+	}
+	return resp, nil
+}
+
+// batchRequest is the body of /batch
+type batchRequest struct {
+	Requests []batchItem `json:"requests"`
+}
+
+type batchItem struct {
+	Op string `json:"op"`
+	pairRequest
+}
+
+type batchResult struct {
+	Result  *bool  `json:"result,omitempty"`
+	Matrix  string `json:"matrix,omitempty"`
+	Matches *bool  `json:"matches,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+type batchResponse struct {
+	Results []batchResult `json:"results"`
+}
+
+// handleBatch answers many predicate and relate requests at once. Each
+// request gets a result or an error of its own.
+func (s *Server) handleBatch(w http.ResponseWriter, r *http.Request) {
+	var req batchRequest
+	if !s.readJSON(w, r, &req) {
+		return
+	}
+	if len(req.Requests) > s.opts.MaxBatch {
+		writeError(w, http.StatusRequestEntityTooLarge,
+			fmt.Errorf("batch of %d requests is larger than the limit of %d", len(req.Requests), s.opts.MaxBatch))
+		return
+	}
+
+	resp := batchResponse{Results: make([]batchResult, len(req.Requests))}
+	for i, item := range req.Requests {
+		// Stop once the request has timed out or the client has gone; the
+		// item being evaluated is not interrupted
+		if r.Context().Err() != nil {
+			return
+		}
+		resp.Results[i] = s.batchItem(item)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) batchItem(item batchItem) batchResult {
+	if strings.EqualFold(item.Op, "relate") {
+		rel, err := s.relate(item.pairRequest)
+		if err != nil {
+			return batchResult{Error: err.Error()}
+		}
+		return batchResult{Matrix: rel.Matrix, Matches: rel.Matches}
+	}
+
+	p, ok := predicates.ParsePredicate(item.Op)
+	if !ok {
+		return batchResult{Error: fmt.Sprintf("unknown op %q", item.Op)}
+	}
+	a, b, err := s.decodePair(item.A, item.B)
+	if err != nil {
+		return batchResult{Error: err.Error()}
+	}
+	result := evaluate(p, a, b)
+	return batchResult{Result: &result}
+}
+
+type geometryRequest struct {
+	Geometry json.RawMessage `json:"geometry"`
+}
+
+type geometryResponse struct {
+	ID        string     `json:"id"`
+	Type      string     `json:"type"`
+	Empty     bool       `json:"empty"`
+	Envelope  [4]float64 `json:"envelope"`
+	Dimension int        `json:"dimension"`
+}
+
+func newGeometryResponse(id string, p *decoded) geometryResponse {
+	return geometryResponse{
+		ID:        id,
+		Type:      p.geom.GeoJSONType(),
+		Empty:     p.empty,
+		Envelope:  [4]float64{p.env.Min[0], p.env.Min[1], p.env.Max[0], p.env.Max[1]},
+		Dimension: predicates.Dimension(p.geom),
+	}
+}
+
+// handlePutGeometry registers a geometry under an ID, replacing any
+// geometry registered under it before
+func (s *Server) handlePutGeometry(w http.ResponseWriter, r *http.Request) {
+	var req geometryRequest
+	if !s.readJSON(w, r, &req) {
+		return
+	}
+	p, err := s.store.decode(req.Geometry)
+	if err != nil {
+		writeError(w, statusOf(badRequest(err)), err)
+		return
+	}
+	id := r.PathValue("id")
+	replaced, err := s.store.put(id, p)
+	if err != nil {
+		writeError(w, http.StatusInsufficientStorage, err)
+		return
+	}
+	status := http.StatusCreated
+	if replaced {
+		status = http.StatusOK
+	}
+	writeJSON(w, status, newGeometryResponse(id, p))
+}
+
+func (s *Server) handleGetGeometry(w http.ResponseWriter, r *http.Request) {
+	p, err := s.store.get(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newGeometryResponse(r.PathValue("id"), p))
+}
+
+func (s *Server) handleDeleteGeometry(w http.ResponseWriter, r *http.Request) {
+	if !s.store.delete(r.PathValue("id")) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("%w: %q", errNotFound, r.PathValue("id")))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodePair decodes the two geometries of a request
+func (s *Server) decodePair(a, b json.RawMessage) (*decoded, *decoded, error) {
+	pa, err := s.store.decode(a)
+	if err != nil {
+		return nil, nil, badRequest(fmt.Errorf("a: %w", err))
+	}
+	pb, err := s.store.decode(b)
+	if err != nil {
+		return nil, nil, badRequest(fmt.Errorf("b: %w", err))
+	}
+	return pa, pb, nil
+}
+
+// requestError is an error in the content of a request
+type requestError struct{ err error }
+
+func (e requestError) Error() string { return e.err.Error() }
+func (e requestError) Unwrap() error { return e.err }
+
+func badRequest(err error) error { return requestError{err} }
+
+// statusOf returns the HTTP status for an error
+func statusOf(err error) int {
+	switch {
+	case errors.Is(err, errNotFound):
+		return http.StatusNotFound
+	case errors.As(err, new(requestError)):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// readJSON decodes the request body into v, writing an error response and
+// returning false if it is too large or malformed
+func (s *Server) readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	body := http.MaxBytesReader(w, r.Body, s.opts.MaxBodyBytes)
+	if err := json.NewDecoder(body).Decode(v); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge,
+				fmt.Errorf("request body is larger than the limit of %d bytes", tooLarge.Limit))
+			return false
+		}
+		writeError(w, http.StatusBadRequest, fmt.Errorf("bad request body: %w", err))
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	// The status is already sent, so an error writing the body, such as a
+	// client that has gone, cannot be reported to the client
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
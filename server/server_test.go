@@ -0,0 +1,215 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const square = `{"type": "Polygon", "coordinates": [[[0, 0], [4, 0], [4, 4], [0, 4], [0, 0]]]}`
+
+// do sends a request to the server and decodes the JSON response
+func do(t *testing.T, srv *httptest.Server, method, path, body string) (int, map[string]interface{}) {
+	t.Helper()
+	req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out map[string]interface{}
+	if len(data) > 0 && strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		if err := json.Unmarshal(data, &out); err != nil {
+			t.Fatalf("%s %s: response is not JSON: %v\n%s", method, path, err, data)
+		}
+	}
+	return resp.StatusCode, out
+}
+
+func TestPredicate(t *testing.T) {
+	srv := httptest.NewServer(New(Options{}))
+	defer srv.Close()
+
+	tests := []struct {
+		name     string
+		body     string
+		expected bool
+	}{
+		{"within", `{"a": "POINT (1 1)", "b": ` + square + `}`, true},
+		{"within", `{"a": "POINT (9 9)", "b": ` + square + `}`, false},
+		{"contains", `{"a": "POLYGON ((0 0, 4 0, 4 4, 0 4, 0 0))", "b": {"type": "Feature", "geometry": {"type": "Point", "coordinates": [1, 1]}, "properties": {}}}`, true},
+		{"touches", `{"a": "LINESTRING (4 0, 6 0)", "b": ` + square + `}`, true},
+		{"disjoint", `{"a": "POINT (9 9)", "b": ` + square + `}`, true},
+		{"covered_by", `{"a": "POINT (4 4)", "b": ` + square + `}`, true},
+		{"equals", `{"a": "POLYGON ((4 4, 0 4, 0 0, 4 0, 4 4))", "b": ` + square + `}`, true},
+		{"intersects", `{"a": "POINT EMPTY", "b": ` + square + `}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, resp := do(t, srv, "POST", "/predicate/"+tt.name, tt.body)
+			if status != http.StatusOK || resp["result"] != tt.expected {
+				t.Errorf("POST /predicate/%s %s = %d %v, expected %v", tt.name, tt.body, status, resp, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRelate(t *testing.T) {
+	srv := httptest.NewServer(New(Options{}))
+	defer srv.Close()
+
+	status, resp := do(t, srv, "POST", "/relate", `{"a": "POINT (1 1)", "b": `+square+`}`)
+	if status != http.StatusOK || resp["matrix"] != "0FFFFF212" || resp["matches"] != nil {
+		t.Errorf("POST /relate = %d %v, expected 0FFFFF212", status, resp)
+	}
+	status, resp = do(t, srv, "POST", "/relate", `{"a": "POINT (1 1)", "b": `+square+`, "pattern": "T*F**F***"}`)
+	if status != http.StatusOK || resp["matches"] != true {
+		t.Errorf("POST /relate with pattern = %d %v, expected a match", status, resp)
+	}
+	status, _ = do(t, srv, "POST", "/relate", `{"a": "POINT (1 1)", "b": `+square+`, "pattern": "T*F**F"}`)
+	if status != http.StatusBadRequest {
+		t.Errorf("POST /relate with a short pattern = %d, expected 400", status)
+	}
+}
+
+func TestRegisteredGeometries(t *testing.T) {
+	srv := httptest.NewServer(New(Options{MaxGeometries: 2}))
+	defer srv.Close()
+
+	status, resp := do(t, srv, "PUT", "/geometries/zone", `{"geometry": `+square+`}`)
+	if status != http.StatusCreated || resp["id"] != "zone" || resp["type"] != "Polygon" || fmt.Sprint(resp["envelope"]) != "[0 0 4 4]" {
+		t.Errorf("PUT /geometries/zone = %d %v", status, resp)
+	}
+	if status, _ := do(t, srv, "PUT", "/geometries/zone", `{"geometry": "POLYGON ((0 0, 8 0, 8 8, 0 8, 0 0))"}`); status != http.StatusOK {
+		t.Errorf("replacing PUT /geometries/zone = %d, expected 200", status)
+	}
+	if status, resp := do(t, srv, "GET", "/geometries/zone", ""); status != http.StatusOK || fmt.Sprint(resp["envelope"]) != "[0 0 8 8]" {
+		t.Errorf("GET /geometries/zone = %d %v, expected the replaced geometry", status, resp)
+	}
+
+	for _, tt := range []struct {
+		body     string
+		expected bool
+	}{
+		{`{"a": "POINT (6 6)", "b": {"ref": "zone"}}`, true},
+		{`{"a": "POINT (9 9)", "b": {"ref": "zone"}}`, false},
+		{`{"a": {"ref": "zone"}, "b": {"ref": "zone"}}`, true},
+	} {
+		if status, resp := do(t, srv, "POST", "/predicate/within", tt.body); status != http.StatusOK || resp["result"] != tt.expected {
+			t.Errorf("POST /predicate/within %s = %d %v, expected %v", tt.body, status, resp, tt.expected)
+		}
+	}
+
+	if status, _ := do(t, srv, "POST", "/predicate/within", `{"a": "POINT (1 1)", "b": {"ref": "missing"}}`); status != http.StatusNotFound {
+		t.Errorf("reference to a missing geometry = %d, expected 404", status)
+	}
+
+	// The store is limited to two geometries
+	do(t, srv, "PUT", "/geometries/second", `{"geometry": "POINT (1 1)"}`)
+	if status, _ := do(t, srv, "PUT", "/geometries/third", `{"geometry": "POINT (1 1)"}`); status != http.StatusInsufficientStorage {
+		t.Errorf("PUT beyond MaxGeometries = %d, expected 507", status)
+	}
+
+	if status, _ := do(t, srv, "DELETE", "/geometries/zone", ""); status != http.StatusNoContent {
+		t.Errorf("DELETE /geometries/zone = %d, expected 204", status)
+	}
+	if status, _ := do(t, srv, "GET", "/geometries/zone", ""); status != http.StatusNotFound {
+		t.Errorf("GET of a deleted geometry = %d, expected 404", status)
+	}
+	if status, _ := do(t, srv, "DELETE", "/geometries/zone", ""); status != http.StatusNotFound {
+		t.Errorf("second DELETE = %d, expected 404", status)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	srv := httptest.NewServer(New(Options{MaxBatch: 4}))
+	defer srv.Close()
+	do(t, srv, "PUT", "/geometries/zone", `{"geometry": `+square+`}`)
+
+	body := `{"requests": [
+		{"op": "within", "a": "POINT (1 1)", "b": {"ref": "zone"}},
+		{"op": "relate", "a": "POINT (1 1)", "b": {"ref": "zone"}, "pattern": "0FFFFF212"},
+		{"op": "nearby", "a": "POINT (1 1)", "b": {"ref": "zone"}},
+		{"op": "intersects", "a": "POINT (1", "b": {"ref": "zone"}}
+	]}`
+	status, resp := do(t, srv, "POST", "/batch", body)
+	if status != http.StatusOK {
+		t.Fatalf("POST /batch = %d %v", status, resp)
+	}
+	results := resp["results"].([]interface{})
+	first, second := results[0].(map[string]interface{}), results[1].(map[string]interface{})
+	if first["result"] != true || second["matrix"] != "0FFFFF212" || second["matches"] != true {
+		t.Errorf("batch results = %v", results)
+	}
+	for _, i := range []int{2, 3} {
+		if r := results[i].(map[string]interface{}); r["error"] == nil || r["result"] != nil {
+			t.Errorf("batch result %d = %v, expected an error", i, r)
+		}
+	}
+
+	large := `{"requests": [` + strings.Repeat(`{"op": "within", "a": "POINT (1 1)", "b": "POINT (1 1)"},`, 4) + `{"op": "within", "a": "POINT (1 1)", "b": "POINT (1 1)"}]}`
+	if status, _ := do(t, srv, "POST", "/batch", large); status != http.StatusRequestEntityTooLarge {
+		t.Errorf("batch beyond MaxBatch = %d, expected 413", status)
+	}
+}
+
+func TestLimits(t *testing.T) {
+	srv := httptest.NewServer(New(Options{MaxBodyBytes: 256}))
+	defer srv.Close()
+
+	big := `{"a": "POINT (1 1)", "b": "` + strings.Repeat(" ", 300) + `POINT (1 1)"}`
+	if status, resp := do(t, srv, "POST", "/predicate/within", big); status != http.StatusRequestEntityTooLarge || resp["error"] == nil {
+		t.Errorf("body beyond MaxBodyBytes = %d %v, expected 413", status, resp)
+	}
+
+	for _, tt := range []struct {
+		method, path, body string
+		expected           int
+	}{
+		{"POST", "/predicate/nearby", `{"a": "POINT (1 1)", "b": "POINT (1 1)"}`, http.StatusNotFound},
+		{"POST", "/predicate/within", `{"a": "POINT (1 1)"}`, http.StatusBadRequest},
+		{"POST", "/predicate/within", `{"a": "CIRCLE (1 1)", "b": "POINT (1 1)"}`, http.StatusBadRequest},
+		{"POST", "/predicate/within", `{"a": {"type": "Point"}, "b": "POINT (1 1)"}`, http.StatusBadRequest},
+		{"POST", "/predicate/within", `not json`, http.StatusBadRequest},
+		{"GET", "/predicate/within", "", http.StatusMethodNotAllowed},
+	} {
+		if status, _ := do(t, srv, tt.method, tt.path, tt.body); status != tt.expected {
+			t.Errorf("%s %s %s = %d, expected %d", tt.method, tt.path, tt.body, status, tt.expected)
+		}
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	srv := httptest.NewServer(New(Options{Timeout: time.Millisecond, MaxBodyBytes: 10 << 20}))
+	defer srv.Close()
+
+	// A batch of relates of many-sided polygons takes far longer than the timeout
+	var ring []string
+	for i := 0; i <= 500; i++ {
+		a := 2 * math.Pi * float64(i%500) / 500
+		ring = append(ring, fmt.Sprintf("%f %f", math.Cos(a), math.Sin(a)))
+	}
+	circle := `"POLYGON ((` + strings.Join(ring, ", ") + `))"`
+	item := `{"op": "relate", "a": ` + circle + `, "b": ` + circle + `}`
+	body := `{"requests": [` + strings.Repeat(item+",", 199) + item + `]}`
+
+	status, resp := do(t, srv, "POST", "/batch", body)
+	if status != http.StatusServiceUnavailable || resp["error"] == nil {
+		t.Errorf("slow batch = %d %v, expected 503", status, resp)
+	}
+}
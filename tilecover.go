@@ -0,0 +1,54 @@
+package predicates
+
+import (
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/maptile"
+)
+
+// TileCover returns the tiles at zoom z that g intersects, and the subset of
+// those that g covers entirely, so that covered tiles can take a fast path
+// and the others be clipped. g is in longitude and latitude. Tiles are
+// matched with Intersects and Covers against their Bound(), so a tile that
+// only shares an edge or corner with g is in covering too.
+//
+// The tiles are found by descending the quadtree from zoom 0: a tile g does
+// not intersect is dropped with all of its children, and all the children at
+// zoom z of a tile g covers are interior without further tests. Large
+// polygons therefore cost tests in proportion to the tiles along their
+// boundary rather than to all the tiles they cover.
+func TileCover(g orb.Geometry, z maptile.Zoom) (covering, interior []maptile.Tile) {
+	if IsEmpty(g) {
+		return nil, nil
+	}
+	g = normalize(g)
+	env := Envelope(g)
+
+	var descend func(t maptile.Tile)
+	descend = func(t maptile.Tile) {
+		bound := t.Bound()
+		if !boundsOverlap(bound, env) || !Intersects(g, bound) {
+			return
+		}
+		if Covers(g, bound) {
+			min, max := t.Range(z)
+			for x := min.X; x <= max.X; x++ {
+				for y := min.Y; y <= max.Y; y++ {
+					tile := maptile.New(x, y, z)
+					covering = append(covering, tile)
+					interior = append(interior, tile)
+				}
+			}
+			return
+		}
+		if t.Z == z {
+			covering = append(covering, t)
+			return
+		}
+		for _, child := range t.Children() {
+			descend(child)
+		}
+	}
+	descend(maptile.New(0, 0, 0))
+
+	return covering, interior
+}
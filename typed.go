@@ -0,0 +1,269 @@
+package predicates
+
+import "github.com/paulmach/orb"
+
+// The functions in this file are type-specialised versions of the predicates
+// for hot loops. They give the same results as the generic predicates but
+// skip the interface dispatch and do not allocate. Empty geometries and
+// collapsed areas are rare, so they are passed on to the generic predicates.
+// Points are located with the ray test the relate engine probes polygons
+// with, see locateInPolygon.
+
+// PointInPolygon returns true if p lies in the interior of poly, like Within(p, poly)
+func PointInPolygon(p orb.Point, poly orb.Polygon) bool {
+	if !polygonIsArea(poly) {
+		return Within(p, poly)
+	}
+	return boundContainsPoint(poly.Bound(), p) && locateInPolygon(p, poly) == locInterior
+}
+
+// PointInRing returns true if p lies in the interior of r, like Within(p, r)
+func PointInRing(p orb.Point, r orb.Ring) bool {
+	if !shellHasArea(r) {
+		return Within(p, r)
+	}
+	if !boundContainsPoint(r.Bound(), p) {
+		return false
+	}
+	inside := false
+	return !probeRing(p, r, &inside) && inside
+}
+
+// PointInMultiPolygon returns true if p lies in the interior of one of the
+// polygons, like Within(p, mp)
+func PointInMultiPolygon(p orb.Point, mp orb.MultiPolygon) bool {
+	for _, poly := range mp {
+		if !polygonIsArea(poly) {
+			return Within(p, mp)
+		}
+	}
+	// A point on the boundaries of several polygons may be surrounded by
+	// them, which the relate engine decides
+	onBoundary := 0
+	for _, poly := range mp {
+		if !boundContainsPoint(poly.Bound(), p) {
+			continue
+		}
+		switch locateInPolygon(p, poly) {
+		case locInterior:
+			return true
+		case locBoundary:
+			onBoundary++
+		}
+	}
+	return onBoundary > 1 && Within(p, mp)
+}
+
+// PointInBound returns true if p lies in the interior of b, like Within(p, b)
+func PointInBound(p orb.Point, b orb.Bound) bool {
+	if !boundIsArea(b) {
+		return Within(p, b)
+	}
+	return locateInBound(p, b) == locInterior
+}
+
+// PolygonCoversPoint returns true if p lies in poly or on its boundary, like Covers(poly, p)
+func PolygonCoversPoint(poly orb.Polygon, p orb.Point) bool {
+	if !polygonIsArea(poly) {
+		return Covers(poly, p)
+	}
+	return boundContainsPoint(poly.Bound(), p) && locateInPolygon(p, poly) != locExterior
+}
+
+// BoundCoversPoint returns true if p lies in b or on its boundary, like Covers(b, p)
+func BoundCoversPoint(b orb.Bound, p orb.Point) bool {
+	if !boundIsArea(b) {
+		return Covers(b, p)
+	}
+	return locateInBound(p, b) != locExterior
+}
+
+// LineStringIntersectsLineString returns true if the lines share a point, like Intersects(a, b)
+func LineStringIntersectsLineString(a, b orb.LineString) bool {
+	if len(a) < 2 || len(b) < 2 {
+		return Intersects(a, b)
+	}
+	return lineStringsIntersect(a, b)
+}
+
+// LineStringIntersectsPolygon returns true if ls and poly share a point, like Intersects(ls, poly)
+func LineStringIntersectsPolygon(ls orb.LineString, poly orb.Polygon) bool {
+	if len(ls) < 2 || !polygonIsArea(poly) {
+		return Intersects(ls, poly)
+	}
+	return boundsOverlap(ls.Bound(), poly.Bound()) && lineStringIntersectsPolygon(ls, poly)
+}
+
+// PolygonIntersectsPolygon returns true if the polygons share a point, like Intersects(a, b)
+func PolygonIntersectsPolygon(a, b orb.Polygon) bool {
+	if !polygonIsArea(a) || !polygonIsArea(b) {
+		return Intersects(a, b)
+	}
+	return boundsOverlap(a.Bound(), b.Bound()) && polygonsIntersect(a, b)
+}
+
+// BoundIntersectsBound returns true if the bounds share a point, like Intersects(a, b)
+func BoundIntersectsBound(a, b orb.Bound) bool {
+	if a.IsEmpty() || b.IsEmpty() {
+		return false
+	}
+	return boundsOverlap(a, b)
+}
+
+// BoundIntersectsLineString returns true if b and ls share a point, like Intersects(b, ls)
+func BoundIntersectsLineString(b orb.Bound, ls orb.LineString) bool {
+	if !boundIsArea(b) || len(ls) < 2 {
+		return Intersects(b, ls)
+	}
+	return boundsOverlap(b, ls.Bound()) && boundIntersectsLineString(b, ls)
+}
+
+// BoundIntersectsPolygon returns true if b and poly share a point, like Intersects(b, poly)
+func BoundIntersectsPolygon(b orb.Bound, poly orb.Polygon) bool {
+	if !boundIsArea(b) || !polygonIsArea(poly) {
+		return Intersects(b, poly)
+	}
+	return boundsOverlap(b, poly.Bound()) && boundIntersectsPolygon(b, poly)
+}
+
+// boundIntersectsLineString checks if ls has a vertex in b or meets an edge of it
+func boundIntersectsLineString(b orb.Bound, ls orb.LineString) bool {
+	for _, p := range ls {
+		if boundContainsPoint(b, p) {
+			return true
+		}
+	}
+	for i := 0; i < len(ls)-1; i++ {
+		if segmentIntersectsBoundEdges(ls[i], ls[i+1], b) {
+			return true
+		}
+	}
+	return false
+}
+
+// boundIntersectsPolygon checks if an edge of poly meets an edge of b, or
+// else one contains a vertex of the other
+func boundIntersectsPolygon(b orb.Bound, poly orb.Polygon) bool {
+	for _, ring := range poly {
+		for i := 0; i < len(ring)-1; i++ {
+			if segmentIntersectsBoundEdges(ring[i], ring[i+1], b) {
+				return true
+			}
+		}
+	}
+	// Without edge contact one contains the other, or they are apart
+	return locateInBound(poly[0][0], b) != locExterior || locateInPolygon(b.Min, poly) != locExterior
+}
+
+// locateInPolygon returns the location of p in poly
+func locateInPolygon(p orb.Point, poly orb.Polygon) int {
+	inside := false
+	for _, ring := range poly {
+		if probeRing(p, ring, &inside) {
+			return locBoundary
+		}
+	}
+	if inside {
+		return locInterior
+	}
+	return locExterior
+}
+
+// locateInBound returns the location of p in b
+func locateInBound(p orb.Point, b orb.Bound) int {
+	ring := [5]orb.Point{b.Min, {b.Max[0], b.Min[1]}, b.Max, {b.Min[0], b.Max[1]}, b.Min}
+	inside := false
+	switch {
+	case probeRing(p, ring[:], &inside):
+		return locBoundary
+	case inside:
+		return locInterior
+	}
+	return locExterior
+}
+
+// probeRing checks if p is on the ring, as the relate engine's probe does,
+// and otherwise flips inside for every edge the ray from p to the right
+// crosses
+func probeRing(p orb.Point, ring []orb.Point, inside *bool) bool {
+	for i := 0; i < len(ring)-1; i++ {
+		a, b := ring[i], ring[i+1]
+		if (a[1] < p[1]-epsilon && b[1] < p[1]-epsilon) || (a[1] > p[1]+epsilon && b[1] > p[1]+epsilon) || pointsEqual(a, b) {
+			continue
+		}
+		if pointOnSegment(p, a, b) {
+			return true
+		}
+		if crossesRay(p, a, b) {
+			*inside = !*inside
+		}
+	}
+	return false
+}
+
+// lineStringIntersectsPolygon checks if ls meets an edge of poly or has a
+// vertex inside it
+func lineStringIntersectsPolygon(ls orb.LineString, poly orb.Polygon) bool {
+	for _, ring := range poly {
+		if lineStringsIntersect(ls, orb.LineString(ring)) {
+			return true
+		}
+	}
+	return locateInPolygon(ls[0], poly) != locExterior
+}
+
+// polygonsIntersect checks if the edges of two polygons meet, or else one
+// has its first vertex inside the other
+func polygonsIntersect(a, b orb.Polygon) bool {
+	for _, ra := range a {
+		for _, rb := range b {
+			if lineStringsIntersect(orb.LineString(ra), orb.LineString(rb)) {
+				return true
+			}
+		}
+	}
+	return locateInPolygon(a[0][0], b) != locExterior || locateInPolygon(b[0][0], a) != locExterior
+}
+
+// polygonIsArea checks that a polygon has a shell that clearly encloses an area
+func polygonIsArea(poly orb.Polygon) bool {
+	return len(poly) > 0 && shellHasArea(poly[0])
+}
+
+// boundIsArea checks that a bound is neither empty nor collapsed to a line or point
+func boundIsArea(b orb.Bound) bool {
+	return !b.IsEmpty() && b.Max[0]-b.Min[0] >= epsilon && b.Max[1]-b.Min[1] >= epsilon
+}
+
+// segmentIntersectsBoundEdges checks if segment pq meets any edge of b
+func segmentIntersectsBoundEdges(p, q orb.Point, b orb.Bound) bool {
+	corners := [5]orb.Point{
+		b.Min,
+		{b.Max[0], b.Min[1]},
+		b.Max,
+		{b.Min[0], b.Max[1]},
+		b.Min,
+	}
+	for i := 0; i < 4; i++ {
+		if segmentsIntersect(p, q, corners[i], corners[i+1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// PointInCircle returns true if p lies in the interior of c, like Within(p, c)
+func PointInCircle(p orb.Point, c Circle) bool {
+	if !(c.Radius >= epsilon) {
+		return Within(p, c)
+	}
+	return c.locate(p) == locInterior
+}
+
+// CircleCoversPoint returns true if p lies in c or on its circumference, like Covers(c, p)
+func CircleCoversPoint(c Circle, p orb.Point) bool {
+	if !(c.Radius >= epsilon) {
+		return Covers(c, p)
+	}
+	return c.locate(p) != locExterior
+}
@@ -0,0 +1,463 @@
+package predicates
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/paulmach/orb"
+)
+
+// The functions in this file evaluate the predicates on WKB and EWKB, as
+// stored by PostGIS, without decoding every geometry. The envelopes are read
+// straight from the bytes, and the geometries are only decoded when the
+// envelopes leave the answer open. Z and M coordinates, in the EWKB flags or
+// the ISO type codes, are read past and dropped.
+
+// ErrInvalidWKB is returned by the WKB predicates for data that is not WKB or EWKB
+var ErrInvalidWKB = errors.New("predicates: invalid WKB")
+
+// ErrSRIDMismatch is returned by the WKB predicates for geometries with
+// different SRIDs. WKB without an SRID has SRID 0, as in PostGIS.
+var ErrSRIDMismatch = errors.New("predicates: SRID mismatch")
+
+const (
+	wkbPoint uint32 = iota + 1
+	wkbLineString
+	wkbPolygon
+	wkbMultiPoint
+	wkbMultiLineString
+	wkbMultiPolygon
+	wkbCollection
+)
+
+// EWKB flags in the high bits of the type
+const (
+	ewkbZ    uint32 = 0x80000000
+	ewkbM    uint32 = 0x40000000
+	ewkbSRID uint32 = 0x20000000
+)
+
+// envelopeCheck is what the envelopes of the arguments must satisfy for a
+// predicate to hold, or for Disjoint to fail
+type envelopeCheck int
+
+const (
+	envelopesMeet envelopeCheck = iota
+	envelopesApart
+	envelopeWithin
+	envelopeContains
+)
+
+// IntersectsWKB is Intersects for WKB or EWKB geometries
+func IntersectsWKB(a, b []byte) (bool, error) {
+	return evalWKB(Intersects, envelopesMeet, a, b)
+}
+
+// DisjointWKB is Disjoint for WKB or EWKB geometries
+func DisjointWKB(a, b []byte) (bool, error) {
+	return evalWKB(Disjoint, envelopesApart, a, b)
+}
+
+// ContainsWKB is Contains for WKB or EWKB geometries
+func ContainsWKB(a, b []byte) (bool, error) {
+	return evalWKB(Contains, envelopeContains, a, b)
+}
+
+// WithinWKB is Within for WKB or EWKB geometries
+func WithinWKB(a, b []byte) (bool, error) {
+	return evalWKB(Within, envelopeWithin, a, b)
+}
+
+// CoversWKB is Covers for WKB or EWKB geometries
+func CoversWKB(a, b []byte) (bool, error) {
+	return evalWKB(Covers, envelopeContains, a, b)
+}
+
+// CoveredByWKB is CoveredBy for WKB or EWKB geometries
+func CoveredByWKB(a, b []byte) (bool, error) {
+	return evalWKB(CoveredBy, envelopeWithin, a, b)
+}
+
+// ContainsProperlyWKB is ContainsProperly for WKB or EWKB geometries
+func ContainsProperlyWKB(a, b []byte) (bool, error) {
+	return evalWKB(ContainsProperly, envelopeContains, a, b)
+}
+
+// CrossesWKB is Crosses for WKB or EWKB geometries
+func CrossesWKB(a, b []byte) (bool, error) {
+	return evalWKB(Crosses, envelopesMeet, a, b)
+}
+
+// OverlapsWKB is Overlaps for WKB or EWKB geometries
+func OverlapsWKB(a, b []byte) (bool, error) {
+	return evalWKB(Overlaps, envelopesMeet, a, b)
+}
+
+// TouchesWKB is Touches for WKB or EWKB geometries
+func TouchesWKB(a, b []byte) (bool, error) {
+	return evalWKB(Touches, envelopesMeet, a, b)
+}
+
+// WKBEnvelope returns the envelope and SRID of a WKB or EWKB geometry
+// without decoding it. Like Envelope, the envelope of an empty geometry is
+// the zero orb.Bound.
+func WKBEnvelope(data []byte) (orb.Bound, int, error) {
+	env, srid, err := scanWKB(data, "geometry")
+	return env.bound(), srid, err
+}
+
+// DecodeWKB decodes a WKB or EWKB geometry and returns it with its SRID.
+// Z and M coordinates are dropped, and POINT EMPTY is a Point with NaN
+// coordinates.
+func DecodeWKB(data []byte) (orb.Geometry, int, error) {
+	return decodeWKB(data, "geometry")
+}
+
+func decodeWKB(data []byte, name string) (orb.Geometry, int, error) {
+	r := &wkbReader{data: data, name: name}
+	g, srid, err := r.geometry(true)
+	if err == nil && r.pos != len(data) {
+		err = r.errorf("%d bytes after the geometry", len(data)-r.pos)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	return g, srid, nil
+}
+
+// evalWKB settles pred from the envelopes of a and b when it can, and
+// decodes them otherwise
+func evalWKB(pred func(a, b orb.Geometry) bool, check envelopeCheck, a, b []byte) (bool, error) {
+	envA, sridA, err := scanWKB(a, "a")
+	if err != nil {
+		return false, err
+	}
+	envB, sridB, err := scanWKB(b, "b")
+	if err != nil {
+		return false, err
+	}
+	if sridA != sridB {
+		return false, fmt.Errorf("%w: %d and %d", ErrSRIDMismatch, sridA, sridB)
+	}
+
+	// Without the geometries, the answer is the one for envelopes that are
+	// apart: true for Disjoint and false for the others
+	apart := check == envelopesApart
+	if envA.empty || envB.empty {
+		return apart, nil
+	}
+	ba, bb := envA.bound(), envB.bound()
+	switch check {
+	case envelopesMeet, envelopesApart:
+		if !boundsOverlap(ba, bb) {
+			return apart, nil
+		}
+	case envelopeWithin:
+		if !boundCoversBound(bb, ba) {
+			return false, nil
+		}
+	case envelopeContains:
+		if !boundCoversBound(ba, bb) {
+			return false, nil
+		}
+	}
+
+	ga, _, err := decodeWKB(a, "a")
+	if err != nil {
+		return false, err
+	}
+	gb, _, err := decodeWKB(b, "b")
+	if err != nil {
+		return false, err
+	}
+	return pred(ga, gb), nil
+}
+
+// boundCoversBound checks that b lies in a, with epsilon tolerance
+func boundCoversBound(a, b orb.Bound) bool {
+	return b.Min[0] >= a.Min[0]-epsilon &&
+		b.Max[0] <= a.Max[0]+epsilon &&
+		b.Min[1] >= a.Min[1]-epsilon &&
+		b.Max[1] <= a.Max[1]+epsilon
+}
+
+// wkbOrder is the byte order of a geometry, true for little endian. The
+// coordinate loops read faster through it than through binary.ByteOrder.
+type wkbOrder bool
+
+func (o wkbOrder) uint32(b []byte) uint32 {
+	if o {
+		return binary.LittleEndian.Uint32(b)
+	}
+	return binary.BigEndian.Uint32(b)
+}
+
+func (o wkbOrder) float64(b []byte) float64 {
+	if o {
+		return math.Float64frombits(binary.LittleEndian.Uint64(b))
+	}
+	return math.Float64frombits(binary.BigEndian.Uint64(b))
+}
+
+// wkbEnvelope accumulates the envelope of the coordinates read
+type wkbEnvelope struct {
+	min, max orb.Point
+	empty    bool
+}
+
+func (e *wkbEnvelope) extend(x, y float64) {
+	if x != x || y != y { // NaN
+		return
+	}
+	if e.empty {
+		e.min, e.max, e.empty = orb.Point{x, y}, orb.Point{x, y}, false
+		return
+	}
+	if x < e.min[0] {
+		e.min[0] = x
+	} else if x > e.max[0] {
+		e.max[0] = x
+	}
+	if y < e.min[1] {
+		e.min[1] = y
+	} else if y > e.max[1] {
+		e.max[1] = y
+	}
+}
+
+func (e wkbEnvelope) bound() orb.Bound {
+	if e.empty {
+		return orb.Bound{}
+	}
+	return orb.Bound{Min: e.min, Max: e.max}
+}
+
+// scanWKB reads the envelope and SRID of a WKB geometry without allocating
+func scanWKB(data []byte, name string) (wkbEnvelope, int, error) {
+	r := &wkbReader{data: data, name: name, env: wkbEnvelope{empty: true}}
+	_, srid, err := r.geometry(false)
+	if err == nil && r.pos != len(data) {
+		err = r.errorf("%d bytes after the geometry", len(data)-r.pos)
+	}
+	return r.env, srid, err
+}
+
+// wkbReader reads one WKB geometry. It always extends env with the
+// coordinates it reads, and builds the orb geometry when asked to.
+type wkbReader struct {
+	data []byte
+	pos  int
+	name string
+	env  wkbEnvelope
+}
+
+func (r *wkbReader) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s: %s at byte %d", ErrInvalidWKB, r.name, fmt.Sprintf(format, args...), r.pos)
+}
+
+// header reads the byte order, type and SRID of a geometry, and returns the
+// base type and the number of coordinates per point
+func (r *wkbReader) header() (wkbOrder, uint32, int, int, error) {
+	if r.pos+5 > len(r.data) {
+		return false, 0, 0, 0, r.errorf("truncated header")
+	}
+	if r.data[r.pos] > 1 {
+		return false, 0, 0, 0, r.errorf("bad byte order %d", r.data[r.pos])
+	}
+	order := wkbOrder(r.data[r.pos] == 1)
+	typ := order.uint32(r.data[r.pos+1:])
+	r.pos += 5
+
+	dims := 2
+	if typ&ewkbZ != 0 {
+		dims++
+	}
+	if typ&ewkbM != 0 {
+		dims++
+	}
+	srid := 0
+	if typ&ewkbSRID != 0 {
+		if r.pos+4 > len(r.data) {
+			return false, 0, 0, 0, r.errorf("truncated SRID")
+		}
+		srid = int(int32(order.uint32(r.data[r.pos:])))
+		r.pos += 4
+	}
+
+	// ISO WKB adds 1000 for Z, 2000 for M and 3000 for ZM to the type
+	typ &^= ewkbZ | ewkbM | ewkbSRID
+	switch typ / 1000 {
+	case 1, 2:
+		dims++
+	case 3:
+		dims += 2
+	}
+	typ %= 1000
+	return order, typ, srid, dims, nil
+}
+
+func (r *wkbReader) count(order wkbOrder, size int) (int, error) {
+	if r.pos+4 > len(r.data) {
+		return 0, r.errorf("truncated count")
+	}
+	n := int(order.uint32(r.data[r.pos:]))
+	r.pos += 4
+	// Every element takes at least size bytes, so a larger count is corrupt
+	if size > 0 && n > (len(r.data)-r.pos)/size {
+		return 0, r.errorf("count %d exceeds the data", n)
+	}
+	return n, nil
+}
+
+func (r *wkbReader) point(order wkbOrder, dims int) orb.Point {
+	x := order.float64(r.data[r.pos:])
+	y := order.float64(r.data[r.pos+8:])
+	r.pos += 8 * dims
+	r.env.extend(x, y)
+	return orb.Point{x, y}
+}
+
+// points reads a counted list of points, skipping them without touching the
+// envelope when skip is set
+func (r *wkbReader) points(order wkbOrder, dims int, build, skip bool) ([]orb.Point, error) {
+	n, err := r.count(order, 8*dims)
+	if err != nil {
+		return nil, err
+	}
+	size := n * 8 * dims
+	if skip {
+		r.pos += size
+		return nil, nil
+	}
+	if !build {
+		// The hot path of the envelope scan
+		env := r.env
+		buf := r.data[r.pos : r.pos+size]
+		for i := 0; i < size; i += 8 * dims {
+			env.extend(order.float64(buf[i:]), order.float64(buf[i+8:]))
+		}
+		r.env, r.pos = env, r.pos+size
+		return nil, nil
+	}
+	ps := make([]orb.Point, n)
+	for i := range ps {
+		ps[i] = r.point(order, dims)
+	}
+	return ps, nil
+}
+
+func (r *wkbReader) polygon(order wkbOrder, dims int, build bool) (orb.Polygon, error) {
+	n, err := r.count(order, 4)
+	if err != nil {
+		return nil, err
+	}
+	var poly orb.Polygon
+	if build {
+		poly = make(orb.Polygon, 0, n)
+	}
+	for i := 0; i < n; i++ {
+		// The holes lie in the shell, so only the shell is needed for the
+		// envelope
+		ps, err := r.points(order, dims, build, !build && i > 0)
+		if err != nil {
+			return nil, err
+		}
+		if build {
+			poly = append(poly, orb.Ring(ps))
+		}
+	}
+	return poly, nil
+}
+
+// geometry reads a geometry and its SRID; the geometry is nil unless build is set
+func (r *wkbReader) geometry(build bool) (orb.Geometry, int, error) {
+	order, typ, srid, dims, err := r.header()
+	if err != nil {
+		return nil, 0, err
+	}
+	g, err := r.body(order, typ, dims, build)
+	return g, srid, err
+}
+
+func (r *wkbReader) body(order wkbOrder, typ uint32, dims int, build bool) (orb.Geometry, error) {
+	switch typ {
+	case wkbPoint:
+		if r.pos+8*dims > len(r.data) {
+			return nil, r.errorf("truncated point")
+		}
+		p := r.point(order, dims)
+		if !build {
+			return nil, nil
+		}
+		return p, nil
+	case wkbLineString:
+		ps, err := r.points(order, dims, build, false)
+		if err != nil || !build {
+			return nil, err
+		}
+		return orb.LineString(ps), nil
+	case wkbPolygon:
+		poly, err := r.polygon(order, dims, build)
+		if err != nil || !build {
+			return nil, err
+		}
+		return poly, nil
+	case wkbMultiPoint, wkbMultiLineString, wkbMultiPolygon, wkbCollection:
+	default:
+		return nil, fmt.Errorf("%w: %s: WKB type %d", ErrUnsupportedType, r.name, typ)
+	}
+
+	// The parts of multi geometries and collections are geometries with their
+	// own headers
+	n, err := r.count(order, 9)
+	if err != nil {
+		return nil, err
+	}
+	var parts []orb.Geometry
+	if build {
+		parts = make([]orb.Geometry, 0, n)
+	}
+	for i := 0; i < n; i++ {
+		order, part, _, dims, err := r.header()
+		if err != nil {
+			return nil, err
+		}
+		if typ != wkbCollection && part != typ-3 {
+			return nil, r.errorf("part of type %d in a multi geometry of type %d", part, typ)
+		}
+		g, err := r.body(order, part, dims, build)
+		if err != nil {
+			return nil, err
+		}
+		if build {
+			parts = append(parts, g)
+		}
+	}
+	if !build {
+		return nil, nil
+	}
+
+	switch typ {
+	case wkbMultiPoint:
+		mp := make(orb.MultiPoint, len(parts))
+		for i, g := range parts {
+			mp[i] = g.(orb.Point)
+		}
+		return mp, nil
+	case wkbMultiLineString:
+		mls := make(orb.MultiLineString, len(parts))
+		for i, g := range parts {
+			mls[i] = g.(orb.LineString)
+		}
+		return mls, nil
+	case wkbMultiPolygon:
+		mp := make(orb.MultiPolygon, len(parts))
+		for i, g := range parts {
+			mp[i] = g.(orb.Polygon)
+		}
+		return mp, nil
+	}
+	return orb.Collection(parts), nil
+}
@@ -0,0 +1,402 @@
+package predicates
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/paulmach/orb"
+)
+
+// ParseWKT parses WKT into an orb.Geometry. Unlike orb's encoding/wkt it
+// accepts EMPTY at any level, both MULTIPOINT forms, LINEARRING,
+// CIRCULARSTRING, and Z and M ordinates, which are dropped. An empty point
+// is returned as a point with NaN ordinates, see IsEmpty. Rings must be
+// closed and have at least four points, as in JTS and PostGIS.
+func ParseWKT(s string) (orb.Geometry, error) {
+	p := &wktParser{s: s}
+	g, err := p.geometry()
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpace(); p.pos != len(p.s) {
+		return nil, p.errorf("unexpected trailing text")
+	}
+	return g, nil
+}
+
+// wktParser is a small recursive-descent WKT reader, see ParseWKT
+type wktParser struct {
+	s   string
+	pos int
+}
+
+func (p *wktParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("predicates: wkt: %s at offset %d", fmt.Sprintf(format, args...), p.pos)
+}
+
+func (p *wktParser) skipSpace() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// word reads the next keyword in upper case
+func (p *wktParser) word() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		if (c < 'A' || c > 'Z') && (c < 'a' || c > 'z') {
+			break
+		}
+		p.pos++
+	}
+	return strings.ToUpper(p.s[start:p.pos])
+}
+
+// peek returns the next non-space byte without consuming it
+func (p *wktParser) peek() byte {
+	p.skipSpace()
+	if p.pos >= len(p.s) {
+		return 0
+	}
+	return p.s[p.pos]
+}
+
+func (p *wktParser) expect(c byte) error {
+	if p.peek() != c {
+		return p.errorf("expected %q", c)
+	}
+	p.pos++
+	return nil
+}
+
+// empty consumes an EMPTY keyword if one is next
+func (p *wktParser) empty() bool {
+	p.skipSpace()
+	if len(p.s)-p.pos >= 5 && strings.EqualFold(p.s[p.pos:p.pos+5], "EMPTY") {
+		p.pos += 5
+		return true
+	}
+	return false
+}
+
+// list parses "( item, item, ... )", calling item for each entry
+func (p *wktParser) list(item func() error) error {
+	if err := p.expect('('); err != nil {
+		return err
+	}
+	for {
+		if err := item(); err != nil {
+			return err
+		}
+		if p.peek() != ',' {
+			break
+		}
+		p.pos++
+	}
+	return p.expect(')')
+}
+
+func (p *wktParser) geometry() (orb.Geometry, error) {
+	tag := p.word()
+	// Dimension tags such as "POINT Z" or "POINT ZM"
+	if c := p.peek(); c == 'Z' || c == 'z' || c == 'M' || c == 'm' {
+		p.word()
+	}
+
+	switch tag {
+	case "POINT":
+		if p.empty() {
+			return orb.Point{math.NaN(), math.NaN()}, nil
+		}
+		var pt orb.Point
+		err := p.list(func() (err error) {
+			pt, err = p.point()
+			return err
+		})
+		return pt, err
+	case "LINESTRING":
+		return p.lineString()
+	case "LINEARRING":
+		return p.ring()
+	case "CIRCULARSTRING":
+		ls, err := p.lineString()
+		if err == nil && len(ls) > 0 && (len(ls) < 3 || len(ls)%2 == 0) {
+			err = p.errorf("CIRCULARSTRING needs an odd number of at least 3 points")
+		}
+		return CircularString{Points: ls}, err
+	case "POLYGON":
+		return p.polygon()
+	case "MULTIPOINT":
+		mp := orb.MultiPoint{}
+		if p.empty() {
+			return mp, nil
+		}
+		err := p.list(func() error {
+			pt, err := p.multiPointMember()
+			mp = append(mp, pt)
+			return err
+		})
+		return mp, err
+	case "MULTILINESTRING":
+		mls := orb.MultiLineString{}
+		if p.empty() {
+			return mls, nil
+		}
+		err := p.list(func() error {
+			ls, err := p.lineString()
+			mls = append(mls, ls)
+			return err
+		})
+		return mls, err
+	case "MULTIPOLYGON":
+		mp := orb.MultiPolygon{}
+		if p.empty() {
+			return mp, nil
+		}
+		err := p.list(func() error {
+			poly, err := p.polygon()
+			mp = append(mp, poly)
+			return err
+		})
+		return mp, err
+	case "GEOMETRYCOLLECTION":
+		c := orb.Collection{}
+		if p.empty() {
+			return c, nil
+		}
+		err := p.list(func() error {
+			g, err := p.geometry()
+			c = append(c, g)
+			return err
+		})
+		return c, err
+	case "":
+		return nil, p.errorf("expected geometry type")
+	}
+	return nil, p.errorf("unsupported geometry type %s", tag)
+}
+
+// multiPointMember parses "(x y)", "x y" or "EMPTY"
+func (p *wktParser) multiPointMember() (orb.Point, error) {
+	if p.empty() {
+		return orb.Point{math.NaN(), math.NaN()}, nil
+	}
+	if p.peek() != '(' {
+		return p.point()
+	}
+	var pt orb.Point
+	err := p.list(func() (err error) {
+		pt, err = p.point()
+		return err
+	})
+	return pt, err
+}
+
+func (p *wktParser) lineString() (orb.LineString, error) {
+	ls := orb.LineString{}
+	if p.empty() {
+		return ls, nil
+	}
+	err := p.list(func() error {
+		pt, err := p.point()
+		ls = append(ls, pt)
+		return err
+	})
+	return ls, err
+}
+
+func (p *wktParser) polygon() (orb.Polygon, error) {
+	poly := orb.Polygon{}
+	if p.empty() {
+		return poly, nil
+	}
+	err := p.list(func() error {
+		ls, err := p.ring()
+		poly = append(poly, orb.Ring(ls))
+		return err
+	})
+	return poly, err
+}
+
+// ring parses the points of a ring and checks that it is closed
+func (p *wktParser) ring() (orb.LineString, error) {
+	ls, err := p.lineString()
+	if err == nil && len(ls) > 0 && (len(ls) < 4 || ls[0] != ls[len(ls)-1]) {
+		err = p.errorf("ring must be closed and have at least 4 points")
+	}
+	return ls, err
+}
+
+// point parses a coordinate, keeping only x and y
+func (p *wktParser) point() (orb.Point, error) {
+	var pt orb.Point
+	for i := 0; ; i++ {
+		p.skipSpace()
+		start := p.pos
+		for p.pos < len(p.s) && strings.IndexByte("+-.0123456789eE", p.s[p.pos]) >= 0 {
+			p.pos++
+		}
+		if start == p.pos {
+			if i < 2 {
+				return pt, p.errorf("expected coordinate")
+			}
+			return pt, nil
+		}
+		v, err := strconv.ParseFloat(p.s[start:p.pos], 64)
+		if err != nil {
+			return pt, p.errorf("bad number %q", p.s[start:p.pos])
+		}
+		if i < 2 {
+			pt[i] = v
+		}
+	}
+}
+
+// FormatWKT writes g as WKT that ParseWKT reads back exactly. Coordinates
+// are written in the shortest form that parses to the same float64. Rings
+// and Bounds are areas in the predicates, so they are written as polygons.
+// Circles and custom geometry types have no WKT form and are reported as
+// ErrUnsupportedType.
+func FormatWKT(g orb.Geometry) (string, error) {
+	var sb strings.Builder
+	if err := writeWKT(&sb, g); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+func writeWKT(sb *strings.Builder, g orb.Geometry) error {
+	switch g := g.(type) {
+	case orb.Point:
+		if pointEmpty(g) {
+			sb.WriteString("POINT EMPTY")
+			return nil
+		}
+		sb.WriteString("POINT (")
+		writeCoord(sb, g)
+		sb.WriteByte(')')
+	case orb.MultiPoint:
+		sb.WriteString("MULTIPOINT ")
+		if len(g) == 0 {
+			sb.WriteString("EMPTY")
+			return nil
+		}
+		sb.WriteByte('(')
+		for i, p := range g {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			if pointEmpty(p) {
+				sb.WriteString("EMPTY")
+				continue
+			}
+			sb.WriteByte('(')
+			writeCoord(sb, p)
+			sb.WriteByte(')')
+		}
+		sb.WriteByte(')')
+	case orb.LineString:
+		sb.WriteString("LINESTRING ")
+		writeCoords(sb, g)
+	case CircularString:
+		sb.WriteString("CIRCULARSTRING ")
+		writeCoords(sb, g.Points)
+	case orb.MultiLineString:
+		sb.WriteString("MULTILINESTRING ")
+		if len(g) == 0 {
+			sb.WriteString("EMPTY")
+			return nil
+		}
+		sb.WriteByte('(')
+		for i, ls := range g {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			writeCoords(sb, ls)
+		}
+		sb.WriteByte(')')
+	case orb.Ring:
+		sb.WriteString("POLYGON ")
+		writeRings(sb, orb.Polygon{g})
+	case orb.Bound:
+		sb.WriteString("POLYGON ")
+		writeRings(sb, g.ToPolygon())
+	case orb.Polygon:
+		sb.WriteString("POLYGON ")
+		writeRings(sb, g)
+	case orb.MultiPolygon:
+		sb.WriteString("MULTIPOLYGON ")
+		if len(g) == 0 {
+			sb.WriteString("EMPTY")
+			return nil
+		}
+		sb.WriteByte('(')
+		for i, poly := range g {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			writeRings(sb, poly)
+		}
+		sb.WriteByte(')')
+	case orb.Collection:
+		sb.WriteString("GEOMETRYCOLLECTION ")
+		if len(g) == 0 {
+			sb.WriteString("EMPTY")
+			return nil
+		}
+		sb.WriteByte('(')
+		for i, part := range g {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			if err := writeWKT(sb, part); err != nil {
+				return err
+			}
+		}
+		sb.WriteByte(')')
+	default:
+		return fmt.Errorf("%w: %T has no WKT form", ErrUnsupportedType, g)
+	}
+	return nil
+}
+
+// writeCoords writes "(x y, x y, ...)", or EMPTY for no points
+func writeCoords(sb *strings.Builder, points []orb.Point) {
+	if len(points) == 0 {
+		sb.WriteString("EMPTY")
+		return
+	}
+	sb.WriteByte('(')
+	for i, p := range points {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		writeCoord(sb, p)
+	}
+	sb.WriteByte(')')
+}
+
+func writeRings(sb *strings.Builder, poly orb.Polygon) {
+	if len(poly) == 0 {
+		sb.WriteString("EMPTY")
+		return
+	}
+	sb.WriteByte('(')
+	for i, ring := range poly {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		writeCoords(sb, ring)
+	}
+	sb.WriteByte(')')
+}
+
+func writeCoord(sb *strings.Builder, p orb.Point) {
+	sb.WriteString(strconv.FormatFloat(p[0], 'g', -1, 64))
+	sb.WriteByte(' ')
+	sb.WriteString(strconv.FormatFloat(p[1], 'g', -1, 64))
+}